@@ -4,7 +4,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/princecee/go_chat/app/api/auth"
+	"github.com/princecee/go_chat/app/api/blocks"
+	"github.com/princecee/go_chat/app/api/dms"
+	"github.com/princecee/go_chat/app/api/hooks"
+	"github.com/princecee/go_chat/app/api/invites"
 	"github.com/princecee/go_chat/app/api/rooms"
+	"github.com/princecee/go_chat/app/api/saved"
+	"github.com/princecee/go_chat/app/api/search"
 	"github.com/princecee/go_chat/app/api/users"
 	"github.com/princecee/go_chat/internal/services"
 )
@@ -16,4 +22,10 @@ func SetupAPI(r *gin.Engine, conn *pgxpool.Pool) {
 	auth.Routes(v1.Group("/auth"), services)
 	rooms.Routes(v1.Group("/rooms"), services)
 	users.Routes(v1.Group("/users"), services)
+	search.Routes(v1.Group("/search"), services)
+	hooks.Routes(v1.Group("/hooks"), services)
+	dms.Routes(v1.Group("/dms"), services)
+	blocks.Routes(v1.Group("/blocks"), services)
+	saved.Routes(v1.Group("/saved"), services)
+	invites.Routes(v1.Group("/invites"), services)
 }
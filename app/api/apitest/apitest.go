@@ -0,0 +1,118 @@
+// Package apitest holds the fixture shared by the API handler test suites:
+// a test server backed by the database named in the repository's .env, and
+// helpers to sign users up and call it.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite is embedded by handler test suites. They call Serve from SetupSuite
+// and Close from TearDownSuite.
+type Suite struct {
+	suite.Suite
+	Services services.Services
+	Server   *httptest.Server
+}
+
+// Serve starts a test server with the handlers registered by routes. The
+// .env file is looked up relative to the test package, which lives in
+// app/api.
+func (s *Suite) Serve(routes func(r *gin.Engine, services services.Services)) {
+	gin.SetMode(gin.TestMode)
+
+	if err := godotenv.Load("../../../.env"); err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(context.Background(), os.Getenv("DSN"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s.Services = services.New(conn)
+
+	r := gin.New()
+	routes(r, s.Services)
+
+	s.Server = httptest.NewServer(r.Handler())
+}
+
+// Close empties tables, in order, and shuts the server and database down.
+func (s *Suite) Close(tables ...string) {
+	db := s.Services.GetDB()
+	defer db.Close()
+	defer s.Server.Close()
+
+	queries := []string{}
+	for _, table := range tables {
+		queries = append(queries, "DELETE FROM "+table)
+	}
+
+	_, err := db.Exec(context.Background(), strings.Join(queries, ";"))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			panic(err)
+		}
+	}
+}
+
+// SignUp registers a user and returns them along with their access token.
+func (s *Suite) SignUp(dto map[string]string) (models.User, string) {
+	client := s.Server.Client()
+
+	signupJson, err := json.Marshal(dto)
+	s.NoError(err)
+
+	resp, err := client.Post(s.Server.URL+"/api/v1/auth/sign-up", "application/json", bytes.NewBuffer(signupJson))
+	s.NoError(err)
+	defer resp.Body.Close()
+
+	var data utils.Response[map[string]models.User]
+	err = utils.ReadJSON(resp.Body, &data)
+	s.NoError(err)
+
+	return data.Data["user"], data.Meta.AccessToken
+}
+
+// Request sends body, if any, as JSON to path and decodes the response into
+// v, if given. It returns the response status code.
+func (s *Suite) Request(method, path, accessToken string, body any, v any) int {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		s.NoError(err)
+		reader = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, s.Server.URL+path, reader)
+	s.NoError(err)
+
+	req.Header.Set("Authorization", accessToken)
+	resp, err := s.Server.Client().Do(req)
+	s.NoError(err)
+	defer resp.Body.Close()
+
+	if v != nil {
+		s.NoError(utils.ReadJSON(resp.Body, v))
+	}
+	return resp.StatusCode
+}
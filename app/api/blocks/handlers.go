@@ -0,0 +1,137 @@
+package blocks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type blockHandler struct {
+	services services.Services
+}
+
+func (h *blockHandler) getBlockedUsers(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	users, err := h.services.GetBlockService().GetBlockedUsers(user.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "fetched blocked users successfully",
+		Data:    map[string]any{"users": users},
+	})
+	return nil
+}
+
+func (h *blockHandler) blockUser(c *gin.Context) error {
+	userId := c.Params.ByName("userId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    utils.ErrNotFound.Error(),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	_, err := h.services.GetUserService().GetUser(repositories.GetUserParams{ID: userId}, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	user := val.(*models.User)
+	err = h.services.GetBlockService().BlockUser(user.ID, userId, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrBlockSelf):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "blocked user successfully",
+	})
+	return nil
+}
+
+func (h *blockHandler) unblockUser(c *gin.Context) error {
+	userId := c.Params.ByName("userId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    utils.ErrNotFound.Error(),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	user := val.(*models.User)
+	err := h.services.GetBlockService().UnblockUser(user.ID, userId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "unblocked user successfully",
+	})
+	return nil
+}
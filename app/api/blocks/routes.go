@@ -0,0 +1,17 @@
+package blocks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := blockHandler{services: s}
+
+	r.Use(middlewares.Authenticator(s))
+
+	r.GET("/", middlewares.ErrorHandler(h.getBlockedUsers))
+	r.POST("/:userId", middlewares.ErrorHandler(h.blockUser))
+	r.DELETE("/:userId", middlewares.ErrorHandler(h.unblockUser))
+}
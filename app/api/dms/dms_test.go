@@ -0,0 +1,231 @@
+package dms
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/app/api/apitest"
+	"github.com/princecee/go_chat/app/api/auth"
+	"github.com/princecee/go_chat/app/api/blocks"
+	"github.com/princecee/go_chat/app/api/rooms"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type dmsTestSuite struct {
+	apitest.Suite
+}
+
+func (s *dmsTestSuite) SetupSuite() {
+	s.Serve(func(r *gin.Engine, services services.Services) {
+		auth.Routes(r.Group("/api/v1/auth"), services)
+		rooms.Routes(r.Group("/api/v1/rooms"), services)
+		blocks.Routes(r.Group("/api/v1/blocks"), services)
+		Routes(r.Group("/api/v1/dms"), services)
+	})
+}
+
+func (s *dmsTestSuite) TearDownSuite() {
+	s.Close("auths", "user_blocks", "room_messages", "room_members", "rooms", "users")
+}
+
+func (s *dmsTestSuite) TestDirectMessagesHandler() {
+	ada, adaToken := s.SignUp(map[string]string{
+		"first_name": "Ada",
+		"last_name":  "Obi",
+		"email":      "ada@gmail.com",
+		"password":   "password",
+	})
+	bola, bolaToken := s.SignUp(map[string]string{
+		"first_name": "Bola",
+		"last_name":  "Ade",
+		"email":      "bola@gmail.com",
+		"password":   "password",
+	})
+	_, outsiderToken := s.SignUp(map[string]string{
+		"first_name": "Chidi",
+		"last_name":  "Eze",
+		"email":      "chidi@gmail.com",
+		"password":   "password",
+	})
+
+	var dm models.DirectMessage
+	s.Run("open direct message", func() {
+		var data utils.Response[map[string]models.DirectMessage]
+		status := s.Request("POST", "/api/v1/dms", adaToken, map[string]string{"user_id": bola.ID}, &data)
+		s.Equal(http.StatusOK, status)
+
+		dm = data.Data["direct_message"]
+		s.Equal(models.RoomKindDirect, dm.Room.Kind)
+		s.Len(dm.Participants, 2)
+	})
+
+	s.Run("open is idempotent", func() {
+		var data utils.Response[map[string]models.DirectMessage]
+		status := s.Request("POST", "/api/v1/dms", bolaToken, map[string]string{"user_id": ada.ID}, &data)
+		s.Equal(http.StatusOK, status)
+		s.Equal(dm.Room.ID, data.Data["direct_message"].Room.ID)
+
+		var list utils.Response[map[string][]models.DirectMessage]
+		status = s.Request("GET", "/api/v1/dms", adaToken, nil, &list)
+		s.Equal(http.StatusOK, status)
+		s.Len(list.Data["direct_messages"], 1)
+	})
+
+	s.Run("invalid participants", func() {
+		status := s.Request("POST", "/api/v1/dms", adaToken, map[string]string{"user_id": ada.ID}, nil)
+		s.Equal(http.StatusBadRequest, status)
+
+		status = s.Request("POST", "/api/v1/dms", adaToken, map[string]string{"user_id": "5c7c9a7e-5a77-4f3e-8a55-0a8f0b0c9b11"}, nil)
+		s.Equal(http.StatusNotFound, status)
+	})
+
+	s.Run("hidden from others", func() {
+		var rooms utils.Response[map[string][]models.Room]
+		status := s.Request("GET", "/api/v1/rooms", outsiderToken, nil, &rooms)
+		s.Equal(http.StatusOK, status)
+		for _, room := range rooms.Data["rooms"] {
+			s.NotEqual(dm.Room.ID, room.ID)
+		}
+
+		var list utils.Response[map[string][]models.DirectMessage]
+		status = s.Request("GET", "/api/v1/dms", outsiderToken, nil, &list)
+		s.Equal(http.StatusOK, status)
+		s.Empty(list.Data["direct_messages"])
+
+		status = s.Request("GET", fmt.Sprintf("/api/v1/rooms/%s", dm.Room.ID), outsiderToken, nil, nil)
+		s.Equal(http.StatusUnauthorized, status)
+
+		status = s.Request("POST", fmt.Sprintf("/api/v1/rooms/%s/join", dm.Room.ID), outsiderToken, nil, nil)
+		s.Equal(http.StatusForbidden, status)
+	})
+
+	s.Run("blocked users cannot message", func() {
+		status := s.Request("POST", "/api/v1/blocks/"+ada.ID, bolaToken, nil, nil)
+		s.Equal(http.StatusOK, status)
+
+		var blocked utils.Response[map[string][]models.User]
+		status = s.Request("GET", "/api/v1/blocks", bolaToken, nil, &blocked)
+		s.Equal(http.StatusOK, status)
+		s.Len(blocked.Data["users"], 1)
+
+		status = s.Request("POST", "/api/v1/dms", adaToken, map[string]string{"user_id": bola.ID}, nil)
+		s.Equal(http.StatusForbidden, status)
+
+		member, err := s.Services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: ada.ID,
+			RoomID: dm.Room.ID,
+		}, nil)
+		s.Require().NoError(err)
+		err = s.Services.GetRoomService().CreateMessage(&models.RoomMessage{
+			RoomID:       dm.Room.ID,
+			UserID:       ada.ID,
+			RoomMemberID: member.ID,
+			Content:      "hello?",
+		}, nil)
+		s.ErrorIs(err, services.ErrBlocked)
+
+		status = s.Request("DELETE", "/api/v1/blocks/"+ada.ID, bolaToken, nil, nil)
+		s.Equal(http.StatusOK, status)
+
+		status = s.Request("POST", "/api/v1/dms", adaToken, map[string]string{"user_id": bola.ID}, nil)
+		s.Equal(http.StatusOK, status)
+	})
+}
+
+func (s *dmsTestSuite) TestGroupMessagesHandler() {
+	ids := []string{}
+	tokens := []string{}
+	for _, name := range []string{"Dayo", "Efe", "Funmi", "Gozie"} {
+		user, token := s.SignUp(map[string]string{
+			"first_name": name,
+			"last_name":  "Test",
+			"email":      strings.ToLower(name) + "@gmail.com",
+			"password":   "password",
+		})
+		ids = append(ids, user.ID)
+		tokens = append(tokens, token)
+	}
+
+	var group models.DirectMessage
+	s.Run("open group message", func() {
+		var data utils.Response[map[string]models.DirectMessage]
+		status := s.Request("POST", "/api/v1/dms", tokens[0], map[string][]string{"user_ids": ids[1:3]}, &data)
+		s.Equal(http.StatusOK, status)
+
+		group = data.Data["direct_message"]
+		s.Equal(models.RoomKindGroup, group.Room.Kind)
+		s.Len(group.Participants, 3)
+
+		status = s.Request("POST", "/api/v1/dms", tokens[2], map[string][]string{"user_ids": {ids[1], ids[0]}}, &data)
+		s.Equal(http.StatusOK, status)
+		s.Equal(group.Room.ID, data.Data["direct_message"].Room.ID)
+
+		status = s.Request("POST", "/api/v1/dms", tokens[0], map[string][]string{"user_ids": ids[1:2]}, nil)
+		s.Equal(http.StatusBadRequest, status)
+	})
+
+	s.Run("add participants", func() {
+		path := fmt.Sprintf("/api/v1/dms/%s/participants", group.Room.ID)
+
+		status := s.Request("POST", path, tokens[3], map[string][]string{"user_ids": ids[3:]}, nil)
+		s.Equal(http.StatusUnauthorized, status)
+
+		var data utils.Response[map[string]models.DirectMessage]
+		status = s.Request("POST", path, tokens[1], map[string][]string{"user_ids": ids[3:]}, &data)
+		s.Equal(http.StatusOK, status)
+		s.Equal(models.RoomKindGroup, data.Data["direct_message"].Room.Kind)
+		s.Len(data.Data["direct_message"].Participants, 4)
+
+		// the original participants can start a new group without the
+		// added one, which cannot then grow into the existing group
+		status = s.Request("POST", "/api/v1/dms", tokens[0], map[string][]string{"user_ids": ids[1:3]}, &data)
+		s.Equal(http.StatusOK, status)
+		s.NotEqual(group.Room.ID, data.Data["direct_message"].Room.ID)
+
+		status = s.Request("POST", fmt.Sprintf("/api/v1/dms/%s/participants", data.Data["direct_message"].Room.ID), tokens[0], map[string][]string{"user_ids": ids[3:]}, nil)
+		s.Equal(http.StatusConflict, status)
+	})
+
+	s.Run("convert to room", func() {
+		more := []string{}
+		for i := range 6 {
+			user := &models.User{FirstName: "Extra", LastName: "Test", Email: fmt.Sprintf("extra%d@gmail.com", i)}
+			s.Require().NoError(s.Services.GetUserService().CreateUser(user, nil))
+			more = append(more, user.ID)
+		}
+
+		var data utils.Response[map[string]models.DirectMessage]
+		status := s.Request("POST", fmt.Sprintf("/api/v1/dms/%s/participants", group.Room.ID), tokens[3], map[string][]string{"user_ids": more}, &data)
+		s.Equal(http.StatusOK, status)
+
+		room := data.Data["direct_message"].Room
+		s.Equal(models.RoomKindRoom, room.Kind)
+		s.Equal(models.RoomVisibilityPrivate, room.Visibility)
+		s.Contains(room.Name, "Dayo")
+		s.Len(data.Data["direct_message"].Participants, 10)
+
+		stored, err := s.Services.GetRoomService().GetRoom(room.ID, nil)
+		s.Require().NoError(err)
+		s.Equal(models.RoomVisibilityPrivate, stored.Visibility)
+
+		var rooms utils.Response[map[string][]models.Room]
+		status = s.Request("GET", "/api/v1/rooms", tokens[0], nil, &rooms)
+		s.Equal(http.StatusOK, status)
+		found := false
+		for _, r := range rooms.Data["rooms"] {
+			found = found || r.ID == room.ID
+		}
+		s.True(found)
+	})
+}
+
+func TestDirectMessagesHandler(t *testing.T) {
+	suite.Run(t, new(dmsTestSuite))
+}
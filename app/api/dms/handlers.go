@@ -0,0 +1,258 @@
+package dms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type dmHandler struct {
+	services services.Services
+}
+
+func (h *dmHandler) getDirectMessages(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	dms, err := h.services.GetDirectMessageService().GetDirectMessages(user.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "fetched direct messages successfully",
+		Data:    map[string]any{"direct_messages": dms},
+	})
+	return nil
+}
+
+type OpenDirectMessageDto struct {
+	UserID  string   `json:"user_id"`
+	UserIDs []string `json:"user_ids"`
+}
+
+// openDirectMessage returns the caller's direct message with another user,
+// or group message when several are given, creating it on first use. The
+// other participants are told about new ones so their clients can start
+// listening to the room.
+func (h *dmHandler) openDirectMessage(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var openDto OpenDirectMessageDto
+	err := c.BindJSON(&openDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	userIds := openDto.UserIDs
+	if len(userIds) == 0 {
+		userIds = []string{openDto.UserID}
+	}
+	if err := validateUserIDs(userIds); err != nil {
+		return err
+	}
+
+	user := val.(*models.User)
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	var dm *models.DirectMessage
+	var created bool
+	if len(openDto.UserIDs) == 0 {
+		dm, created, err = h.services.GetDirectMessageService().OpenDirectMessage(user.ID, openDto.UserID, tx)
+	} else {
+		dm, created, err = h.services.GetDirectMessageService().OpenGroupMessage(user.ID, openDto.UserIDs, tx)
+	}
+	if err != nil {
+		return participantsError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if created {
+		for _, participant := range dm.Participants {
+			if participant.ID == user.ID {
+				continue
+			}
+			h.services.GetEventService().Publish(&models.Event{
+				Type:   models.EventRoomInvited,
+				RoomID: dm.Room.ID,
+				UserID: participant.ID,
+				Data:   dm,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "opened direct message successfully",
+		Data:    map[string]any{"direct_message": dm},
+	})
+	return nil
+}
+
+type AddParticipantsDto struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// addParticipants adds users to a group message the caller is in. Groups
+// that grow too large become rooms, which is announced to everyone in them.
+func (h *dmHandler) addParticipants(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var addDto AddParticipantsDto
+	err := c.BindJSON(&addDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if err := validateUserIDs(addDto.UserIDs); err != nil {
+		return err
+	}
+
+	user := val.(*models.User)
+
+	_, err = h.services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: user.ID,
+		RoomID: roomId,
+	}, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    utils.ErrUnauthorized.Error(),
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	dm, converted, err := h.services.GetDirectMessageService().AddParticipants(roomId, user.ID, addDto.UserIDs, tx)
+	if err != nil {
+		return participantsError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	for _, id := range addDto.UserIDs {
+		h.services.GetEventService().Publish(&models.Event{
+			Type:   models.EventRoomInvited,
+			RoomID: roomId,
+			UserID: id,
+			Data:   dm,
+		})
+	}
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventRoomUpdated,
+		RoomID: roomId,
+		Data:   dm,
+	})
+
+	message := "added participants successfully"
+	if converted {
+		message = "added participants and converted to room successfully"
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: message,
+		Data:    map[string]any{"direct_message": dm},
+	})
+	return nil
+}
+
+func validateUserIDs(userIds []string) error {
+	for _, id := range userIds {
+		if _, err := uuid.Parse(id); err != nil {
+			return &utils.ServerError{
+				Err:        err,
+				Message:    "invalid user id",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+// participantsError maps errors opening or adding to conversations to
+// responses.
+func participantsError(err error) error {
+	se := utils.ServerError{Err: err, Message: err.Error()}
+	switch {
+	case errors.Is(err, services.ErrDirectMessageSelf),
+		errors.Is(err, services.ErrTooFewParticipants),
+		errors.Is(err, services.ErrTooManyParticipants),
+		errors.Is(err, services.ErrNotGroupMessage),
+		errors.Is(err, services.ErrNoParticipants):
+		se.StatusCode = http.StatusBadRequest
+	case errors.Is(err, services.ErrBlocked):
+		se.StatusCode = http.StatusForbidden
+	case errors.Is(err, services.ErrConversationExists):
+		se.StatusCode = http.StatusConflict
+	case errors.Is(err, pgx.ErrNoRows):
+		se.Message = utils.ErrNotFound.Error()
+		se.StatusCode = http.StatusNotFound
+	default:
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
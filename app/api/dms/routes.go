@@ -0,0 +1,17 @@
+package dms
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := dmHandler{services: s}
+
+	r.Use(middlewares.Authenticator(s))
+
+	r.GET("/", middlewares.ErrorHandler(h.getDirectMessages))
+	r.POST("/", middlewares.ErrorHandler(h.openDirectMessage))
+	r.POST("/:roomId/participants", middlewares.ErrorHandler(h.addParticipants))
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/internal/slack"
+	"github.com/princecee/go_chat/utils"
+)
+
+const maxPayloadSize = 1 << 20
+
+type hooksHandler struct {
+	services services.Services
+}
+
+// IncomingWebhookDto accepts both our own payload and Slack's, where the
+// message is in "text".
+type IncomingWebhookDto struct {
+	Content string `json:"content"`
+	Text    string `json:"text"`
+}
+
+func (h *hooksHandler) postMessage(c *gin.Context) error {
+	token := c.Params.ByName("token")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPayloadSize)
+
+	var dto IncomingWebhookDto
+	var err error
+	if c.ContentType() == "application/x-www-form-urlencoded" {
+		// Slack clients may send the JSON payload as a form field
+		err = json.Unmarshal([]byte(c.PostForm("payload")), &dto)
+	} else {
+		err = c.BindJSON(&dto)
+	}
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    "invalid payload",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	content := dto.Content
+	if content == "" {
+		content = slackToMarkdown(dto.Text)
+	}
+
+	tx, _ := h.services.GetDB().Begin(c.Request.Context())
+	defer tx.Rollback(c.Request.Context())
+
+	message, err := h.services.GetIncomingWebhookService().PostMessage(token, content, tx)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		case errors.Is(err, services.ErrEmptyMessage), errors.Is(err, services.ErrMessageTooLong):
+			se.StatusCode = http.StatusBadRequest
+		case errors.Is(err, services.ErrMuted):
+			se.StatusCode = http.StatusForbidden
+		case errors.Is(err, services.ErrMessageRejected):
+			se.StatusCode = http.StatusUnprocessableEntity
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	err = tx.Commit(c.Request.Context())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.services.GetUnfurlService().EnqueueMessage(message)
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageCreated,
+		RoomID: message.RoomID,
+		Data:   message,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message posted successfully",
+		Data:    map[string]*models.RoomMessage{"message": message},
+	})
+	return nil
+}
+
+// slackToMarkdown converts the parts of Slack's message formatting that
+// would otherwise show up literally. Mentions are left alone since
+// integrations have no Slack workspace to resolve them against.
+func slackToMarkdown(text string) string {
+	return slack.ToMarkdown(text, nil)
+}
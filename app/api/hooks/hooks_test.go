@@ -0,0 +1,27 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Build passed", "Build passed"},
+		{"labelled link", "Deploy <https://ci.example.com/1?a=1&amp;b=2|#1> done", "Deploy [#1](https://ci.example.com/1?a=1&b=2) done"},
+		{"bare link", "see <https://example.com>", "see https://example.com"},
+		{"escaped", "a &lt; b &amp;&amp; c &gt; d", "a < b && c > d"},
+		{"not a link", "<!channel> heads up", "<!channel> heads up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, slackToMarkdown(tt.text))
+		})
+	}
+}
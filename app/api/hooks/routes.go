@@ -0,0 +1,15 @@
+package hooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+// Routes registers the endpoints external systems call. They authenticate
+// with the secret token in the URL rather than a user's access token.
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := hooksHandler{services: s}
+
+	r.POST("/:token", middlewares.ErrorHandler(h.postMessage))
+}
@@ -0,0 +1,76 @@
+package invites
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type invitesHandler struct {
+	services services.Services
+}
+
+// acceptInvite joins the room an invite is for. It is the only way into a
+// private room.
+func (h *invitesHandler) acceptInvite(c *gin.Context) error {
+	code := c.Params.ByName("code")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	member, err := h.services.GetRoomInviteService().AcceptInvite(code, user.ID, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		case errors.Is(err, services.ErrInviteExpired),
+			errors.Is(err, services.ErrInviteRevoked),
+			errors.Is(err, services.ErrInviteUsedUp):
+			se.StatusCode = http.StatusGone
+		case errors.Is(err, services.ErrAlreadyMember):
+			se.StatusCode = http.StatusNotAcceptable
+		case errors.Is(err, services.ErrMaxMembersReached):
+			se.StatusCode = http.StatusUnauthorized
+		case errors.Is(err, services.ErrBanned):
+			se.StatusCode = http.StatusForbidden
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMemberJoined,
+		RoomID: member.RoomID,
+		Data:   member,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "joined room successfully",
+		Data:    map[string]*models.RoomMember{"member": member},
+	})
+	return nil
+}
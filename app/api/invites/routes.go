@@ -0,0 +1,17 @@
+package invites
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+// Routes registers the endpoint invite links point to. Invites are created
+// and revoked through the room they belong to.
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := invitesHandler{services: s}
+
+	r.Use(middlewares.Authenticator(s))
+
+	r.POST("/:code", middlewares.ErrorHandler(h.acceptInvite))
+}
@@ -0,0 +1,287 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/internal/storage"
+	"github.com/princecee/go_chat/utils"
+)
+
+func (h *roomHandler) uploadAttachment(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	_, member, err := h.authorize(roomId, user, services.PermissionSendMessage)
+	if err != nil {
+		return err
+	}
+
+	// leave room for the multipart framing and caption around the file
+	maxSize := services.MaxAttachmentSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &utils.ServerError{
+				Err:        err,
+				Message:    services.ErrAttachmentTooLarge.Error(),
+				StatusCode: http.StatusRequestEntityTooLarge,
+			}
+		}
+
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	defer file.Close()
+
+	message := &models.RoomMessage{
+		RoomID:       roomId,
+		UserID:       user.ID,
+		RoomMemberID: member.ID,
+		Content:      c.PostForm("content"),
+	}
+	if ttl, err := strconv.Atoi(c.PostForm("ttl")); err == nil && ttl > 0 {
+		expiresAt := time.Now().Add(time.Duration(ttl) * time.Second)
+		message.ExpiresAt = &expiresAt
+	}
+	attachment := &models.Attachment{
+		RoomID:   roomId,
+		UserID:   user.ID,
+		FileName: filepath.Base(fileHeader.Filename),
+		Size:     fileHeader.Size,
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	err = h.services.GetRoomService().CreateMessage(message, tx)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrMessageTooLong):
+			se.StatusCode = http.StatusBadRequest
+		case errors.Is(err, services.ErrBlocked),
+			errors.Is(err, services.ErrMuted):
+			se.StatusCode = http.StatusForbidden
+		case errors.Is(err, services.ErrMessageRejected):
+			se.StatusCode = http.StatusUnprocessableEntity
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	attachment.RoomMessageID = message.ID
+	err = h.services.GetAttachmentService().CreateAttachment(attachment, file, tx)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrAttachmentTooLarge):
+			se.StatusCode = http.StatusRequestEntityTooLarge
+		case errors.Is(err, services.ErrEmptyAttachment):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.services.GetAttachmentService().EnqueueProcessing(attachment.ID)
+	h.services.GetUnfurlService().EnqueueMessage(message)
+
+	message.Attachments = []*models.Attachment{attachment}
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageCreated,
+		RoomID: roomId,
+		Data:   message,
+	})
+	h.services.GetMessageDraftService().ClearSentDraft(message, c.GetHeader(sessionHeader))
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "attachment uploaded successfully",
+		Data:    map[string]*models.RoomMessage{"message": message},
+	})
+	return nil
+}
+
+func (h *roomHandler) downloadAttachment(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	attachmentId := c.Params.ByName("attachmentId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	attachmentService := h.services.GetAttachmentService()
+	attachment, err := attachmentService.GetAttachment(attachmentId, nil)
+	if err == nil && attachment.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	body, err := attachmentService.OpenAttachment(attachment)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+	defer body.Close()
+
+	// only images are rendered inline, everything else is downloaded
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+	return nil
+}
+
+func (h *roomHandler) downloadThumbnail(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	attachmentId := c.Params.ByName("attachmentId")
+	label := c.Params.ByName("label")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	attachmentService := h.services.GetAttachmentService()
+	attachment, err := attachmentService.GetAttachment(attachmentId, nil)
+	if err == nil && attachment.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	var thumbnail *models.AttachmentThumbnail
+	for _, t := range attachment.Thumbnails {
+		if t.Label == label {
+			thumbnail = t
+			break
+		}
+	}
+	if thumbnail == nil {
+		return &utils.ServerError{
+			Err:        utils.ErrNotFound,
+			Message:    utils.ErrNotFound.Error(),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	body, err := attachmentService.OpenThumbnail(thumbnail)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, thumbnail.Size, thumbnail.ContentType, body, map[string]string{
+		"Content-Disposition":    "inline",
+		"X-Content-Type-Options": "nosniff",
+	})
+	return nil
+}
@@ -0,0 +1,40 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+// authorize returns the room and the user's membership of it if their role
+// holds permission there, or an error response otherwise.
+func (h *roomHandler) authorize(roomId string, user *models.User, permission services.Permission) (*models.Room, *models.RoomMember, error) {
+	room, member, err := h.services.GetAuthorizationService().Authorize(roomId, user.ID, permission, nil)
+	if err != nil {
+		return nil, nil, authorizationError(err)
+	}
+	return room, member, nil
+}
+
+func authorizationError(err error) error {
+	se := utils.ServerError{Err: err}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		se.Message = utils.ErrNotFound.Error()
+		se.StatusCode = http.StatusNotFound
+	case errors.Is(err, services.ErrPermissionDenied):
+		se.Message = utils.ErrUnauthorized.Error()
+		se.StatusCode = http.StatusUnauthorized
+	case errors.Is(err, services.ErrInvalidRole):
+		se.Message = err.Error()
+		se.StatusCode = http.StatusBadRequest
+	default:
+		se.Message = err.Error()
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
@@ -0,0 +1,115 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+// sessionHeader names the websocket session a request comes from, the same
+// value the client passed as session_id when it connected.
+const sessionHeader = "X-Session-ID"
+
+type SaveDraftDto struct {
+	Content string `json:"content"`
+}
+
+// getDraft returns the user's draft in the room, or null when they have
+// none.
+func (h *roomHandler) getDraft(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	draft, err := h.services.GetMessageDraftService().GetDraft(user.ID, roomId, nil)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "draft fetched successfully",
+		Data:    map[string]*models.MessageDraft{"draft": draft},
+	})
+	return nil
+}
+
+// saveDraft replaces the user's draft in the room and pushes it to their
+// other sessions. Saving empty content clears the draft.
+func (h *roomHandler) saveDraft(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var saveDraftDto SaveDraftDto
+	err := c.BindJSON(&saveDraftDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionSendMessage); err != nil {
+		return err
+	}
+
+	draft := &models.MessageDraft{
+		UserID:  user.ID,
+		RoomID:  roomId,
+		Content: saveDraftDto.Content,
+	}
+	err = h.services.GetMessageDraftService().SaveDraft(draft, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:        models.EventDraftUpdated,
+		RoomID:      roomId,
+		UserID:      user.ID,
+		SkipSession: c.GetHeader(sessionHeader),
+		Data:        draft,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "draft saved successfully",
+		Data:    map[string]*models.MessageDraft{"draft": draft},
+	})
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -29,27 +30,9 @@ func (h *roomHandler) getRoom(c *gin.Context) error {
 	}
 
 	user := val.(*models.User)
-
-	roomService := h.services.GetRoomService()
-	_, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-		UserID: user.ID,
-		RoomID: roomId,
-	}, nil)
+	room, _, err := h.authorize(roomId, user, services.PermissionReadRoom)
 	if err != nil {
-		return &utils.ServerError{
-			Err:        err,
-			Message:    utils.ErrUnauthorized.Error(),
-			StatusCode: http.StatusUnauthorized,
-		}
-	}
-
-	room, err := roomService.GetRoom(roomId, nil)
-	if err != nil {
-		return &utils.ServerError{
-			Err:        err,
-			Message:    err.Error(),
-			StatusCode: http.StatusInternalServerError,
-		}
+		return err
 	}
 
 	c.JSON(http.StatusOK, utils.ResponseGeneric{
@@ -60,8 +43,22 @@ func (h *roomHandler) getRoom(c *gin.Context) error {
 	return nil
 }
 
+// getRooms lists the rooms the user can find, which leaves out the private
+// rooms they are not in.
 func (h *roomHandler) getRooms(c *gin.Context) error {
-	rooms, err := h.services.GetRoomService().GetRooms(nil, nil)
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	rooms, err := h.services.GetRoomService().GetRooms(repositories.GetRoomsParams{
+		ViewerID: &user.ID,
+	}, nil)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			c.JSON(http.StatusOK, utils.ResponseGeneric{
@@ -92,6 +89,8 @@ type CreateRoomDto struct {
 	Name        string `json:"name" validate:"required,alphanumeric"`
 	Description string `json:"description" validate:"required,alphanumeric"`
 	MaxMembers  int    `json:"max_members" validate:"required,gt=0"`
+	MessageTTL  int    `json:"message_ttl" validate:"gte=0"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=public restricted private"`
 }
 
 func (h *roomHandler) createRoom(c *gin.Context) error {
@@ -120,6 +119,8 @@ func (h *roomHandler) createRoom(c *gin.Context) error {
 		Description: createRoomDto.Description,
 		Name:        createRoomDto.Name,
 		MaxMembers:  createRoomDto.MaxMembers,
+		MessageTTL:  createRoomDto.MessageTTL,
+		Visibility:  createRoomDto.Visibility,
 	}
 
 	tx, _ := h.services.GetDB().Begin(context.Background())
@@ -127,11 +128,14 @@ func (h *roomHandler) createRoom(c *gin.Context) error {
 
 	err = h.services.GetRoomService().CreateRoom(room, tx)
 	if err != nil {
-		return &utils.ServerError{
-			Err:        err,
-			Message:    err.Error(),
-			StatusCode: http.StatusInternalServerError,
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidMessageTTL), errors.Is(err, services.ErrInvalidVisibility):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
 		}
+		return &se
 	}
 
 	err = tx.Commit(context.Background())
@@ -166,37 +170,20 @@ func (h *roomHandler) deleteRoom(c *gin.Context) error {
 
 	user := val.(*models.User)
 
-	roomService := h.services.GetRoomService()
-	_, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-		UserID: user.ID,
-		RoomID: roomId,
-	}, nil)
+	room, _, err := h.authorize(roomId, user, services.PermissionDeleteRoom)
 	if err != nil {
-		return &utils.ServerError{
-			Err:        err,
-			Message:    utils.ErrUnauthorized.Error(),
-			StatusCode: http.StatusUnauthorized,
-		}
+		return err
 	}
 
-	room, err := roomService.GetRoom(roomId, nil)
-	if err != nil {
+	if room.Kind != models.RoomKindRoom {
 		return &utils.ServerError{
-			Message:    err.Error(),
-			Err:        err,
-			StatusCode: http.StatusInternalServerError,
-		}
-	}
-
-	if room.CreatedBy != user.ID {
-		return &utils.ServerError{
-			Message:    utils.ErrUnauthorized.Error(),
-			Err:        utils.ErrUnauthorized,
-			StatusCode: http.StatusUnauthorized,
+			Message:    services.ErrDirectMessageRoom.Error(),
+			Err:        services.ErrDirectMessageRoom,
+			StatusCode: http.StatusBadRequest,
 		}
 	}
 
-	err = roomService.DeleteRoom(roomId, nil)
+	err = h.services.GetRoomService().DeleteRoom(roomId, nil)
 	if err != nil {
 		return &utils.ServerError{
 			Message:    err.Error(),
@@ -217,6 +204,8 @@ type UpdateRoomDto struct {
 	Name        *string `json:"name,omitempty" validate:"alphanumeric"`
 	Description *string `json:"description,omitempty" validate:"alphanumeric"`
 	MaxMembers  *int    `json:"max_members,omitempty" validate:"gt=0"`
+	MessageTTL  *int    `json:"message_ttl,omitempty" validate:"gte=0"`
+	Visibility  *string `json:"visibility,omitempty" validate:"oneof=public restricted private"`
 }
 
 func (h *roomHandler) updateRoom(c *gin.Context) error {
@@ -243,34 +232,9 @@ func (h *roomHandler) updateRoom(c *gin.Context) error {
 
 	user := val.(*models.User)
 
-	roomService := h.services.GetRoomService()
-	_, err = roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-		UserID: user.ID,
-		RoomID: roomId,
-	}, nil)
-	if err != nil {
-		return &utils.ServerError{
-			Err:        err,
-			Message:    utils.ErrUnauthorized.Error(),
-			StatusCode: http.StatusUnauthorized,
-		}
-	}
-
-	room, err := roomService.GetRoom(roomId, nil)
+	room, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom)
 	if err != nil {
-		return &utils.ServerError{
-			Message:    err.Error(),
-			Err:        err,
-			StatusCode: http.StatusInternalServerError,
-		}
-	}
-
-	if room.CreatedBy != user.ID {
-		return &utils.ServerError{
-			Message:    utils.ErrUnauthorized.Error(),
-			Err:        utils.ErrUnauthorized,
-			StatusCode: http.StatusUnauthorized,
-		}
+		return err
 	}
 
 	if updateRoomDto.Name != nil {
@@ -282,14 +246,24 @@ func (h *roomHandler) updateRoom(c *gin.Context) error {
 	if updateRoomDto.MaxMembers != nil {
 		room.MaxMembers = *updateRoomDto.MaxMembers
 	}
+	if updateRoomDto.MessageTTL != nil {
+		room.MessageTTL = *updateRoomDto.MessageTTL
+	}
+	if updateRoomDto.Visibility != nil {
+		room.Visibility = *updateRoomDto.Visibility
+	}
 
-	err = roomService.UpdateRoom(room, nil)
+	err = h.services.GetRoomService().UpdateRoom(room, nil)
 	if err != nil {
-		return &utils.ServerError{
-			Message:    err.Error(),
-			Err:        err,
-			StatusCode: http.StatusInternalServerError,
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidMessageTTL), errors.Is(err, services.ErrInvalidVisibility),
+			errors.Is(err, services.ErrDirectMessageRoom):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
 		}
+		return &se
 	}
 
 	c.JSON(http.StatusOK, utils.ResponseGeneric{
@@ -361,6 +335,12 @@ func (h *roomHandler) joinRoom(c *gin.Context) error {
 		case errors.Is(err, services.ErrMaxMembersReached):
 			se.StatusCode = http.StatusUnauthorized
 			se.Message = "max room members reached"
+		case errors.Is(err, services.ErrDirectMessageRoom),
+			errors.Is(err, services.ErrPrivateRoom),
+			errors.Is(err, services.ErrApprovalRequired),
+			errors.Is(err, services.ErrBanned):
+			se.StatusCode = http.StatusForbidden
+			se.Message = err.Error()
 		default:
 			se.StatusCode = http.StatusInternalServerError
 			se.Message = err.Error()
@@ -368,6 +348,12 @@ func (h *roomHandler) joinRoom(c *gin.Context) error {
 		return &se
 	}
 
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMemberJoined,
+		RoomID: roomId,
+		Data:   member,
+	})
+
 	c.JSON(http.StatusOK, utils.ResponseGeneric{
 		Success: true,
 		Message: "joined room successfully",
@@ -428,13 +414,22 @@ func (h *roomHandler) leaveRoom(c *gin.Context) error {
 
 	err = roomService.LeaveRoom(member.ID, nil)
 	if err != nil {
-		return &utils.ServerError{
-			Message:    err.Error(),
-			Err:        err,
-			StatusCode: http.StatusInternalServerError,
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrOwnerCannotLeave):
+			se.StatusCode = http.StatusConflict
+		default:
+			se.StatusCode = http.StatusInternalServerError
 		}
+		return &se
 	}
 
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMemberLeft,
+		RoomID: roomId,
+		Data:   member,
+	})
+
 	c.JSON(http.StatusOK, utils.ResponseGeneric{
 		Success: true,
 		Message: "left room successfully",
@@ -455,45 +450,13 @@ func (h *roomHandler) getRoomMembers(c *gin.Context) error {
 	}
 
 	user := val.(*models.User)
-	roomService := h.services.GetRoomService()
-
-	_, err := roomService.GetRoom(roomId, nil)
-	if err != nil {
-		se := utils.ServerError{Err: err}
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
-			se.Message = utils.ErrNotFound.Error()
-			se.StatusCode = http.StatusNotFound
-		default:
-			se.Message = err.Error()
-			se.StatusCode = http.StatusInternalServerError
-		}
-
-		return &se
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
 	}
 
-	_, err = roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-		UserID: user.ID,
-		RoomID: roomId,
-	}, nil)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return &utils.ServerError{
-				Err:        err,
-				Message:    "not a member of room",
-				StatusCode: http.StatusBadRequest,
-			}
-		}
-
-		return &utils.ServerError{
-			Err:        err,
-			Message:    err.Error(),
-			StatusCode: http.StatusInternalServerError,
-		}
-	}
+	roomService := h.services.GetRoomService()
 
 	members, err := roomService.GetRoomMembers(repositories.GetRoomMembersParams{
-		UserID: &user.ID,
 		RoomID: &roomId,
 	}, nil)
 	if err != nil {
@@ -539,36 +502,32 @@ func (h *roomHandler) getRoomMessages(c *gin.Context) error {
 	}
 
 	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
 	roomService := h.services.GetRoomService()
 
-	_, err := roomService.GetRoom(roomId, nil)
+	params, err := getRoomMessagesPageParams(c, roomId)
 	if err != nil {
-		se := utils.ServerError{Err: err}
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
-			se.Message = utils.ErrNotFound.Error()
-			se.StatusCode = http.StatusNotFound
-		default:
-			se.Message = err.Error()
-			se.StatusCode = http.StatusInternalServerError
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
 		}
-
-		return &se
 	}
 
-	_, err = roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-		UserID: user.ID,
-		RoomID: roomId,
-	}, nil)
+	page, err := roomService.GetMessagesPage(*params, nil)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return &utils.ServerError{
-				Err:        err,
-				Message:    "not a member of room",
-				StatusCode: http.StatusBadRequest,
-			}
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
 		}
+	}
 
+	err = h.services.GetAttachmentService().LoadMessageAttachments(page.Messages, nil)
+	if err != nil {
 		return &utils.ServerError{
 			Err:        err,
 			Message:    err.Error(),
@@ -576,21 +535,17 @@ func (h *roomHandler) getRoomMessages(c *gin.Context) error {
 		}
 	}
 
-	messages, err := roomService.GetMessages(repositories.GetRoomMessagesParams{
-		RoomID: &roomId,
-	}, nil)
+	err = h.services.GetUnfurlService().LoadMessagePreviews(page.Messages, nil)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			c.JSON(http.StatusOK, utils.ResponseGeneric{
-				Success: true,
-				Message: "messages fetched successfully",
-				Data: map[string][]*models.RoomMessage{
-					"messages": {},
-				},
-			})
-			return nil
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
 		}
+	}
 
+	err = h.services.GetPollService().LoadMessagePolls(page.Messages, nil)
+	if err != nil {
 		return &utils.ServerError{
 			Err:        err,
 			Message:    err.Error(),
@@ -602,9 +557,56 @@ func (h *roomHandler) getRoomMessages(c *gin.Context) error {
 		Success: true,
 		Message: "messages fetched successfully",
 		Data: map[string][]*models.RoomMessage{
-			"messages": messages,
+			"messages": page.Messages,
+		},
+		Meta: &utils.ResponseMeta{
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
 		},
 	})
 
 	return nil
 }
+
+const (
+	defaultMessagesLimit = 50
+	maxMessagesLimit     = 100
+)
+
+// getRoomMessagesPageParams reads the `before`, `after` and `limit` query
+// parameters used to page through a room's message history.
+func getRoomMessagesPageParams(c *gin.Context, roomId string) (*repositories.GetRoomMessagesPageParams, error) {
+	params := &repositories.GetRoomMessagesPageParams{
+		RoomID: roomId,
+		Limit:  defaultMessagesLimit,
+	}
+
+	before, after := c.Query("before"), c.Query("after")
+	if before != "" && after != "" {
+		return nil, errors.New("before and after cannot be used together")
+	}
+
+	var err error
+	if before != "" {
+		params.Before, err = utils.DecodeCursor(before)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if after != "" {
+		params.After, err = utils.DecodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil || params.Limit <= 0 {
+			return nil, errors.New("limit must be a positive integer")
+		}
+		params.Limit = min(params.Limit, maxMessagesLimit)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,181 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type CreateInviteDto struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+	MaxUses   *int       `json:"max_uses" validate:"omitempty,gt=0"`
+}
+
+func (h *roomHandler) createInvite(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var createInviteDto CreateInviteDto
+	err := c.BindJSON(&createInviteDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionManageInvites); err != nil {
+		return err
+	}
+
+	invite := &models.RoomInvite{
+		RoomID:    roomId,
+		CreatedBy: user.ID,
+		ExpiresAt: createInviteDto.ExpiresAt,
+		MaxUses:   createInviteDto.MaxUses,
+	}
+	err = h.services.GetRoomInviteService().CreateInvite(invite, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInviteExpiresInPast),
+			errors.Is(err, services.ErrInvalidInviteUses),
+			errors.Is(err, services.ErrDirectMessageRoom):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "invite created successfully",
+		Data: map[string]any{
+			"invite": invite,
+			"url":    inviteURL(c, invite.Code),
+		},
+	})
+	return nil
+}
+
+func (h *roomHandler) getInvites(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionManageInvites); err != nil {
+		return err
+	}
+
+	invites, err := h.services.GetRoomInviteService().GetInvites(roomId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "invites fetched successfully",
+		Data:    map[string][]*models.RoomInvite{"invites": invites},
+	})
+	return nil
+}
+
+func (h *roomHandler) revokeInvite(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	inviteId := c.Params.ByName("inviteId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionManageInvites); err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	inviteService := h.services.GetRoomInviteService()
+	invite, err := inviteService.GetInvite(inviteId, tx)
+	if err == nil && invite.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	err = inviteService.RevokeInvite(invite, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "invite revoked successfully",
+		Data:    map[string]*models.RoomInvite{"invite": invite},
+	})
+	return nil
+}
+
+// inviteURL returns the link people follow to join with an invite, as
+// reached through the host the request was made to.
+func inviteURL(c *gin.Context, code string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/api/v1/invites/%s", scheme, c.Request.Host, code)
+}
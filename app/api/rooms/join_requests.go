@@ -0,0 +1,267 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type CreateJoinRequestDto struct {
+	Message string `json:"message" validate:"max=500"`
+}
+
+// requestToJoin asks to join a restricted room and lets the room's
+// approvers know.
+func (h *roomHandler) requestToJoin(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var createJoinRequestDto CreateJoinRequestDto
+	err := c.BindJSON(&createJoinRequestDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	request := &models.RoomJoinRequest{
+		RoomID:  roomId,
+		UserID:  user.ID,
+		Message: createJoinRequestDto.Message,
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	err = h.services.GetRoomJoinRequestService().RequestToJoin(request, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return joinRequestError(err)
+	}
+
+	h.notifyApprovers(request)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "join request sent successfully",
+		Data:    map[string]*models.RoomJoinRequest{"request": request},
+	})
+	return nil
+}
+
+func (h *roomHandler) getJoinRequests(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReviewJoinRequests); err != nil {
+		return err
+	}
+
+	requests, err := h.services.GetRoomJoinRequestService().GetJoinRequests(roomId, c.Query("status"), nil)
+	if err != nil {
+		return joinRequestError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "join requests fetched successfully",
+		Data:    map[string][]*models.RoomJoinRequest{"requests": requests},
+	})
+	return nil
+}
+
+func (h *roomHandler) approveJoinRequest(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReviewJoinRequests); err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	request, err := h.roomJoinRequest(c, tx)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.services.GetRoomJoinRequestService().ApproveJoinRequest(request, user.ID, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return joinRequestError(err)
+	}
+
+	eventService := h.services.GetEventService()
+	eventService.Publish(&models.Event{
+		Type:   models.EventJoinRequestReviewed,
+		RoomID: roomId,
+		UserID: request.UserID,
+		Data:   request,
+	})
+	eventService.Publish(&models.Event{
+		Type:   models.EventMemberJoined,
+		RoomID: roomId,
+		Data:   member,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "join request approved successfully",
+		Data: map[string]any{
+			"request": request,
+			"member":  member,
+		},
+	})
+	return nil
+}
+
+func (h *roomHandler) rejectJoinRequest(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReviewJoinRequests); err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	request, err := h.roomJoinRequest(c, tx)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.GetRoomJoinRequestService().RejectJoinRequest(request, user.ID, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return joinRequestError(err)
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventJoinRequestReviewed,
+		RoomID: roomId,
+		UserID: request.UserID,
+		Data:   request,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "join request rejected successfully",
+		Data:    map[string]*models.RoomJoinRequest{"request": request},
+	})
+	return nil
+}
+
+// roomJoinRequest returns the join request named in the request, or a not
+// found error if it does not belong to the room.
+func (h *roomHandler) roomJoinRequest(c *gin.Context, tx pgx.Tx) (*models.RoomJoinRequest, error) {
+	roomId := c.Params.ByName("roomId")
+	requestId := c.Params.ByName("requestId")
+
+	request, err := h.services.GetRoomJoinRequestService().GetJoinRequest(requestId, tx)
+	if err == nil && request.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		return nil, joinRequestError(err)
+	}
+
+	return request, nil
+}
+
+// notifyApprovers tells everyone who can review request about it. The
+// request is already saved, so failing to look them up is only logged.
+func (h *roomHandler) notifyApprovers(request *models.RoomJoinRequest) {
+	approvers, err := h.services.GetRoomJoinRequestService().GetApprovers(request.RoomID, nil)
+	if err != nil {
+		log.Printf("failed to notify approvers of join request %s: %v", request.ID, err)
+		return
+	}
+
+	for _, approver := range approvers {
+		h.services.GetEventService().Publish(&models.Event{
+			Type:   models.EventJoinRequestCreated,
+			RoomID: request.RoomID,
+			UserID: approver.UserID,
+			Data:   request,
+		})
+	}
+}
+
+func joinRequestError(err error) error {
+	se := utils.ServerError{Err: err, Message: err.Error()}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		se.Message = utils.ErrNotFound.Error()
+		se.StatusCode = http.StatusNotFound
+	case errors.Is(err, services.ErrJoinRequestTooLong),
+		errors.Is(err, services.ErrInvalidJoinRequestStatus),
+		errors.Is(err, services.ErrJoinRequestNotNeeded),
+		errors.Is(err, services.ErrDirectMessageRoom):
+		se.StatusCode = http.StatusBadRequest
+	case errors.Is(err, services.ErrJoinRequestPending),
+		errors.Is(err, services.ErrJoinRequestReviewed):
+		se.StatusCode = http.StatusConflict
+	case errors.Is(err, services.ErrAlreadyMember):
+		se.StatusCode = http.StatusNotAcceptable
+	case errors.Is(err, services.ErrMaxMembersReached):
+		se.StatusCode = http.StatusUnauthorized
+	case errors.Is(err, services.ErrBanned):
+		se.StatusCode = http.StatusForbidden
+	default:
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
@@ -0,0 +1,413 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type RestrictUserDto struct {
+	UserID    string     `json:"user_id" validate:"required,uuid"`
+	Reason    string     `json:"reason" validate:"required,max=500"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// banUser removes a user from the room and keeps them from joining again
+// until the ban expires or is lifted. Users who are not members can be
+// banned too.
+func (h *roomHandler) banUser(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var banUserDto RestrictUserDto
+	err := c.BindJSON(&banUserDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	_, actor, err := h.authorize(roomId, user, services.PermissionBanMember)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.services.GetUserService().GetUser(repositories.GetUserParams{ID: banUserDto.UserID}, nil)
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	member, err := h.roomMemberByUser(roomId, banUserDto.UserID, tx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return memberActionError(err)
+	}
+	if member != nil {
+		err = h.services.GetAuthorizationService().AuthorizeOver(actor, member, services.PermissionBanMember)
+		if err != nil {
+			return authorizationError(err)
+		}
+	}
+
+	ban := &models.RoomBan{
+		RoomID:    roomId,
+		UserID:    banUserDto.UserID,
+		Reason:    banUserDto.Reason,
+		CreatedBy: user.ID,
+		ExpiresAt: banUserDto.ExpiresAt,
+	}
+	action, err := h.services.GetMemberModerationService().BanUser(ban, member, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	h.notifyMemberAction(models.EventMemberBanned, action, member)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "user banned successfully",
+		Data: map[string]any{
+			"ban":    ban,
+			"action": action,
+		},
+	})
+	return nil
+}
+
+func (h *roomHandler) getBans(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionBanMember); err != nil {
+		return err
+	}
+
+	bans, err := h.services.GetMemberModerationService().GetBans(roomId, nil)
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "bans fetched successfully",
+		Data:    map[string][]*models.RoomBan{"bans": bans},
+	})
+	return nil
+}
+
+func (h *roomHandler) unbanUser(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	userId := c.Params.ByName("userId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionBanMember); err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	action, err := h.services.GetMemberModerationService().UnbanUser(roomId, userId, user.ID, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	h.notifyMemberAction(models.EventMemberUnbanned, action, nil)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "user unbanned successfully",
+		Data:    map[string]*models.RoomMemberAction{"action": action},
+	})
+	return nil
+}
+
+// muteMember stops a member from posting to the room until the mute expires
+// or is lifted. They stay in the room and can still read it.
+func (h *roomHandler) muteMember(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var muteMemberDto RestrictUserDto
+	err := c.BindJSON(&muteMemberDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	_, actor, err := h.authorize(roomId, user, services.PermissionMuteMember)
+	if err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	member, err := h.roomMemberByUser(roomId, muteMemberDto.UserID, tx)
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	err = h.services.GetAuthorizationService().AuthorizeOver(actor, member, services.PermissionMuteMember)
+	if err != nil {
+		return authorizationError(err)
+	}
+
+	mute := &models.RoomMute{
+		RoomID:    roomId,
+		UserID:    member.UserID,
+		Reason:    muteMemberDto.Reason,
+		CreatedBy: user.ID,
+		ExpiresAt: muteMemberDto.ExpiresAt,
+	}
+	action, err := h.services.GetMemberModerationService().MuteMember(mute, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	h.notifyMemberAction(models.EventMemberMuted, action, nil)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "member muted successfully",
+		Data: map[string]any{
+			"mute":   mute,
+			"action": action,
+		},
+	})
+	return nil
+}
+
+func (h *roomHandler) getMutes(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionMuteMember); err != nil {
+		return err
+	}
+
+	mutes, err := h.services.GetMemberModerationService().GetMutes(roomId, nil)
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "mutes fetched successfully",
+		Data:    map[string][]*models.RoomMute{"mutes": mutes},
+	})
+	return nil
+}
+
+func (h *roomHandler) unmuteMember(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	userId := c.Params.ByName("userId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	_, actor, err := h.authorize(roomId, user, services.PermissionMuteMember)
+	if err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	// the mute outlives a member leaving, so only those still in the room
+	// are checked against the actor's rank
+	member, err := h.roomMemberByUser(roomId, userId, tx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return memberActionError(err)
+	}
+	if member != nil {
+		err = h.services.GetAuthorizationService().AuthorizeOver(actor, member, services.PermissionMuteMember)
+		if err != nil {
+			return authorizationError(err)
+		}
+	}
+
+	action, err := h.services.GetMemberModerationService().UnmuteMember(roomId, userId, user.ID, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	h.notifyMemberAction(models.EventMemberUnmuted, action, nil)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "member unmuted successfully",
+		Data:    map[string]*models.RoomMemberAction{"action": action},
+	})
+	return nil
+}
+
+// getMemberActions returns the audit trail of kicks, bans and mutes in the
+// room, newest first.
+func (h *roomHandler) getMemberActions(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	limit := defaultAuditLogLimit
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxAuditLogLimit {
+			return &utils.ServerError{
+				Err:        errors.New("invalid limit"),
+				Message:    "invalid limit",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		limit = n
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionKickMember); err != nil {
+		return err
+	}
+
+	actions, err := h.services.GetMemberModerationService().GetMemberActions(roomId, limit, nil)
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "member actions fetched successfully",
+		Data:    map[string][]*models.RoomMemberAction{"actions": actions},
+	})
+	return nil
+}
+
+// roomMemberByUser returns userId's membership of the room, or
+// pgx.ErrNoRows if they are not in it.
+func (h *roomHandler) roomMemberByUser(roomId, userId string, tx pgx.Tx) (*models.RoomMember, error) {
+	return h.services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: userId,
+		RoomID: roomId,
+	}, tx)
+}
+
+// notifyMemberAction tells the affected user about action. If it removed
+// them from the room, the rest of the room is told they left.
+func (h *roomHandler) notifyMemberAction(eventType string, action *models.RoomMemberAction, removed *models.RoomMember) {
+	eventService := h.services.GetEventService()
+	eventService.Publish(&models.Event{
+		Type:   eventType,
+		RoomID: action.RoomID,
+		UserID: action.UserID,
+		Data:   action,
+	})
+	if removed != nil {
+		eventService.Publish(&models.Event{
+			Type:   models.EventMemberLeft,
+			RoomID: action.RoomID,
+			Data:   removed,
+		})
+	}
+}
+
+func memberActionError(err error) error {
+	se := utils.ServerError{Err: err, Message: err.Error()}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		se.Message = utils.ErrNotFound.Error()
+		se.StatusCode = http.StatusNotFound
+	case errors.Is(err, services.ErrReasonRequired),
+		errors.Is(err, services.ErrReasonTooLong),
+		errors.Is(err, services.ErrModerationExpiresInPast),
+		errors.Is(err, services.ErrDirectMessageRoom):
+		se.StatusCode = http.StatusBadRequest
+	default:
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
@@ -0,0 +1,166 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type UpdateMemberRoleDto struct {
+	Role string `json:"role" validate:"required,oneof=admin moderator member"`
+}
+
+// updateMemberRole changes another member's role. Members can only manage
+// those below them and grant roles below their own.
+func (h *roomHandler) updateMemberRole(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var updateMemberRoleDto UpdateMemberRoleDto
+	err := c.BindJSON(&updateMemberRoleDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	_, actor, err := h.authorize(roomId, user, services.PermissionManageRoles)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.roomMember(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.GetAuthorizationService().AuthorizeRoleChange(actor, member, updateMemberRoleDto.Role)
+	if err != nil {
+		return authorizationError(err)
+	}
+
+	member.Role = updateMemberRoleDto.Role
+	err = h.services.GetRoomService().UpdateMemberRole(member, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "member role updated successfully",
+		Data:    map[string]*models.RoomMember{"member": member},
+	})
+	return nil
+}
+
+type KickMemberDto struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// kickMember removes another member from the room and tells them why. They
+// can join again.
+func (h *roomHandler) kickMember(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var kickMemberDto KickMemberDto
+	err := c.BindJSON(&kickMemberDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	_, actor, err := h.authorize(roomId, user, services.PermissionKickMember)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.roomMember(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.GetAuthorizationService().AuthorizeOver(actor, member, services.PermissionKickMember)
+	if err != nil {
+		return authorizationError(err)
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	action, err := h.services.GetMemberModerationService().KickMember(member, user.ID, kickMemberDto.Reason, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		return memberActionError(err)
+	}
+
+	h.notifyMemberAction(models.EventMemberKicked, action, member)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "member removed successfully",
+		Data:    map[string]*models.RoomMemberAction{"action": action},
+	})
+	return nil
+}
+
+// roomMember returns the member named in the request, or a not found error
+// if they are not in the room.
+func (h *roomHandler) roomMember(c *gin.Context) (*models.RoomMember, error) {
+	roomId := c.Params.ByName("roomId")
+	memberId := c.Params.ByName("memberId")
+
+	member, err := h.services.GetRoomService().GetRoomMember(memberId, nil)
+	if err == nil && member.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return nil, &se
+	}
+
+	return member, nil
+}
@@ -0,0 +1,93 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+// deleteMessage removes a message along with its attachments. Members can
+// delete their own messages; deleting anyone else's needs
+// PermissionDeleteMessages.
+func (h *roomHandler) deleteMessage(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	messageId := c.Params.ByName("messageId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	_, member, err := h.authorize(roomId, user, services.PermissionReadRoom)
+	if err != nil {
+		return err
+	}
+
+	roomService := h.services.GetRoomService()
+	message, err := roomService.GetMessage(messageId, nil)
+	if err == nil && message.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = "message not found"
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	if message.UserID != user.ID && !h.services.GetAuthorizationService().Can(member.Role, services.PermissionDeleteMessages) {
+		return &utils.ServerError{
+			Message:    utils.ErrUnauthorized.Error(),
+			Err:        utils.ErrUnauthorized,
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+
+	messages := []*models.RoomMessage{message}
+	err = h.services.GetAttachmentService().LoadMessageAttachments(messages, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	err = roomService.DeleteMessage(message.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.services.GetAttachmentService().DeleteAttachmentBlobs(message.Attachments)
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageDeleted,
+		RoomID: roomId,
+		Data:   &models.MessagesRemoved{MessageIDs: []string{message.ID}},
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message deleted successfully",
+	})
+	return nil
+}
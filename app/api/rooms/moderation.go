@@ -0,0 +1,153 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/moderation"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 200
+)
+
+func (h *roomHandler) getModeration(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	settings, err := h.services.GetModerationService().GetRoomModeration(roomId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "moderation settings fetched successfully",
+		Data:    map[string]*models.RoomModeration{"moderation": settings},
+	})
+	return nil
+}
+
+// updateModeration replaces the room's moderation settings. Filters left
+// out of the body are turned off.
+func (h *roomHandler) updateModeration(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var config moderation.Config
+	err := c.BindJSON(&config)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	settings := &models.RoomModeration{
+		RoomID:    roomId,
+		Config:    &config,
+		UpdatedBy: user.ID,
+	}
+	err = h.services.GetModerationService().SaveRoomModeration(settings, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidModerationConfig):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "moderation settings updated successfully",
+		Data:    map[string]*models.RoomModeration{"moderation": settings},
+	})
+	return nil
+}
+
+func (h *roomHandler) getModerationAuditLog(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	limit := defaultAuditLogLimit
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxAuditLogLimit {
+			return &utils.ServerError{
+				Err:        errors.New("invalid limit"),
+				Message:    "invalid limit",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		limit = n
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	entries, err := h.services.GetModerationService().GetAuditLog(roomId, limit, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "moderation audit log fetched successfully",
+		Data:    map[string][]*models.ModerationAuditEntry{"entries": entries},
+	})
+	return nil
+}
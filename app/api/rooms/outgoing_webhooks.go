@@ -0,0 +1,220 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type CreateOutgoingWebhookDto struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"required,min=1"`
+}
+
+func (h *roomHandler) createOutgoingWebhook(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var createOutgoingWebhookDto CreateOutgoingWebhookDto
+	err := c.BindJSON(&createOutgoingWebhookDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	webhook := &models.OutgoingWebhook{
+		RoomID:     roomId,
+		URL:        createOutgoingWebhookDto.URL,
+		EventTypes: createOutgoingWebhookDto.EventTypes,
+		CreatedBy:  user.ID,
+	}
+	err = h.services.GetOutgoingWebhookService().CreateOutgoingWebhook(webhook, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrInvalidWebhookEventTypes):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhook created successfully",
+		Data:    map[string]*models.OutgoingWebhook{"webhook": webhook},
+	})
+	return nil
+}
+
+func (h *roomHandler) getOutgoingWebhooks(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	webhooks, err := h.services.GetOutgoingWebhookService().GetOutgoingWebhooks(roomId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhooks fetched successfully",
+		Data:    map[string][]*models.OutgoingWebhook{"webhooks": webhooks},
+	})
+	return nil
+}
+
+func (h *roomHandler) deleteOutgoingWebhook(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	webhook, err := h.roomOutgoingWebhook(c, user)
+	if err != nil {
+		return err
+	}
+
+	err = h.services.GetOutgoingWebhookService().DeleteOutgoingWebhook(webhook.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhook deleted successfully",
+	})
+	return nil
+}
+
+// getWebhookDeliveries returns the delivery history of a webhook, newest
+// first, so failing endpoints can be diagnosed.
+func (h *roomHandler) getWebhookDeliveries(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	limit := defaultDeliveriesLimit
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > maxDeliveriesLimit {
+			return &utils.ServerError{
+				Err:        errors.New("invalid limit"),
+				Message:    "invalid limit",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		limit = n
+	}
+
+	user := val.(*models.User)
+	webhook, err := h.roomOutgoingWebhook(c, user)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.services.GetOutgoingWebhookService().GetWebhookDeliveries(webhook.ID, limit, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "deliveries fetched successfully",
+		Data:    map[string][]*models.WebhookDelivery{"deliveries": deliveries},
+	})
+	return nil
+}
+
+// roomOutgoingWebhook returns the outgoing webhook named in the request if
+// user created its room.
+func (h *roomHandler) roomOutgoingWebhook(c *gin.Context, user *models.User) (*models.OutgoingWebhook, error) {
+	roomId := c.Params.ByName("roomId")
+	webhookId := c.Params.ByName("webhookId")
+
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return nil, err
+	}
+
+	webhook, err := h.services.GetOutgoingWebhookService().GetOutgoingWebhook(webhookId, nil)
+	if err == nil && webhook.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return nil, &se
+	}
+
+	return webhook, nil
+}
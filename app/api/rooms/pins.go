@@ -0,0 +1,167 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+func (h *roomHandler) getPins(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	pins, err := h.services.GetRoomService().GetPins(roomId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "pins fetched successfully",
+		Data:    map[string][]*models.RoomPin{"pins": pins},
+	})
+	return nil
+}
+
+type PinMessageDto struct {
+	MessageID string `json:"message_id" validate:"required,uuid"`
+}
+
+func (h *roomHandler) pinMessage(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var pinMessageDto PinMessageDto
+	err := c.BindJSON(&pinMessageDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionPinMessage); err != nil {
+		return err
+	}
+
+	pin := &models.RoomPin{
+		RoomID:        roomId,
+		RoomMessageID: pinMessageDto.MessageID,
+		PinnedBy:      user.ID,
+	}
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	err = h.services.GetRoomService().PinMessage(pin, tx)
+	if err == nil {
+		err = tx.Commit(context.Background())
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = "message not found"
+			se.StatusCode = http.StatusNotFound
+		case errors.Is(err, services.ErrMessageNotInRoom):
+			se.StatusCode = http.StatusBadRequest
+		case errors.Is(err, services.ErrAlreadyPinned):
+			se.StatusCode = http.StatusNotAcceptable
+		case errors.Is(err, services.ErrMaxPinsReached):
+			se.StatusCode = http.StatusNotAcceptable
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessagePinned,
+		RoomID: roomId,
+		Data:   pin,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message pinned successfully",
+		Data:    map[string]*models.RoomPin{"pin": pin},
+	})
+	return nil
+}
+
+func (h *roomHandler) unpinMessage(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	messageId := c.Params.ByName("messageId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionPinMessage); err != nil {
+		return err
+	}
+
+	pin, err := h.services.GetRoomService().UnpinMessage(roomId, messageId, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = "pin not found"
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageUnpinned,
+		RoomID: roomId,
+		Data:   pin,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message unpinned successfully",
+	})
+	return nil
+}
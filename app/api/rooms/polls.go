@@ -0,0 +1,361 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type CreatePollDto struct {
+	Question       string     `json:"question" validate:"required"`
+	Options        []string   `json:"options" validate:"required,min=2,max=10"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	Anonymous      bool       `json:"anonymous"`
+	ClosesAt       *time.Time `json:"closes_at"`
+}
+
+type VotePollDto struct {
+	OptionIDs []string `json:"option_ids" validate:"required,min=1,dive,uuid"`
+}
+
+func (h *roomHandler) createPoll(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var createPollDto CreatePollDto
+	err := c.BindJSON(&createPollDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	_, member, err := h.authorize(roomId, user, services.PermissionSendMessage)
+	if err != nil {
+		return err
+	}
+
+	message := &models.RoomMessage{
+		RoomID:       roomId,
+		UserID:       user.ID,
+		RoomMemberID: member.ID,
+	}
+	poll := &models.Poll{
+		Question:       createPollDto.Question,
+		MultipleChoice: createPollDto.MultipleChoice,
+		Anonymous:      createPollDto.Anonymous,
+		ClosesAt:       createPollDto.ClosesAt,
+	}
+	for _, text := range createPollDto.Options {
+		poll.Options = append(poll.Options, &models.PollOption{Text: text})
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	err = h.services.GetPollService().CreatePoll(message, poll, tx)
+	if err != nil {
+		return pollError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.services.GetUnfurlService().EnqueueMessage(message)
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageCreated,
+		RoomID: roomId,
+		Data:   message,
+	})
+	h.services.GetMessageDraftService().ClearSentDraft(message, c.GetHeader(sessionHeader))
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "poll created successfully",
+		Data:    map[string]*models.RoomMessage{"message": message},
+	})
+	return nil
+}
+
+func (h *roomHandler) getPoll(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	poll, err := h.roomPoll(c, user)
+	if err != nil {
+		return err
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "poll fetched successfully",
+		Data:    map[string]*models.Poll{"poll": poll},
+	})
+	return nil
+}
+
+func (h *roomHandler) votePoll(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var votePollDto VotePollDto
+	err := c.BindJSON(&votePollDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionSendMessage); err != nil {
+		return err
+	}
+
+	poll, err := h.roomPoll(c, user)
+	if err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	poll, err = h.services.GetPollService().Vote(poll.ID, user.ID, votePollDto.OptionIDs, tx)
+	if err != nil {
+		return pollError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.publishPollUpdated(poll)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "vote recorded successfully",
+		Data:    map[string]*models.Poll{"poll": poll},
+	})
+	return nil
+}
+
+func (h *roomHandler) retractPollVote(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionSendMessage); err != nil {
+		return err
+	}
+
+	poll, err := h.roomPoll(c, user)
+	if err != nil {
+		return err
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	poll, err = h.services.GetPollService().RetractVote(poll.ID, user.ID, tx)
+	if err != nil {
+		return pollError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.publishPollUpdated(poll)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "vote retracted successfully",
+		Data:    map[string]*models.Poll{"poll": poll},
+	})
+	return nil
+}
+
+// closePoll ends voting on a poll. Only its creator and the members who
+// can manage polls may close it.
+func (h *roomHandler) closePoll(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	_, member, err := h.authorize(roomId, user, services.PermissionReadRoom)
+	if err != nil {
+		return err
+	}
+
+	poll, err := h.roomPoll(c, user)
+	if err != nil {
+		return err
+	}
+
+	if poll.CreatedBy != user.ID && !h.services.GetAuthorizationService().Can(member.Role, services.PermissionManagePolls) {
+		return &utils.ServerError{
+			Message:    utils.ErrUnauthorized.Error(),
+			Err:        utils.ErrUnauthorized,
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	poll, err = h.services.GetPollService().ClosePoll(poll.ID, tx)
+	if err != nil {
+		return pollError(err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	h.publishPollUpdated(poll)
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "poll closed successfully",
+		Data:    map[string]*models.Poll{"poll": poll},
+	})
+	return nil
+}
+
+// roomPoll returns the poll named in the request as seen by user, or a not
+// found error if it does not belong to the room.
+func (h *roomHandler) roomPoll(c *gin.Context, user *models.User) (*models.Poll, error) {
+	roomId := c.Params.ByName("roomId")
+	pollId := c.Params.ByName("pollId")
+
+	poll, err := h.services.GetPollService().GetPoll(pollId, user.ID, nil)
+	if err == nil && poll.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return nil, &se
+	}
+
+	return poll, nil
+}
+
+// publishPollUpdated pushes the new results of poll to the room. The
+// caller's own choices are not part of the shared payload.
+func (h *roomHandler) publishPollUpdated(poll *models.Poll) {
+	update := *poll
+	update.VotedOptionIDs = nil
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventPollUpdated,
+		RoomID: poll.RoomID,
+		Data:   &update,
+	})
+}
+
+func pollError(err error) error {
+	se := utils.ServerError{Err: err, Message: err.Error()}
+	switch {
+	case errors.Is(err, services.ErrPollQuestionRequired),
+		errors.Is(err, services.ErrInvalidPollOptions),
+		errors.Is(err, services.ErrPollClosesInPast),
+		errors.Is(err, services.ErrInvalidPollVote),
+		errors.Is(err, services.ErrMessageTooLong):
+		se.StatusCode = http.StatusBadRequest
+	case errors.Is(err, services.ErrPollClosed):
+		se.StatusCode = http.StatusConflict
+	case errors.Is(err, services.ErrBlocked),
+		errors.Is(err, services.ErrMuted):
+		se.StatusCode = http.StatusForbidden
+	case errors.Is(err, services.ErrMessageRejected):
+		se.StatusCode = http.StatusUnprocessableEntity
+	default:
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
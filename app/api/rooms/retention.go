@@ -0,0 +1,75 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type UpdateRetentionDto struct {
+	// RetentionDays left out falls back to the server default, zero keeps
+	// messages forever.
+	RetentionDays *int `json:"retention_days"`
+	LegalHold     bool `json:"legal_hold"`
+}
+
+// updateRetention replaces the room's retention period and legal hold.
+func (h *roomHandler) updateRetention(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var updateRetentionDto UpdateRetentionDto
+	err := c.BindJSON(&updateRetentionDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	room, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom)
+	if err != nil {
+		return err
+	}
+
+	room.RetentionDays = updateRetentionDto.RetentionDays
+	room.LegalHold = updateRetentionDto.LegalHold
+	err = h.services.GetRoomService().UpdateRetention(room, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidRetention):
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	h.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventRoomUpdated,
+		RoomID: roomId,
+		Data:   room,
+	})
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "retention updated successfully",
+		Data:    map[string]*models.Room{"room": room},
+	})
+	return nil
+}
@@ -3,20 +3,32 @@ package rooms
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/princecee/go_chat/app/api/auth"
+	"github.com/princecee/go_chat/app/api/hooks"
+	"github.com/princecee/go_chat/app/api/invites"
+	"github.com/princecee/go_chat/internal/db/repositories"
 	"github.com/princecee/go_chat/internal/models"
 	"github.com/princecee/go_chat/internal/services"
 	"github.com/princecee/go_chat/utils"
@@ -41,12 +53,16 @@ func (s *roomsTestSuite) SetupSuite() {
 		log.Fatal(err)
 	}
 
+	os.Setenv("BLOB_STORE", "local")
+	os.Setenv("BLOB_STORE_PATH", s.T().TempDir())
 	s.services = services.New(conn)
 
 	r := gin.New()
 
 	auth.Routes(r.Group("/api/v1/auth"), s.services)
 	Routes(r.Group("/api/v1/rooms"), s.services)
+	hooks.Routes(r.Group("/api/v1/hooks"), s.services)
+	invites.Routes(r.Group("/api/v1/invites"), s.services)
 
 	s.server = httptest.NewServer(r.Handler())
 }
@@ -303,6 +319,16 @@ func (s *roomsTestSuite) TestRoomsHandler() {
 		s.Equal(http.StatusOK, resp.StatusCode)
 		s.Equal("left room successfully", data.Message)
 		s.Equal(true, data.Success)
+
+		req, err = http.NewRequest("POST", url, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusConflict, resp.StatusCode)
 	})
 
 	s.Run("get room members", func() {
@@ -346,6 +372,1004 @@ func (s *roomsTestSuite) TestRoomsHandler() {
 		s.Equal(0, len(data.Data["messages"]))
 	})
 
+	s.Run("pin and unpin message", func() {
+		member, err := s.services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: user.ID,
+			RoomID: room.ID,
+		}, nil)
+		s.NoError(err)
+
+		message := models.RoomMessage{
+			RoomID:       room.ID,
+			RoomMemberID: member.ID,
+			UserID:       user.ID,
+			Content:      "Runbook: restart the server",
+		}
+		err = s.services.GetRoomService().CreateMessage(&message, nil)
+		s.NoError(err)
+
+		pinDtoJson, err := json.Marshal(map[string]string{"message_id": message.ID})
+		s.NoError(err)
+
+		url := fmt.Sprintf("%s/%s/pins", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(pinDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		req, err = http.NewRequest("POST", url, bytes.NewBuffer(pinDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.RoomPin]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("message pinned successfully", data.Message)
+		s.Equal(message.ID, data.Data["pin"].RoomMessageID)
+
+		req, err = http.NewRequest("GET", url, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var pinsData utils.Response[map[string][]models.RoomPin]
+		err = utils.ReadJSON(resp.Body, &pinsData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal(1, len(pinsData.Data["pins"]))
+		s.Equal(message.Content, pinsData.Data["pins"][0].Message.Content)
+
+		req, err = http.NewRequest("DELETE", fmt.Sprintf("%s/%s", url, message.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var unpinData utils.Response[any]
+		err = utils.ReadJSON(resp.Body, &unpinData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("message unpinned successfully", unpinData.Message)
+	})
+
+	s.Run("upload and download attachment", func() {
+		png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		s.NoError(writer.WriteField("content", "screenshot"))
+		part, err := writer.CreateFormFile("file", "screen.txt")
+		s.NoError(err)
+		_, err = part.Write(png)
+		s.NoError(err)
+		s.NoError(writer.Close())
+
+		url := fmt.Sprintf("%s/%s/attachments", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, body)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.RoomMessage]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("attachment uploaded successfully", data.Message)
+
+		message := data.Data["message"]
+		s.Equal("screenshot", message.Content)
+		s.Len(message.Attachments, 1)
+
+		attachment := message.Attachments[0]
+		s.Equal("image/png", attachment.ContentType)
+		s.Equal(int64(len(png)), attachment.Size)
+
+		req, err = http.NewRequest("GET", baseUrl+attachment.URL, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		req, err = http.NewRequest("GET", baseUrl+attachment.URL, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		downloaded, err := io.ReadAll(resp.Body)
+		s.NoError(err)
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("image/png", resp.Header.Get("Content-Type"))
+		s.Equal(png, downloaded)
+	})
+
+	s.Run("process image attachment", func() {
+		img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+		draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0, 128, 255, 255}}, image.Point{}, draw.Src)
+		encoded := &bytes.Buffer{}
+		s.NoError(png.Encode(encoded, img))
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "banner.png")
+		s.NoError(err)
+		_, err = part.Write(encoded.Bytes())
+		s.NoError(err)
+		s.NoError(writer.Close())
+
+		url := fmt.Sprintf("%s/%s/attachments", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, body)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.RoomMessage]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		attachment := data.Data["message"].Attachments[0]
+		s.Equal(models.AttachmentStatusPending, attachment.ProcessingStatus)
+
+		attachmentService := s.services.GetAttachmentService()
+		s.NoError(attachmentService.ProcessAttachment(attachment.ID))
+
+		processed, err := attachmentService.GetAttachment(attachment.ID, nil)
+		s.NoError(err)
+		s.Equal(models.AttachmentStatusProcessed, processed.ProcessingStatus)
+		s.Equal(400, processed.Width)
+		s.Equal(200, processed.Height)
+		s.Equal("#0080ff", processed.DominantColor)
+		s.Len(processed.Thumbnails, 2)
+		s.Equal("small", processed.Thumbnails[0].Label)
+		s.Equal(96, processed.Thumbnails[0].Width)
+		s.Equal(48, processed.Thumbnails[0].Height)
+
+		req, err = http.NewRequest("GET", baseUrl+processed.Thumbnails[1].URL, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("image/png", resp.Header.Get("Content-Type"))
+
+		thumbnail, err := png.Decode(resp.Body)
+		s.NoError(err)
+		s.Equal(320, thumbnail.Bounds().Dx())
+	})
+
+	s.Run("schedule message", func() {
+		scheduleDtoJson, err := json.Marshal(map[string]any{
+			"content":      "Standup in 5 minutes",
+			"scheduled_at": time.Now().Add(time.Hour),
+		})
+		s.NoError(err)
+
+		url := fmt.Sprintf("%s/%s/scheduled-messages", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(scheduleDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.ScheduledMessage]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		scheduled := data.Data["scheduled_message"]
+		s.Equal(models.ScheduledMessageStatusPending, scheduled.Status)
+
+		scheduledUrl := fmt.Sprintf("%s/%s", url, scheduled.ID)
+		req, err = http.NewRequest("GET", scheduledUrl, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+
+		pastDtoJson, err := json.Marshal(map[string]any{"scheduled_at": time.Now().Add(-time.Hour)})
+		s.NoError(err)
+
+		req, err = http.NewRequest("PATCH", scheduledUrl, bytes.NewBuffer(pastDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+		// make the message due without waiting for it
+		_, err = s.services.GetDB().Exec(context.Background(),
+			"UPDATE scheduled_messages SET scheduled_at = NOW() - INTERVAL '1 minute' WHERE id = $1", scheduled.ID)
+		s.NoError(err)
+
+		scheduledMessageService := s.services.GetScheduledMessageService()
+		n, err := scheduledMessageService.DispatchDue(context.Background())
+		s.NoError(err)
+		s.Equal(1, n)
+
+		n, err = scheduledMessageService.DispatchDue(context.Background())
+		s.NoError(err)
+		s.Equal(0, n)
+
+		sent, err := scheduledMessageService.GetScheduledMessage(scheduled.ID, nil)
+		s.NoError(err)
+		s.Equal(models.ScheduledMessageStatusSent, sent.Status)
+
+		message, err := s.services.GetRoomService().GetMessage(sent.RoomMessageID, nil)
+		s.NoError(err)
+		s.Equal("Standup in 5 minutes", message.Content)
+
+		req, err = http.NewRequest("DELETE", scheduledUrl, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("expire messages", func() {
+		roomService := s.services.GetRoomService()
+		member, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: user.ID,
+			RoomID: room.ID,
+		}, nil)
+		s.NoError(err)
+
+		ttlDtoJson, err := json.Marshal(map[string]int{"message_ttl": 60})
+		s.NoError(err)
+
+		url := fmt.Sprintf("%s/%s", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(ttlDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		ephemeral := &models.RoomMessage{
+			RoomID:       room.ID,
+			RoomMemberID: member.ID,
+			UserID:       user.ID,
+			Content:      "the door code is 1234",
+		}
+		err = roomService.CreateMessage(ephemeral, nil)
+		s.NoError(err)
+		s.NotNil(ephemeral.ExpiresAt)
+		s.WithinDuration(time.Now().Add(time.Minute), *ephemeral.ExpiresAt, 5*time.Second)
+
+		// a shorter per-message ttl wins over the room default
+		expired := time.Now().Add(-time.Second)
+		burned := &models.RoomMessage{
+			RoomID:       room.ID,
+			RoomMemberID: member.ID,
+			UserID:       user.ID,
+			Content:      "burn after reading",
+			ExpiresAt:    &expired,
+		}
+		err = roomService.CreateMessage(burned, nil)
+		s.NoError(err)
+		s.Equal(expired, *burned.ExpiresAt)
+
+		n, err := s.services.GetReaperService().PurgeExpiredMessages(context.Background())
+		s.NoError(err)
+		s.Equal(1, n)
+
+		_, err = roomService.GetMessage(burned.ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+
+		_, err = roomService.GetMessage(ephemeral.ID, nil)
+		s.NoError(err)
+
+		ttlDtoJson, err = json.Marshal(map[string]int{"message_ttl": -1})
+		s.NoError(err)
+
+		req, err = http.NewRequest("PATCH", url, bytes.NewBuffer(ttlDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("vote in poll", func() {
+		pollDtoJson, err := json.Marshal(map[string]any{
+			"question": "Lunch?",
+			"options":  []string{"Pizza", "Sushi", "Tacos"},
+		})
+		s.NoError(err)
+
+		url := fmt.Sprintf("%s/%s/polls", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(pollDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.RoomMessage]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		poll := data.Data["message"].Poll
+		s.Require().NotNil(poll)
+		s.Equal("Lunch?", data.Data["message"].Content)
+		s.Len(poll.Options, 3)
+
+		req, err = http.NewRequest("POST", fmt.Sprintf("%s/%s/join", roomBaseUrl, room.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		pollUrl := fmt.Sprintf("%s/%s", url, poll.ID)
+		vote := func(token string, optionIds ...string) *http.Response {
+			voteDtoJson, err := json.Marshal(map[string][]string{"option_ids": optionIds})
+			s.NoError(err)
+
+			req, err := http.NewRequest("POST", pollUrl+"/votes", bytes.NewBuffer(voteDtoJson))
+			s.NoError(err)
+
+			req.Header.Set("Authorization", token)
+			resp, err := client.Do(req)
+			s.NoError(err)
+			return resp
+		}
+
+		resp = vote(members[1].accessToken, poll.Options[0].ID, poll.Options[1].ID)
+		defer resp.Body.Close()
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+		resp = vote(members[0].accessToken, poll.Options[0].ID)
+		defer resp.Body.Close()
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		resp = vote(members[1].accessToken, poll.Options[0].ID)
+		defer resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		resp = vote(accessToken, poll.Options[1].ID)
+		var pollData utils.Response[map[string]models.Poll]
+		err = utils.ReadJSON(resp.Body, &pollData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		results := pollData.Data["poll"]
+		s.Equal(2, results.TotalVoters)
+		s.Equal([]string{poll.Options[1].ID}, results.VotedOptionIDs)
+		s.Equal(1, results.Options[0].VoteCount)
+		s.Equal([]string{members[1].user.ID}, results.Options[0].VoterIDs)
+
+		req, err = http.NewRequest("POST", pollUrl+"/close", nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		req, err = http.NewRequest("POST", pollUrl+"/close", nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		resp = vote(members[1].accessToken, poll.Options[2].ID)
+		defer resp.Body.Close()
+		s.Equal(http.StatusConflict, resp.StatusCode)
+	})
+
+	s.Run("post through incoming webhook", func() {
+		webhookDtoJson, err := json.Marshal(map[string]string{"name": "CI"})
+		s.NoError(err)
+
+		url := fmt.Sprintf("%s/%s/webhooks", roomBaseUrl, room.ID)
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(webhookDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var fullData utils.Response[any]
+		err = utils.ReadJSON(resp.Body, &fullData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusNotAcceptable, resp.StatusCode)
+		s.Equal(services.ErrWebhookRoomFull.Error(), fullData.Message)
+
+		// the integration user needs a member slot of its own
+		roomDtoJson, err := json.Marshal(map[string]int{"max_members": 4})
+		s.NoError(err)
+
+		req, err = http.NewRequest("PATCH", fmt.Sprintf("%s/%s", roomBaseUrl, room.ID), bytes.NewBuffer(roomDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		req, err = http.NewRequest("POST", url, bytes.NewBuffer(webhookDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		req, err = http.NewRequest("POST", url, bytes.NewBuffer(webhookDtoJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[struct {
+			Webhook models.IncomingWebhook `json:"webhook"`
+			URL     string                 `json:"url"`
+		}]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		webhook := data.Data.Webhook
+		s.NotEmpty(webhook.Token)
+		s.Equal(fmt.Sprintf("%s/api/v1/hooks/%s", baseUrl, webhook.Token), data.Data.URL)
+
+		slackJson, err := json.Marshal(map[string]string{"text": "Build <https://ci.example.com/42|#42> passed"})
+		s.NoError(err)
+
+		resp, err = client.Post(data.Data.URL, contentType, bytes.NewBuffer(slackJson))
+		s.NoError(err)
+
+		var messageData utils.Response[map[string]models.RoomMessage]
+		err = utils.ReadJSON(resp.Body, &messageData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		message := messageData.Data["message"]
+		s.Equal(webhook.UserID, message.UserID)
+		s.Equal("Build [#42](https://ci.example.com/42) passed", message.Content)
+
+		resp, err = client.Post(baseUrl+"/api/v1/hooks/not-a-token", contentType, bytes.NewBuffer(slackJson))
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+
+		req, err = http.NewRequest("DELETE", fmt.Sprintf("%s/%s", url, webhook.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		resp, err = client.Post(data.Data.URL, contentType, bytes.NewBuffer(slackJson))
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("export transcript", func() {
+		url := fmt.Sprintf("%s/%s/transcript", roomBaseUrl, room.ID)
+
+		for _, query := range []string{"?format=xml", "?tz=Mars/Olympus_Mons"} {
+			req, err := http.NewRequest("GET", url+query, nil)
+			s.NoError(err)
+
+			req.Header.Set("Authorization", accessToken)
+			resp, err := client.Do(req)
+			s.NoError(err)
+			defer resp.Body.Close()
+
+			s.Equal(http.StatusBadRequest, resp.StatusCode)
+		}
+
+		req, err := http.NewRequest("GET", url+"?format=csv&tz=Asia/Tokyo", nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Equal("text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		s.Contains(resp.Header.Get("Content-Disposition"), "attachment")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		s.NoError(err)
+		s.Require().Greater(len(records), 1)
+		s.Equal("author_name", records[0][3])
+		for _, record := range records[1:] {
+			s.NotEmpty(record[3])
+			s.Contains(record[1], "+0900")
+		}
+
+		req, err = http.NewRequest("GET", url, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		lines := 0
+		dec := json.NewDecoder(resp.Body)
+		for dec.More() {
+			var entry models.TranscriptEntry
+			s.NoError(dec.Decode(&entry))
+			s.NotEmpty(entry.AuthorName)
+			lines++
+		}
+		s.Equal(len(records)-1, lines)
+	})
+
+	s.Run("manage outgoing webhooks", func() {
+		url := fmt.Sprintf("%s/%s/outgoing-webhooks", roomBaseUrl, room.ID)
+
+		badJson, err := json.Marshal(map[string]any{
+			"url":         "ftp://example.com/hook",
+			"event_types": []string{models.EventMessageCreated},
+		})
+		s.NoError(err)
+
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(badJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+		webhookJson, err := json.Marshal(map[string]any{
+			"url":         "https://example.com/hook",
+			"event_types": []string{models.EventMessageCreated, models.EventMemberJoined},
+		})
+		s.NoError(err)
+
+		req, err = http.NewRequest("POST", url, bytes.NewBuffer(webhookJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", members[1].accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		req, err = http.NewRequest("POST", url, bytes.NewBuffer(webhookJson))
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]models.OutgoingWebhook]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		webhook := data.Data["webhook"]
+		s.NotEmpty(webhook.Secret)
+		s.Equal([]string{models.EventMessageCreated, models.EventMemberJoined}, webhook.EventTypes)
+
+		req, err = http.NewRequest("GET", url, nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var listData utils.Response[map[string][]models.OutgoingWebhook]
+		err = utils.ReadJSON(resp.Body, &listData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.Len(listData.Data["webhooks"], 1)
+		s.Empty(listData.Data["webhooks"][0].Secret)
+
+		req, err = http.NewRequest("GET", fmt.Sprintf("%s/%s/deliveries?limit=10", url, webhook.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var deliveryData utils.Response[map[string][]models.WebhookDelivery]
+		err = utils.ReadJSON(resp.Body, &deliveryData)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		s.NotNil(deliveryData.Data["deliveries"])
+
+		req, err = http.NewRequest("DELETE", fmt.Sprintf("%s/%s", url, webhook.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		req, err = http.NewRequest("GET", fmt.Sprintf("%s/%s/deliveries", url, webhook.ID), nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("moderate messages", func() {
+		url := fmt.Sprintf("%s/%s/moderation", roomBaseUrl, room.ID)
+
+		put := func(token string, config map[string]any) *http.Response {
+			configJson, err := json.Marshal(config)
+			s.NoError(err)
+
+			req, err := http.NewRequest("PUT", url, bytes.NewBuffer(configJson))
+			s.NoError(err)
+
+			req.Header.Set("Authorization", token)
+			resp, err := client.Do(req)
+			s.NoError(err)
+			return resp
+		}
+
+		config := map[string]any{
+			"blocklist": map[string]any{"words": []string{"darn"}, "action": "redact"},
+			"regex":     []map[string]any{{"name": "invite spam", "pattern": "(?i)join my server", "action": "reject"}},
+		}
+
+		resp := put(members[1].accessToken, config)
+		defer resp.Body.Close()
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+		resp = put(accessToken, map[string]any{"regex": []map[string]any{{"pattern": "(", "action": "reject"}}})
+		defer resp.Body.Close()
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+		resp = put(accessToken, config)
+		defer resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		roomService := s.services.GetRoomService()
+		member, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: user.ID,
+			RoomID: room.ID,
+		}, nil)
+		s.Require().NoError(err)
+
+		message := &models.RoomMessage{RoomID: room.ID, UserID: user.ID, RoomMemberID: member.ID, Content: "darn it"}
+		s.NoError(roomService.CreateMessage(message, nil))
+		s.Equal("**** it", message.Content)
+
+		rejected := &models.RoomMessage{RoomID: room.ID, UserID: user.ID, RoomMemberID: member.ID, Content: "Join my server!"}
+		err = roomService.CreateMessage(rejected, nil)
+		s.ErrorIs(err, services.ErrMessageRejected)
+		s.Empty(rejected.ID)
+
+		req, err := http.NewRequest("GET", url+"/audit", nil)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string][]models.ModerationAuditEntry]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal(http.StatusOK, resp.StatusCode)
+		entries := data.Data["entries"]
+		s.Require().Len(entries, 2)
+		s.Equal("regex", entries[0].Filter)
+		s.Equal("reject", entries[0].Action)
+		s.Nil(entries[0].MessageID)
+		s.Equal("blocklist", entries[1].Filter)
+		s.Equal("darn it", entries[1].Content)
+		s.Equal(message.ID, *entries[1].MessageID)
+
+		resp = put(accessToken, map[string]any{})
+		defer resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	do := func(method, url, token string, body any) int {
+		var reader io.Reader
+		if body != nil {
+			bodyJson, err := json.Marshal(body)
+			s.NoError(err)
+			reader = bytes.NewBuffer(bodyJson)
+		}
+
+		req, err := http.NewRequest(method, url, reader)
+		s.NoError(err)
+
+		req.Header.Set("Authorization", token)
+		resp, err := client.Do(req)
+		s.NoError(err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	s.Run("manage member roles", func() {
+		moderator := members[0]
+		s.Equal(http.StatusOK, do("POST", fmt.Sprintf("%s/%s/join", roomBaseUrl, room.ID), moderator.accessToken, nil))
+
+		roomService := s.services.GetRoomService()
+		owner, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{UserID: user.ID, RoomID: room.ID}, nil)
+		s.Require().NoError(err)
+		s.Equal(models.RoomRoleOwner, owner.Role)
+		member, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{UserID: moderator.user.ID, RoomID: room.ID}, nil)
+		s.Require().NoError(err)
+
+		ownerUrl := fmt.Sprintf("%s/%s/members/%s", roomBaseUrl, room.ID, owner.ID)
+		memberUrl := fmt.Sprintf("%s/%s/members/%s", roomBaseUrl, room.ID, member.ID)
+
+		s.Equal(http.StatusUnauthorized, do("PATCH", memberUrl, moderator.accessToken, map[string]string{"role": "admin"}))
+		s.Equal(http.StatusBadRequest, do("PATCH", memberUrl, accessToken, map[string]string{"role": "owner"}))
+		s.Equal(http.StatusOK, do("PATCH", memberUrl, accessToken, map[string]string{"role": "moderator"}))
+
+		message := &models.RoomMessage{RoomID: room.ID, UserID: user.ID, RoomMemberID: owner.ID, Content: "Off topic"}
+		s.NoError(roomService.CreateMessage(message, nil))
+
+		s.Equal(http.StatusUnauthorized, do("PATCH", fmt.Sprintf("%s/%s", roomBaseUrl, room.ID), moderator.accessToken, map[string]string{"description": "mine"}))
+		s.Equal(http.StatusOK, do("DELETE", fmt.Sprintf("%s/%s/messages/%s", roomBaseUrl, room.ID, message.ID), moderator.accessToken, nil))
+		s.Equal(http.StatusUnauthorized, do("DELETE", ownerUrl, moderator.accessToken, map[string]string{"reason": "coup"}))
+		s.Equal(http.StatusBadRequest, do("DELETE", memberUrl, accessToken, map[string]string{"reason": " "}))
+		s.Equal(http.StatusOK, do("DELETE", memberUrl, accessToken, map[string]string{"reason": "spamming"}))
+
+		_, err = roomService.GetRoomMember(member.ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+	})
+
+	s.Run("join private room with invite", func() {
+		private := &models.Room{Name: "Insiders", MaxMembers: 5, CreatedBy: user.ID, Visibility: models.RoomVisibilityPrivate}
+		s.Require().NoError(s.services.GetRoomService().CreateRoom(private, nil))
+
+		guest := members[1]
+		s.Equal(http.StatusForbidden, do("POST", fmt.Sprintf("%s/%s/join", roomBaseUrl, private.ID), guest.accessToken, nil))
+		s.Equal(http.StatusUnauthorized, do("POST", fmt.Sprintf("%s/%s/invites", roomBaseUrl, private.ID), guest.accessToken, map[string]any{}))
+
+		req, err := http.NewRequest("GET", roomBaseUrl+"/", nil)
+		s.NoError(err)
+		req.Header.Set("Authorization", guest.accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var listData utils.Response[map[string][]*models.Room]
+		s.NoError(utils.ReadJSON(resp.Body, &listData))
+		resp.Body.Close()
+		for _, listed := range listData.Data["rooms"] {
+			s.NotEqual(private.ID, listed.ID)
+		}
+
+		inviteJson, err := json.Marshal(map[string]any{"max_uses": 1})
+		s.NoError(err)
+		req, err = http.NewRequest("POST", fmt.Sprintf("%s/%s/invites", roomBaseUrl, private.ID), bytes.NewBuffer(inviteJson))
+		s.NoError(err)
+		req.Header.Set("Authorization", accessToken)
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var inviteData utils.Response[map[string]any]
+		s.NoError(utils.ReadJSON(resp.Body, &inviteData))
+		resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		inviteUrl, ok := inviteData.Data["url"].(string)
+		s.Require().True(ok)
+		s.Equal(http.StatusOK, do("POST", inviteUrl, guest.accessToken, nil))
+		s.Equal(http.StatusNotAcceptable, do("POST", inviteUrl, guest.accessToken, nil))
+		s.Equal(http.StatusGone, do("POST", inviteUrl, members[0].accessToken, nil))
+
+		s.NoError(s.services.GetRoomService().DeleteRoom(private.ID, nil))
+	})
+
+	s.Run("approve join requests", func() {
+		restricted := &models.Room{Name: "Applicants", MaxMembers: 2, CreatedBy: user.ID, Visibility: models.RoomVisibilityRestricted}
+		s.Require().NoError(s.services.GetRoomService().CreateRoom(restricted, nil))
+
+		requestsUrl := fmt.Sprintf("%s/%s/join-requests", roomBaseUrl, restricted.ID)
+		first, second := members[0], members[1]
+
+		s.Equal(http.StatusForbidden, do("POST", fmt.Sprintf("%s/%s/join", roomBaseUrl, restricted.ID), first.accessToken, nil))
+		s.Equal(http.StatusOK, do("POST", requestsUrl, first.accessToken, map[string]string{"message": "let me in"}))
+		s.Equal(http.StatusConflict, do("POST", requestsUrl, first.accessToken, map[string]string{}))
+		s.Equal(http.StatusOK, do("POST", requestsUrl, second.accessToken, map[string]string{}))
+		s.Equal(http.StatusUnauthorized, do("GET", requestsUrl, first.accessToken, nil))
+
+		req, err := http.NewRequest("GET", requestsUrl, nil)
+		s.NoError(err)
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string][]*models.RoomJoinRequest]
+		s.NoError(utils.ReadJSON(resp.Body, &data))
+		resp.Body.Close()
+
+		requests := data.Data["requests"]
+		s.Require().Len(requests, 2)
+		s.Equal(first.user.ID, requests[0].UserID)
+		s.Equal("let me in", requests[0].Message)
+		s.Equal(models.JoinRequestPending, requests[0].Status)
+
+		s.Equal(http.StatusOK, do("POST", fmt.Sprintf("%s/%s/approve", requestsUrl, requests[0].ID), accessToken, nil))
+		s.Equal(http.StatusConflict, do("POST", fmt.Sprintf("%s/%s/reject", requestsUrl, requests[0].ID), accessToken, nil))
+		s.Equal(http.StatusUnauthorized, do("POST", fmt.Sprintf("%s/%s/approve", requestsUrl, requests[1].ID), accessToken, nil))
+		s.Equal(http.StatusOK, do("POST", fmt.Sprintf("%s/%s/reject", requestsUrl, requests[1].ID), accessToken, nil))
+
+		member, err := s.services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{UserID: first.user.ID, RoomID: restricted.ID}, nil)
+		s.NoError(err)
+		s.Equal(models.RoomRoleMember, member.Role)
+
+		s.NoError(s.services.GetRoomService().DeleteRoom(restricted.ID, nil))
+	})
+
+	s.Run("ban and mute members", func() {
+		moderated := &models.Room{Name: "Moderated", MaxMembers: 5, CreatedBy: user.ID}
+		roomService := s.services.GetRoomService()
+		s.Require().NoError(roomService.CreateRoom(moderated, nil))
+
+		var mu sync.Mutex
+		var events []*models.Event
+		s.services.GetEventService().Subscribe(func(event *models.Event) {
+			if event.RoomID == moderated.ID {
+				mu.Lock()
+				defer mu.Unlock()
+				events = append(events, event)
+			}
+		})
+
+		target := members[0]
+		roomUrl := fmt.Sprintf("%s/%s", roomBaseUrl, moderated.ID)
+		s.Equal(http.StatusOK, do("POST", roomUrl+"/join", target.accessToken, nil))
+
+		s.Equal(http.StatusBadRequest, do("POST", roomUrl+"/mutes", accessToken, map[string]string{"user_id": target.user.ID}))
+		s.Equal(http.StatusUnauthorized, do("POST", roomUrl+"/mutes", target.accessToken, map[string]string{"user_id": user.ID, "reason": "quiet"}))
+		s.Equal(http.StatusOK, do("POST", roomUrl+"/mutes", accessToken, map[string]string{"user_id": target.user.ID, "reason": "flooding"}))
+
+		member, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{UserID: target.user.ID, RoomID: moderated.ID}, nil)
+		s.Require().NoError(err)
+		message := &models.RoomMessage{RoomID: moderated.ID, UserID: target.user.ID, RoomMemberID: member.ID, Content: "hello?"}
+		s.ErrorIs(roomService.CreateMessage(message, nil), services.ErrMuted)
+
+		s.Equal(http.StatusOK, do("DELETE", fmt.Sprintf("%s/mutes/%s", roomUrl, target.user.ID), accessToken, nil))
+		s.Equal(http.StatusNotFound, do("DELETE", fmt.Sprintf("%s/mutes/%s", roomUrl, target.user.ID), accessToken, nil))
+		s.NoError(roomService.CreateMessage(message, nil))
+
+		s.Equal(http.StatusOK, do("POST", roomUrl+"/bans", accessToken, map[string]string{"user_id": target.user.ID, "reason": "harassment"}))
+		_, err = roomService.GetRoomMember(member.ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+		s.Equal(http.StatusForbidden, do("POST", roomUrl+"/join", target.accessToken, nil))
+
+		req, err := http.NewRequest("GET", roomUrl+"/moderation/actions", nil)
+		s.NoError(err)
+		req.Header.Set("Authorization", accessToken)
+		resp, err := client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string][]*models.RoomMemberAction]
+		s.NoError(utils.ReadJSON(resp.Body, &data))
+		resp.Body.Close()
+
+		actions := data.Data["actions"]
+		s.Require().Len(actions, 3)
+		s.Equal(models.MemberActionBan, actions[0].Action)
+		s.Equal("harassment", actions[0].Reason)
+		s.Equal(user.ID, actions[0].ActorID)
+		s.Equal(models.MemberActionUnmute, actions[1].Action)
+		s.Equal(models.MemberActionMute, actions[2].Action)
+
+		s.Equal(http.StatusOK, do("DELETE", fmt.Sprintf("%s/bans/%s", roomUrl, target.user.ID), accessToken, nil))
+		s.Equal(http.StatusOK, do("POST", roomUrl+"/join", target.accessToken, nil))
+
+		mu.Lock()
+		var notified []string
+		for _, event := range events {
+			if event.UserID == target.user.ID {
+				notified = append(notified, event.Type)
+			}
+		}
+		mu.Unlock()
+		s.Equal([]string{models.EventMemberMuted, models.EventMemberUnmuted, models.EventMemberBanned, models.EventMemberUnbanned}, notified)
+
+		s.NoError(roomService.DeleteRoom(moderated.ID, nil))
+	})
+
 	s.Run("delete room", func() {
 		url := fmt.Sprintf("%s/%s", roomBaseUrl, room.ID)
 		req, err := http.NewRequest("DELETE", url, nil)
@@ -18,6 +18,53 @@ func Routes(r *gin.RouterGroup, s services.Services) {
 	r.DELETE("/:roomId", middlewares.ErrorHandler(h.deleteRoom))
 	r.POST("/:roomId/join", middlewares.ErrorHandler(h.joinRoom))
 	r.POST("/:roomId/leave", middlewares.ErrorHandler(h.leaveRoom))
+	r.GET("/:roomId/join-requests", middlewares.ErrorHandler(h.getJoinRequests))
+	r.POST("/:roomId/join-requests", middlewares.ErrorHandler(h.requestToJoin))
+	r.POST("/:roomId/join-requests/:requestId/approve", middlewares.ErrorHandler(h.approveJoinRequest))
+	r.POST("/:roomId/join-requests/:requestId/reject", middlewares.ErrorHandler(h.rejectJoinRequest))
 	r.GET("/:roomId/members", middlewares.ErrorHandler(h.getRoomMembers))
+	r.PATCH("/:roomId/members/:memberId", middlewares.ErrorHandler(h.updateMemberRole))
+	r.DELETE("/:roomId/members/:memberId", middlewares.ErrorHandler(h.kickMember))
+	r.GET("/:roomId/bans", middlewares.ErrorHandler(h.getBans))
+	r.POST("/:roomId/bans", middlewares.ErrorHandler(h.banUser))
+	r.DELETE("/:roomId/bans/:userId", middlewares.ErrorHandler(h.unbanUser))
+	r.GET("/:roomId/mutes", middlewares.ErrorHandler(h.getMutes))
+	r.POST("/:roomId/mutes", middlewares.ErrorHandler(h.muteMember))
+	r.DELETE("/:roomId/mutes/:userId", middlewares.ErrorHandler(h.unmuteMember))
+	r.GET("/:roomId/invites", middlewares.ErrorHandler(h.getInvites))
+	r.POST("/:roomId/invites", middlewares.ErrorHandler(h.createInvite))
+	r.DELETE("/:roomId/invites/:inviteId", middlewares.ErrorHandler(h.revokeInvite))
 	r.GET("/:roomId/messages", middlewares.ErrorHandler(h.getRoomMessages))
+	r.DELETE("/:roomId/messages/:messageId", middlewares.ErrorHandler(h.deleteMessage))
+	r.GET("/:roomId/transcript", middlewares.ErrorHandler(h.exportTranscript))
+	r.GET("/:roomId/draft", middlewares.ErrorHandler(h.getDraft))
+	r.PUT("/:roomId/draft", middlewares.ErrorHandler(h.saveDraft))
+	r.GET("/:roomId/pins", middlewares.ErrorHandler(h.getPins))
+	r.POST("/:roomId/pins", middlewares.ErrorHandler(h.pinMessage))
+	r.DELETE("/:roomId/pins/:messageId", middlewares.ErrorHandler(h.unpinMessage))
+	r.POST("/:roomId/attachments", middlewares.ErrorHandler(h.uploadAttachment))
+	r.GET("/:roomId/attachments/:attachmentId", middlewares.ErrorHandler(h.downloadAttachment))
+	r.GET("/:roomId/attachments/:attachmentId/thumbnails/:label", middlewares.ErrorHandler(h.downloadThumbnail))
+	r.GET("/:roomId/scheduled-messages", middlewares.ErrorHandler(h.getScheduledMessages))
+	r.POST("/:roomId/scheduled-messages", middlewares.ErrorHandler(h.scheduleMessage))
+	r.GET("/:roomId/scheduled-messages/:scheduledMessageId", middlewares.ErrorHandler(h.getScheduledMessage))
+	r.PATCH("/:roomId/scheduled-messages/:scheduledMessageId", middlewares.ErrorHandler(h.updateScheduledMessage))
+	r.DELETE("/:roomId/scheduled-messages/:scheduledMessageId", middlewares.ErrorHandler(h.deleteScheduledMessage))
+	r.POST("/:roomId/polls", middlewares.ErrorHandler(h.createPoll))
+	r.GET("/:roomId/polls/:pollId", middlewares.ErrorHandler(h.getPoll))
+	r.POST("/:roomId/polls/:pollId/votes", middlewares.ErrorHandler(h.votePoll))
+	r.DELETE("/:roomId/polls/:pollId/votes", middlewares.ErrorHandler(h.retractPollVote))
+	r.POST("/:roomId/polls/:pollId/close", middlewares.ErrorHandler(h.closePoll))
+	r.GET("/:roomId/webhooks", middlewares.ErrorHandler(h.getIncomingWebhooks))
+	r.POST("/:roomId/webhooks", middlewares.ErrorHandler(h.createIncomingWebhook))
+	r.DELETE("/:roomId/webhooks/:webhookId", middlewares.ErrorHandler(h.deleteIncomingWebhook))
+	r.GET("/:roomId/outgoing-webhooks", middlewares.ErrorHandler(h.getOutgoingWebhooks))
+	r.POST("/:roomId/outgoing-webhooks", middlewares.ErrorHandler(h.createOutgoingWebhook))
+	r.DELETE("/:roomId/outgoing-webhooks/:webhookId", middlewares.ErrorHandler(h.deleteOutgoingWebhook))
+	r.GET("/:roomId/outgoing-webhooks/:webhookId/deliveries", middlewares.ErrorHandler(h.getWebhookDeliveries))
+	r.GET("/:roomId/moderation", middlewares.ErrorHandler(h.getModeration))
+	r.PUT("/:roomId/moderation", middlewares.ErrorHandler(h.updateModeration))
+	r.GET("/:roomId/moderation/audit", middlewares.ErrorHandler(h.getModerationAuditLog))
+	r.GET("/:roomId/moderation/actions", middlewares.ErrorHandler(h.getMemberActions))
+	r.PUT("/:roomId/retention", middlewares.ErrorHandler(h.updateRetention))
 }
@@ -0,0 +1,239 @@
+package rooms
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type ScheduleMessageDto struct {
+	Content     string    `json:"content" validate:"required"`
+	ScheduledAt time.Time `json:"scheduled_at" validate:"required"`
+}
+
+type UpdateScheduledMessageDto struct {
+	Content     *string    `json:"content"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+func (h *roomHandler) scheduleMessage(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var scheduleMessageDto ScheduleMessageDto
+	err := c.BindJSON(&scheduleMessageDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionSendMessage); err != nil {
+		return err
+	}
+
+	message := &models.ScheduledMessage{
+		RoomID:      roomId,
+		UserID:      user.ID,
+		Content:     scheduleMessageDto.Content,
+		ScheduledAt: scheduleMessageDto.ScheduledAt,
+	}
+	err = h.services.GetScheduledMessageService().CreateScheduledMessage(message, nil)
+	if err != nil {
+		return scheduledMessageError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message scheduled successfully",
+		Data:    map[string]*models.ScheduledMessage{"scheduled_message": message},
+	})
+	return nil
+}
+
+func (h *roomHandler) getScheduledMessages(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionReadRoom); err != nil {
+		return err
+	}
+
+	messages, err := h.services.GetScheduledMessageService().GetScheduledMessages(roomId, user.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "scheduled messages fetched successfully",
+		Data:    map[string][]*models.ScheduledMessage{"scheduled_messages": messages},
+	})
+	return nil
+}
+
+func (h *roomHandler) getScheduledMessage(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	message, err := h.ownScheduledMessage(c, val.(*models.User))
+	if err != nil {
+		return err
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "scheduled message fetched successfully",
+		Data:    map[string]*models.ScheduledMessage{"scheduled_message": message},
+	})
+	return nil
+}
+
+func (h *roomHandler) updateScheduledMessage(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var updateScheduledMessageDto UpdateScheduledMessageDto
+	err := c.BindJSON(&updateScheduledMessageDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	message, err := h.ownScheduledMessage(c, val.(*models.User))
+	if err != nil {
+		return err
+	}
+
+	if updateScheduledMessageDto.Content != nil {
+		message.Content = *updateScheduledMessageDto.Content
+	}
+	if updateScheduledMessageDto.ScheduledAt != nil {
+		message.ScheduledAt = *updateScheduledMessageDto.ScheduledAt
+	}
+
+	err = h.services.GetScheduledMessageService().UpdateScheduledMessage(message, nil)
+	if err != nil {
+		return scheduledMessageError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "scheduled message updated successfully",
+		Data:    map[string]*models.ScheduledMessage{"scheduled_message": message},
+	})
+	return nil
+}
+
+func (h *roomHandler) deleteScheduledMessage(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	message, err := h.ownScheduledMessage(c, val.(*models.User))
+	if err != nil {
+		return err
+	}
+
+	err = h.services.GetScheduledMessageService().DeleteScheduledMessage(message.ID, nil)
+	if err != nil {
+		return scheduledMessageError(err)
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "scheduled message deleted successfully",
+	})
+	return nil
+}
+
+// ownScheduledMessage returns the scheduled message named in the route if
+// it belongs to the room and was scheduled by user. Other users' scheduled
+// messages are reported as not found.
+func (h *roomHandler) ownScheduledMessage(c *gin.Context, user *models.User) (*models.ScheduledMessage, error) {
+	roomId := c.Params.ByName("roomId")
+	scheduledMessageId := c.Params.ByName("scheduledMessageId")
+
+	message, err := h.services.GetScheduledMessageService().GetScheduledMessage(scheduledMessageId, nil)
+	if err == nil && (message.RoomID != roomId || message.UserID != user.ID) {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return nil, &se
+	}
+
+	return message, nil
+}
+
+func scheduledMessageError(err error) error {
+	se := utils.ServerError{Err: err, Message: err.Error()}
+	switch {
+	case errors.Is(err, services.ErrEmptyMessage), errors.Is(err, services.ErrScheduledInPast),
+		errors.Is(err, services.ErrMessageTooLong):
+		se.StatusCode = http.StatusBadRequest
+	case errors.Is(err, services.ErrScheduledMessageLocked):
+		se.StatusCode = http.StatusConflict
+	default:
+		se.StatusCode = http.StatusInternalServerError
+	}
+	return &se
+}
@@ -0,0 +1,106 @@
+package rooms
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/internal/transcript"
+	"github.com/princecee/go_chat/utils"
+)
+
+// exportTranscript streams a room's full history in the format named by
+// the format query parameter (jsonl, csv or text, default jsonl) with
+// timestamps in the IANA time zone named by tz (default UTC).
+func (h *roomHandler) exportTranscript(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	format := c.DefaultQuery("format", transcript.FormatJSONL)
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    "invalid time zone",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	w, err := transcript.NewWriter(format, c.Writer, loc)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	room, _, err := h.authorize(roomId, user, services.PermissionReadRoom)
+	if err != nil {
+		return err
+	}
+
+	// headers are only set once there is something to send, so an error
+	// fetching the first batch can still be reported as JSON
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		filename := fmt.Sprintf("transcript-%s.%s", room.ID, transcript.Extension(format))
+		c.Header("Content-Type", transcript.ContentType(format))
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Status(http.StatusOK)
+	}
+
+	err = h.services.GetRoomService().ExportTranscript(room.ID, func(entries []*models.TranscriptEntry) error {
+		start()
+		for _, entry := range entries {
+			if err := w.Write(entry); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}, nil)
+	if err != nil {
+		if !started {
+			return &utils.ServerError{
+				Err:        err,
+				Message:    err.Error(),
+				StatusCode: http.StatusInternalServerError,
+			}
+		}
+
+		// the status line is already sent, so all that is left is to cut
+		// the transcript short
+		log.Printf("failed to export transcript of room %s: %v", room.ID, err)
+		c.Abort()
+		return nil
+	}
+
+	start()
+	if err := w.Flush(); err != nil {
+		log.Printf("failed to export transcript of room %s: %v", room.ID, err)
+	}
+	return nil
+}
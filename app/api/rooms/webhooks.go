@@ -0,0 +1,184 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+type CreateIncomingWebhookDto struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+func (h *roomHandler) createIncomingWebhook(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	var createIncomingWebhookDto CreateIncomingWebhookDto
+	err := c.BindJSON(&createIncomingWebhookDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	webhook := &models.IncomingWebhook{
+		RoomID:    roomId,
+		Name:      createIncomingWebhookDto.Name,
+		CreatedBy: user.ID,
+	}
+
+	tx, _ := h.services.GetDB().Begin(context.Background())
+	defer tx.Rollback(context.Background())
+
+	err = h.services.GetIncomingWebhookService().CreateIncomingWebhook(webhook, tx)
+	if err != nil {
+		se := utils.ServerError{Err: err, Message: err.Error()}
+		switch {
+		case errors.Is(err, services.ErrInvalidWebhookName):
+			se.StatusCode = http.StatusBadRequest
+		case errors.Is(err, services.ErrWebhookRoomFull):
+			se.StatusCode = http.StatusNotAcceptable
+		default:
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhook created successfully",
+		Data: map[string]any{
+			"webhook": webhook,
+			"url":     incomingWebhookURL(c, webhook.Token),
+		},
+	})
+	return nil
+}
+
+func (h *roomHandler) getIncomingWebhooks(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	webhooks, err := h.services.GetIncomingWebhookService().GetIncomingWebhooks(roomId, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhooks fetched successfully",
+		Data:    map[string][]*models.IncomingWebhook{"webhooks": webhooks},
+	})
+	return nil
+}
+
+func (h *roomHandler) deleteIncomingWebhook(c *gin.Context) error {
+	roomId := c.Params.ByName("roomId")
+	webhookId := c.Params.ByName("webhookId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	if _, _, err := h.authorize(roomId, user, services.PermissionUpdateRoom); err != nil {
+		return err
+	}
+
+	webhookService := h.services.GetIncomingWebhookService()
+	webhook, err := webhookService.GetIncomingWebhook(webhookId, nil)
+	if err == nil && webhook.RoomID != roomId {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	err = webhookService.DeleteIncomingWebhook(webhook.ID, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "webhook deleted successfully",
+	})
+	return nil
+}
+
+// incomingWebhookURL returns the public URL external systems post to, as
+// reached through the host the request was made to.
+func incomingWebhookURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/api/v1/hooks/%s", scheme, c.Request.Host, token)
+}
@@ -0,0 +1,205 @@
+package saved
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+const (
+	defaultSavedLimit = 50
+	maxSavedLimit     = 200
+)
+
+type savedHandler struct {
+	services services.Services
+}
+
+type SaveMessageDto struct {
+	Note string `json:"note"`
+}
+
+// getSavedMessages lists the user's saved messages with the rooms they were
+// posted in, most recently saved first. Pass next_cursor back as before to
+// get the next page.
+func (h *savedHandler) getSavedMessages(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	params := repositories.GetSavedMessagesParams{UserID: user.ID, Limit: defaultSavedLimit}
+
+	var err error
+	if before := c.Query("before"); before != "" {
+		params.Before, err = utils.DecodeCursor(before)
+		if err != nil {
+			return &utils.ServerError{
+				Err:        err,
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil || params.Limit <= 0 {
+			return &utils.ServerError{
+				Err:        errors.New("invalid limit"),
+				Message:    "limit must be a positive integer",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		params.Limit = min(params.Limit, maxSavedLimit)
+	}
+
+	page, err := h.services.GetSavedMessageService().GetSavedMessages(params, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	messages := []*models.RoomMessage{}
+	for _, saved := range page.Items {
+		messages = append(messages, saved.Message)
+	}
+
+	err = h.services.GetAttachmentService().LoadMessageAttachments(messages, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "saved messages fetched successfully",
+		Data:    map[string][]*models.SavedMessage{"saved": page.Items},
+		Meta:    &utils.ResponseMeta{NextCursor: page.NextCursor},
+	})
+	return nil
+}
+
+// saveMessage bookmarks a message, or replaces the note on one already
+// saved.
+func (h *savedHandler) saveMessage(c *gin.Context) error {
+	messageId := c.Params.ByName("messageId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if _, err := uuid.Parse(messageId); err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    utils.ErrNotFound.Error(),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	var saveMessageDto SaveMessageDto
+	err := c.BindJSON(&saveMessageDto)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	user := val.(*models.User)
+	saved := &models.SavedMessage{
+		UserID:    user.ID,
+		MessageID: messageId,
+		Note:      saveMessageDto.Note,
+	}
+	err = h.services.GetSavedMessageService().SaveMessage(saved, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		case errors.Is(err, services.ErrSavedNoteTooLong):
+			se.Message = err.Error()
+			se.StatusCode = http.StatusBadRequest
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message saved successfully",
+		Data:    map[string]*models.SavedMessage{"saved": saved},
+	})
+	return nil
+}
+
+func (h *savedHandler) unsaveMessage(c *gin.Context) error {
+	messageId := c.Params.ByName("messageId")
+
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if _, err := uuid.Parse(messageId); err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    utils.ErrNotFound.Error(),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	user := val.(*models.User)
+	err := h.services.GetSavedMessageService().UnsaveMessage(user.ID, messageId, nil)
+	if err != nil {
+		se := utils.ServerError{Err: err}
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			se.Message = utils.ErrNotFound.Error()
+			se.StatusCode = http.StatusNotFound
+		default:
+			se.Message = err.Error()
+			se.StatusCode = http.StatusInternalServerError
+		}
+		return &se
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "message unsaved successfully",
+	})
+	return nil
+}
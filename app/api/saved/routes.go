@@ -0,0 +1,17 @@
+package saved
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := savedHandler{services: s}
+
+	r.Use(middlewares.Authenticator(s))
+
+	r.GET("/", middlewares.ErrorHandler(h.getSavedMessages))
+	r.PUT("/:messageId", middlewares.ErrorHandler(h.saveMessage))
+	r.DELETE("/:messageId", middlewares.ErrorHandler(h.unsaveMessage))
+}
@@ -0,0 +1,137 @@
+package saved
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/app/api/apitest"
+	"github.com/princecee/go_chat/app/api/auth"
+	"github.com/princecee/go_chat/app/api/rooms"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type savedTestSuite struct {
+	apitest.Suite
+}
+
+func (s *savedTestSuite) SetupSuite() {
+	s.Serve(func(r *gin.Engine, services services.Services) {
+		auth.Routes(r.Group("/api/v1/auth"), services)
+		rooms.Routes(r.Group("/api/v1/rooms"), services)
+		Routes(r.Group("/api/v1/saved"), services)
+	})
+}
+
+func (s *savedTestSuite) TearDownSuite() {
+	s.Close("auths", "saved_messages", "room_messages", "room_members", "rooms", "users")
+}
+
+func (s *savedTestSuite) TestSavedMessagesHandler() {
+	ada, adaToken := s.SignUp(map[string]string{
+		"first_name": "Ada",
+		"last_name":  "Obi",
+		"email":      "ada@gmail.com",
+		"password":   "password",
+	})
+	_, bolaToken := s.SignUp(map[string]string{
+		"first_name": "Bola",
+		"last_name":  "Ade",
+		"email":      "bola@gmail.com",
+		"password":   "password",
+	})
+
+	var room utils.Response[map[string]models.Room]
+	status := s.Request("POST", "/api/v1/rooms", adaToken, map[string]any{"name": "Reading", "description": "Book club", "max_members": 10}, &room)
+	s.Equal(http.StatusOK, status)
+	roomId := room.Data["room"].ID
+
+	member, err := s.Services.GetRoomService().GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: ada.ID,
+		RoomID: roomId,
+	}, nil)
+	s.NoError(err)
+
+	messages := []*models.RoomMessage{}
+	for _, content := range []string{"First chapter", "Second chapter"} {
+		message := &models.RoomMessage{RoomID: roomId, RoomMemberID: member.ID, UserID: ada.ID, Content: content}
+		s.NoError(s.Services.GetRoomService().CreateMessage(message, nil))
+		messages = append(messages, message)
+	}
+
+	s.Run("save messages", func() {
+		for _, message := range messages {
+			var data utils.Response[map[string]models.SavedMessage]
+			status := s.Request("PUT", "/api/v1/saved/"+message.ID, adaToken, map[string]string{"note": "read later"}, &data)
+			s.Equal(http.StatusOK, status)
+			s.Equal(message.ID, data.Data["saved"].MessageID)
+		}
+
+		var data utils.Response[map[string]models.SavedMessage]
+		status := s.Request("PUT", "/api/v1/saved/"+messages[0].ID, adaToken, map[string]string{"note": "the good bit"}, &data)
+		s.Equal(http.StatusOK, status)
+		s.Equal("the good bit", data.Data["saved"].Note)
+	})
+
+	s.Run("cannot save messages in other rooms", func() {
+		status := s.Request("PUT", "/api/v1/saved/"+messages[0].ID, bolaToken, map[string]string{}, nil)
+		s.Equal(http.StatusNotFound, status)
+	})
+
+	s.Run("list saved messages", func() {
+		var data utils.Response[map[string][]models.SavedMessage]
+		status := s.Request("GET", "/api/v1/saved?limit=1", adaToken, nil, &data)
+		s.Equal(http.StatusOK, status)
+		s.Require().Len(data.Data["saved"], 1)
+		s.Equal(messages[1].ID, data.Data["saved"][0].MessageID)
+		s.Equal("Second chapter", data.Data["saved"][0].Message.Content)
+		s.Equal("Reading", data.Data["saved"][0].Room.Name)
+		s.NotEmpty(data.Meta.NextCursor)
+
+		var next utils.Response[map[string][]models.SavedMessage]
+		status = s.Request("GET", "/api/v1/saved?limit=1&before="+data.Meta.NextCursor, adaToken, nil, &next)
+		s.Equal(http.StatusOK, status)
+		s.Require().Len(next.Data["saved"], 1)
+		s.Equal(messages[0].ID, next.Data["saved"][0].MessageID)
+		s.Equal("the good bit", next.Data["saved"][0].Note)
+		s.Nil(next.Meta)
+	})
+
+	s.Run("hidden after leaving room", func() {
+		status := s.Request("POST", fmt.Sprintf("/api/v1/rooms/%s/join", roomId), bolaToken, nil, nil)
+		s.Equal(http.StatusOK, status)
+
+		status = s.Request("PUT", "/api/v1/saved/"+messages[0].ID, bolaToken, map[string]string{}, nil)
+		s.Equal(http.StatusOK, status)
+
+		status = s.Request("POST", fmt.Sprintf("/api/v1/rooms/%s/leave", roomId), bolaToken, nil, nil)
+		s.Equal(http.StatusOK, status)
+
+		var data utils.Response[map[string][]models.SavedMessage]
+		status = s.Request("GET", "/api/v1/saved", bolaToken, nil, &data)
+		s.Equal(http.StatusOK, status)
+		s.Empty(data.Data["saved"])
+	})
+
+	s.Run("unsave message", func() {
+		status := s.Request("DELETE", "/api/v1/saved/"+messages[1].ID, adaToken, nil, nil)
+		s.Equal(http.StatusOK, status)
+
+		status = s.Request("DELETE", "/api/v1/saved/"+messages[1].ID, adaToken, nil, nil)
+		s.Equal(http.StatusNotFound, status)
+
+		var data utils.Response[map[string][]models.SavedMessage]
+		status = s.Request("GET", "/api/v1/saved", adaToken, nil, &data)
+		s.Equal(http.StatusOK, status)
+		s.Len(data.Data["saved"], 1)
+	})
+}
+
+func TestSavedMessagesHandler(t *testing.T) {
+	suite.Run(t, new(savedTestSuite))
+}
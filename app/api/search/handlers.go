@@ -0,0 +1,130 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type searchHandler struct {
+	services services.Services
+}
+
+func (h *searchHandler) searchMessages(c *gin.Context) error {
+	val, ok := c.Get("user")
+	if !ok {
+		return &utils.ServerError{
+			Message:    utils.ErrInternalServer.Error(),
+			Err:        utils.ErrInternalServer,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	user := val.(*models.User)
+	params, page, err := getSearchMessagesParams(c, user.ID)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	results, err := h.services.GetSearchService().SearchMessages(*params, nil)
+	if err != nil {
+		return &utils.ServerError{
+			Err:        err,
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseGeneric{
+		Success: true,
+		Message: "messages searched successfully",
+		Data: map[string][]*models.MessageSearchResult{
+			"results": results,
+		},
+		Meta: &utils.ResponseMeta{Page: page},
+	})
+	return nil
+}
+
+// getSearchMessagesParams reads the search text and filters from the query
+// string. Searches are always scoped to the rooms memberID belongs to.
+func getSearchMessagesParams(c *gin.Context, memberID string) (*repositories.SearchMessagesParams, int, error) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return nil, 0, errors.New("q is required")
+	}
+
+	params := &repositories.SearchMessagesParams{
+		Query:    query,
+		MemberID: memberID,
+		Limit:    defaultSearchLimit,
+	}
+
+	if roomID := c.Query("room_id"); roomID != "" {
+		if _, err := uuid.Parse(roomID); err != nil {
+			return nil, 0, errors.New("invalid room_id")
+		}
+		params.RoomID = &roomID
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, 0, errors.New("invalid user_id")
+		}
+		params.UserID = &userID
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, 0, errors.New("from must be an RFC3339 timestamp")
+		}
+		params.CreatedAfter = &t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, 0, errors.New("to must be an RFC3339 timestamp")
+		}
+		params.CreatedBefore = &t
+	}
+
+	var err error
+	if limit := c.Query("limit"); limit != "" {
+		params.Limit, err = strconv.Atoi(limit)
+		if err != nil || params.Limit <= 0 {
+			return nil, 0, errors.New("limit must be a positive integer")
+		}
+		params.Limit = min(params.Limit, maxSearchLimit)
+	}
+
+	page := 1
+	if p := c.Query("page"); p != "" {
+		page, err = strconv.Atoi(p)
+		if err != nil || page <= 0 {
+			return nil, 0, errors.New("page must be a positive integer")
+		}
+	}
+	params.Offset = (page - 1) * params.Limit
+
+	return params, page, nil
+}
@@ -0,0 +1,15 @@
+package search
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+func Routes(r *gin.RouterGroup, s services.Services) {
+	h := searchHandler{services: s}
+
+	r.Use(middlewares.Authenticator(s))
+
+	r.GET("/messages", middlewares.ErrorHandler(h.searchMessages))
+}
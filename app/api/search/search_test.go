@@ -0,0 +1,133 @@
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/princecee/go_chat/app/api/apitest"
+	"github.com/princecee/go_chat/app/api/auth"
+	"github.com/princecee/go_chat/app/api/rooms"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type searchTestSuite struct {
+	apitest.Suite
+}
+
+func (s *searchTestSuite) SetupSuite() {
+	s.Serve(func(r *gin.Engine, services services.Services) {
+		auth.Routes(r.Group("/api/v1/auth"), services)
+		rooms.Routes(r.Group("/api/v1/rooms"), services)
+		Routes(r.Group("/api/v1/search"), services)
+	})
+}
+
+func (s *searchTestSuite) TearDownSuite() {
+	s.Close("auths", "room_messages", "room_members", "rooms", "users")
+}
+
+func (s *searchTestSuite) search(accessToken string, query url.Values) (int, utils.Response[map[string][]models.MessageSearchResult]) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/search/messages?%s", s.Server.URL, query.Encode()), nil)
+	s.NoError(err)
+
+	req.Header.Set("Authorization", accessToken)
+	resp, err := s.Server.Client().Do(req)
+	s.NoError(err)
+	defer resp.Body.Close()
+
+	var data utils.Response[map[string][]models.MessageSearchResult]
+	err = utils.ReadJSON(resp.Body, &data)
+	s.NoError(err)
+
+	return resp.StatusCode, data
+}
+
+func (s *searchTestSuite) TestSearchHandler() {
+	user, accessToken := s.SignUp(map[string]string{
+		"first_name": "Chimezie",
+		"last_name":  "Edeh",
+		"email":      "princecee15@gmail.com",
+		"password":   "password",
+	})
+	_, outsiderToken := s.SignUp(map[string]string{
+		"first_name": "Yung",
+		"last_name":  "Yu",
+		"email":      "yungyu@gmail.com",
+		"password":   "password",
+	})
+
+	roomService := s.Services.GetRoomService()
+	room := &models.Room{
+		Name:        "Deployments",
+		Description: "release coordination",
+		MaxMembers:  5,
+		CreatedBy:   user.ID,
+	}
+	err := roomService.CreateRoom(room, nil)
+	s.NoError(err)
+
+	member, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: user.ID,
+		RoomID: room.ID,
+	}, nil)
+	s.NoError(err)
+
+	for _, content := range []string{
+		"the database migration <script> failed on staging",
+		"lunch is at noon",
+	} {
+		err = roomService.CreateMessage(&models.RoomMessage{
+			RoomID:       room.ID,
+			RoomMemberID: member.ID,
+			UserID:       user.ID,
+			Content:      content,
+		}, nil)
+		s.NoError(err)
+	}
+
+	s.Run("search without query", func() {
+		statusCode, data := s.search(accessToken, url.Values{})
+		s.Equal(http.StatusBadRequest, statusCode)
+		s.Equal("q is required", data.Message)
+	})
+
+	s.Run("search as member", func() {
+		statusCode, data := s.search(accessToken, url.Values{"q": {"migrations"}})
+		s.Equal(http.StatusOK, statusCode)
+		s.Equal("messages searched successfully", data.Message)
+		s.Len(data.Data["results"], 1)
+
+		result := data.Data["results"][0]
+		s.Equal(room.ID, result.RoomID)
+		s.Contains(result.Snippet, "<mark>migration</mark>")
+		s.NotContains(result.Snippet, "<script>")
+	})
+
+	s.Run("search with filters", func() {
+		statusCode, data := s.search(accessToken, url.Values{
+			"q":       {"migration"},
+			"user_id": {user.ID},
+			"from":    {"2000-01-01T00:00:00Z"},
+			"to":      {"2000-01-02T00:00:00Z"},
+		})
+		s.Equal(http.StatusOK, statusCode)
+		s.Len(data.Data["results"], 0)
+	})
+
+	s.Run("search as non member", func() {
+		statusCode, data := s.search(outsiderToken, url.Values{"q": {"migration"}})
+		s.Equal(http.StatusOK, statusCode)
+		s.Len(data.Data["results"], 0)
+	})
+}
+
+func TestSearchHandler(t *testing.T) {
+	suite.Run(t, new(searchTestSuite))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/princecee/go_chat/app/api"
 	"github.com/princecee/go_chat/app/websocket"
+	"github.com/princecee/go_chat/internal/services"
 )
 
 func StartApp(conn *pgxpool.Pool) {
@@ -33,6 +34,15 @@ func StartApp(conn *pgxpool.Pool) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// background workers
+	s := services.New(conn)
+	go s.GetAttachmentService().ProcessAttachments(ctx)
+	go s.GetUnfurlService().ProcessMessages(ctx)
+	go s.GetScheduledMessageService().DispatchScheduledMessages(ctx)
+	go s.GetReaperService().ReapExpiredMessages(ctx)
+	go s.GetReaperService().EnforceRetention(ctx)
+	go s.GetOutgoingWebhookService().DeliverWebhooks(ctx)
+
 	srv := http.Server{
 		Handler: r.Handler(),
 		Addr:    fmt.Sprintf(":%s", port),
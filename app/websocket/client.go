@@ -1,24 +1,69 @@
 package websocket
 
 import (
+	"errors"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
-	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/commands"
 	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+const (
+	// writeWait is how long a single write may take before the peer is
+	// considered gone
+	writeWait = 10 * time.Second
+	// outboxSize is how many writes may wait for a peer before it is
+	// considered too slow to keep
+	outboxSize = 256
+	// maxFrameSize caps what a peer may send in one frame, comfortably
+	// above the longest message the room service accepts
+	maxFrameSize = 64 << 10
+)
+
+var (
+	errClientClosed = errors.New("websocket client closed")
+	errClientSlow   = errors.New("websocket client too slow, disconnected")
 )
 
 type wsClient struct {
-	conn    *websocket.Conn
-	user    *models.User
+	conn *websocket.Conn
+	user *models.User
+	// session tells apart the connections of a user signed in on several
+	// devices
+	session string
 	handler *wsHandler
-	mu      *sync.Mutex
+	// outbox queues writes for writePump, so publishing an event never
+	// waits on a peer's network
+	outbox    chan any
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWSClient(conn *websocket.Conn, user *models.User, session string, handler *wsHandler) *wsClient {
+	conn.SetReadLimit(maxFrameSize)
+	return &wsClient{
+		conn:    conn,
+		user:    user,
+		session: session,
+		handler: handler,
+		outbox:  make(chan any, outboxSize),
+		done:    make(chan struct{}),
+	}
 }
 
+// Message is what clients send to post in a room. Posted messages reach the
+// room as message.created events, the same as messages posted through the
+// API.
 type Message struct {
 	RoomID  string `json:"room_id"`
 	Content string `json:"content"`
 	UserID  string `json:"user_id,omitempty"`
+	// TTL asks for the message to expire after this many seconds
+	TTL int `json:"ttl,omitempty"`
 }
 
 func (client *wsClient) run(errChan chan<- error) {
@@ -32,61 +77,147 @@ func (client *wsClient) run(errChan chan<- error) {
 			break
 		}
 
-		roomService := client.handler.services.GetRoomService()
-		roomMember, err := roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
-			UserID: client.user.ID,
-			RoomID: data.RoomID,
-		}, nil)
-		if err != nil {
-			errChan <- err
-			break
-		}
-
-		message := &models.RoomMessage{
-			RoomID:       data.RoomID,
-			UserID:       client.user.ID,
-			RoomMemberID: roomMember.ID,
-			Content:      data.Content,
+		// posting goes through the same role checks as the API; users who
+		// may not post are told so without ending the connection
+		room, roomMember, err := client.handler.services.GetAuthorizationService().Authorize(data.RoomID, client.user.ID, services.PermissionSendMessage, nil)
+		switch {
+		case errors.Is(err, services.ErrPermissionDenied):
+			err = client.reject(data.RoomID, err)
+		case err != nil:
+		default:
+			if name, args, ok := commands.Parse(data.Content); ok {
+				err = client.runCommand(room, roomMember, data, name, args)
+			} else {
+				err = client.send(roomMember, data, data.Content)
+			}
 		}
-		err = roomService.CreateMessage(message, nil)
 		if err != nil {
 			errChan <- err
 			break
 		}
+	}
+}
 
-		client.mu.Lock()
-		err = client.broadcast(client.user, data.RoomID, data.Content)
+// send posts content to the room as a message from the client's user.
+// Messages the room refuses are reported back to the client without ending
+// the connection.
+func (client *wsClient) send(roomMember *models.RoomMember, data *Message, content string) error {
+	message := &models.RoomMessage{
+		RoomID:       data.RoomID,
+		UserID:       client.user.ID,
+		RoomMemberID: roomMember.ID,
+		Content:      content,
+	}
+	if data.TTL > 0 {
+		expiresAt := time.Now().Add(time.Duration(data.TTL) * time.Second)
+		message.ExpiresAt = &expiresAt
+	}
+	err := client.handler.services.GetRoomService().CreateMessage(message, nil)
+	if errors.Is(err, services.ErrBlocked) || errors.Is(err, services.ErrMuted) || errors.Is(err, services.ErrMessageRejected) ||
+		errors.Is(err, services.ErrMessageTooLong) {
+		return client.reject(data.RoomID, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	client.handler.services.GetUnfurlService().EnqueueMessage(message)
+	client.handler.services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMessageCreated,
+		RoomID: message.RoomID,
+		Data:   message,
+	})
+	client.handler.services.GetMessageDraftService().ClearSentDraft(message, client.session)
+	return nil
+}
+
+// runCommand executes a slash command instead of posting it. Replies and
+// failures are written back to this client only; only errors writing to the
+// room or the client end the connection.
+func (client *wsClient) runCommand(room *models.Room, roomMember *models.RoomMember, data *Message, name, args string) error {
+	result, err := client.handler.commands.Execute(name, &commands.Context{
+		Room:     room,
+		Member:   roomMember,
+		User:     client.user,
+		Services: client.handler.services,
+		Args:     args,
+	})
+	if err != nil {
+		return client.reply(data.RoomID, &models.CommandResponse{Command: name, Error: err.Error()})
+	}
+
+	if result.Post != "" {
+		err = client.send(roomMember, data, result.Post)
 		if err != nil {
-			errChan <- err
-			break
+			return err
 		}
-		client.mu.Unlock()
 	}
+
+	if result.Reply != "" {
+		return client.reply(data.RoomID, &models.CommandResponse{Command: name, Text: result.Reply})
+	}
+	return nil
+}
+
+func (client *wsClient) reply(roomID string, response *models.CommandResponse) error {
+	return client.write(&models.Event{
+		Type:   models.EventCommandResponse,
+		RoomID: roomID,
+		Data:   response,
+	})
+}
+
+func (client *wsClient) reject(roomID string, err error) error {
+	return client.write(&models.Event{
+		Type:   models.EventMessageRejected,
+		RoomID: roomID,
+		Data:   &models.MessageRejected{Error: err.Error()},
+	})
 }
 
-func (client *wsClient) broadcast(user *models.User, roomID, content string) error {
-	message := Message{
-		RoomID:  roomID,
-		UserID:  user.ID,
-		Content: content,
+// write queues v to be sent to the client. It never blocks: a client whose
+// queue is full is disconnected rather than left to hold up the sender.
+func (client *wsClient) write(v any) error {
+	select {
+	case <-client.done:
+		return errClientClosed
+	default:
 	}
 
-	roomService := client.handler.services.GetRoomService()
-	members, err := roomService.GetRoomMembers(repositories.GetRoomMembersParams{
-		RoomID: &roomID,
-	}, nil)
-	if err != nil {
-		return err
+	select {
+	case client.outbox <- v:
+		return nil
+	default:
+		client.close()
+		return errClientSlow
 	}
+}
 
-	for _, member := range members {
-		peer, ok := client.handler.clients[member.UserID]
-		if ok {
-			err = peer.conn.WriteJSON(message)
-			if err != nil {
-				return err
+// writePump sends queued writes to the client until it is closed.
+// gorilla/websocket connections support a single concurrent writer, so
+// this is the only place that writes to the connection.
+func (client *wsClient) writePump() {
+	for {
+		select {
+		case v := <-client.outbox:
+			client.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := client.conn.WriteJSON(v); err != nil {
+				log.Println(err)
+				client.close()
+				return
 			}
+		case <-client.done:
+			return
 		}
 	}
-	return nil
+}
+
+// close disconnects the client, which also ends its read loop.
+func (client *wsClient) close() {
+	client.closeOnce.Do(func() {
+		close(client.done)
+		if err := client.conn.Close(); err != nil {
+			log.Println(err)
+		}
+	})
 }
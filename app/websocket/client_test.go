@@ -0,0 +1,38 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowClientIsDisconnected(t *testing.T) {
+	clients := make(chan *wsClient, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		clients <- newWSClient(conn, &models.User{ID: "user"}, "session", nil)
+	}))
+	defer server.Close()
+
+	peer, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer peer.Close()
+
+	// writePump is not running, so nothing drains the outbox
+	client := <-clients
+	for i := 0; i < outboxSize; i++ {
+		require.NoError(t, client.write(i))
+	}
+	assert.ErrorIs(t, client.write("one too many"), errClientSlow)
+	assert.ErrorIs(t, client.write("after close"), errClientClosed)
+
+	_, _, err = peer.ReadMessage()
+	assert.Error(t, err)
+}
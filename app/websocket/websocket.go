@@ -3,13 +3,17 @@ package websocket
 import (
 	"log"
 	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/commands"
 	"github.com/princecee/go_chat/internal/db/repositories"
 	"github.com/princecee/go_chat/internal/middlewares"
+	"github.com/princecee/go_chat/internal/models"
 	"github.com/princecee/go_chat/internal/services"
 	"github.com/princecee/go_chat/utils"
 )
@@ -25,16 +29,94 @@ var upgrader = websocket.Upgrader{
 
 type wsHandler struct {
 	services services.Services
-	clients  map[string]*wsClient
+	commands *commands.Registry
+	// clients holds every connected session, keyed by user ID
+	clients map[string][]*wsClient
+	mu      sync.RWMutex
 }
 
 func SetupWebsocket(r *gin.Engine, db *pgxpool.Pool) {
 	services := services.New(db)
-	h := wsHandler{services: services}
+	h := &wsHandler{
+		services: services,
+		commands: commands.Default(),
+		clients:  map[string][]*wsClient{},
+	}
+
+	services.GetEventService().Subscribe(h.dispatch)
 
 	r.GET("/ws", middlewares.Authenticator(services), h.handleHandshake)
 }
 
+// getClients returns the user's connected sessions.
+func (h *wsHandler) getClients(userID string) []*wsClient {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return slices.Clone(h.clients[userID])
+}
+
+func (h *wsHandler) addClient(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clients[client.user.ID] = append(h.clients[client.user.ID], client)
+}
+
+func (h *wsHandler) removeClient(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := slices.DeleteFunc(h.clients[client.user.ID], func(c *wsClient) bool {
+		return c == client
+	})
+	if len(clients) == 0 {
+		delete(h.clients, client.user.ID)
+		return
+	}
+	h.clients[client.user.ID] = clients
+}
+
+// sendToRoom writes v to every connected member of the room. A peer that
+// cannot take the write is disconnected without affecting the others.
+func (h *wsHandler) sendToRoom(roomID string, v any) error {
+	members, err := h.services.GetRoomService().GetRoomMembers(repositories.GetRoomMembersParams{
+		RoomID: &roomID,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		for _, peer := range h.getClients(member.UserID) {
+			if err := peer.write(v); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+	return nil
+}
+
+// dispatch delivers events published through the event service to the
+// connected clients they are addressed to.
+func (h *wsHandler) dispatch(event *models.Event) {
+	if event.UserID != "" {
+		for _, client := range h.getClients(event.UserID) {
+			if event.SkipSession != "" && client.session == event.SkipSession {
+				continue
+			}
+			if err := client.write(event); err != nil {
+				log.Println(err)
+			}
+		}
+		return
+	}
+
+	if err := h.sendToRoom(event.RoomID, event); err != nil {
+		log.Println(err)
+	}
+}
+
 func (h *wsHandler) handleHandshake(c *gin.Context) {
 	payload, err := utils.GetTokenFromRequest(c.Request)
 	if err != nil {
@@ -65,25 +147,21 @@ func (h *wsHandler) handleHandshake(c *gin.Context) {
 		return
 	}
 
-	if h.clients == nil {
-		h.clients = map[string]*wsClient{}
+	// clients name their session so events they caused through the API can
+	// be kept from echoing back to them
+	session := c.Query("session_id")
+	if session == "" {
+		session = uuid.NewString()
 	}
 
-	client := &wsClient{
-		conn:    conn,
-		user:    user,
-		handler: h,
-		mu:      &sync.Mutex{},
-	}
-	h.clients[user.ID] = client
+	client := newWSClient(conn, user, session, h)
+	h.addClient(client)
+	defer h.removeClient(client)
 
 	errChan := make(chan error)
+	go client.writePump()
 	go client.run(errChan)
 
-	err = <-errChan
-	if err != nil {
-		if closeErr := conn.Close(); closeErr != nil {
-			log.Println(closeErr)
-		}
-	}
+	<-errChan
+	client.close()
 }
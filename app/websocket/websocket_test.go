@@ -68,6 +68,7 @@ func (s *WebsocketTestSuite) TearDownSuite() {
 
 	teardownQuery := `
 		DELETE FROM auths;
+		DELETE FROM message_drafts;
 		DELETE FROM room_messages;
 		DELETE FROM room_members;
 		DELETE FROM rooms;
@@ -82,6 +83,12 @@ func (s *WebsocketTestSuite) TearDownSuite() {
 	}
 }
 
+// messageEvent is a message.created event as clients receive it.
+type messageEvent struct {
+	Type string             `json:"type"`
+	Data models.RoomMessage `json:"data"`
+}
+
 func (s *WebsocketTestSuite) TestWebsocket() {
 	baseUrl := s.server.URL
 	client := s.server.Client()
@@ -153,16 +160,162 @@ func (s *WebsocketTestSuite) TestWebsocket() {
 		senderMsg := Message{
 			RoomID:  roomID,
 			UserID:  s.sender.user.ID,
-			Content: "Hello boy! How are you",
+			Content: "Hello boy! How are **you**",
 		}
 		err = senderConn.WriteJSON(&senderMsg)
 		s.NoError(err)
 
-		var receiverMsg Message
-		err = receiverConn.ReadJSON(&receiverMsg)
+		var event messageEvent
+		err = receiverConn.ReadJSON(&event)
+		s.NoError(err)
+
+		s.Equal(models.EventMessageCreated, event.Type)
+		s.Equal(senderMsg.Content, event.Data.Content)
+		s.Equal("Hello boy! How are <strong>you</strong>", event.Data.ContentHTML)
+		s.NotEmpty(event.Data.ID)
+	})
+
+	s.Run("run slash commands", func() {
+		url := fmt.Sprintf("ws://%s/ws", s.server.URL[7:])
+		sheaders, rheaders := http.Header{}, http.Header{}
+		sheaders.Add("Authorization", s.sender.accessToken)
+		rheaders.Add("Authorization", s.receiver.accessToken)
+
+		senderConn, _, err := websocket.DefaultDialer.Dial(url, sheaders)
+		s.NoError(err)
+
+		receiverConn, _, err := websocket.DefaultDialer.Dial(url, rheaders)
+		s.NoError(err)
+
+		defer senderConn.Close()
+		defer receiverConn.Close()
+
+		err = senderConn.WriteJSON(&Message{RoomID: roomID, Content: "/me waves"})
+		s.NoError(err)
+
+		var receiverEvent messageEvent
+		err = receiverConn.ReadJSON(&receiverEvent)
+		s.NoError(err)
+
+		s.Equal(models.EventMessageCreated, receiverEvent.Type)
+		s.Equal("_Chimezie Edeh waves_", receiverEvent.Data.Content)
+
+		var senderEvent messageEvent
+		err = senderConn.ReadJSON(&senderEvent)
+		s.NoError(err)
+
+		err = senderConn.WriteJSON(&Message{RoomID: roomID, Content: "/nope"})
+		s.NoError(err)
+
+		var event struct {
+			Type string                 `json:"type"`
+			Data models.CommandResponse `json:"data"`
+		}
+		err = senderConn.ReadJSON(&event)
+		s.NoError(err)
+
+		s.Equal(models.EventCommandResponse, event.Type)
+		s.Equal("nope", event.Data.Command)
+		s.Equal("unknown command: /nope", event.Data.Error)
+	})
+
+	s.Run("sync drafts across sessions", func() {
+		url := fmt.Sprintf("ws://%s/ws", s.server.URL[7:])
+		headers := http.Header{}
+		headers.Add("Authorization", s.sender.accessToken)
+
+		laptopConn, _, err := websocket.DefaultDialer.Dial(url+"?session_id=laptop", headers)
+		s.NoError(err)
+
+		phoneConn, _, err := websocket.DefaultDialer.Dial(url+"?session_id=phone", headers)
+		s.NoError(err)
+
+		defer laptopConn.Close()
+		defer phoneConn.Close()
+
+		body, err := json.Marshal(map[string]string{"content": "Half a thought"})
+		s.NoError(err)
+
+		req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/v1/rooms/%s/draft", baseUrl, roomID), bytes.NewBuffer(body))
+		s.NoError(err)
+		req.Header.Set("Authorization", s.sender.accessToken)
+		req.Header.Set("X-Session-ID", "laptop")
+
+		resp, err := client.Do(req)
+		s.NoError(err)
+		resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		var event struct {
+			Type string              `json:"type"`
+			Data models.MessageDraft `json:"data"`
+		}
+		err = phoneConn.ReadJSON(&event)
+		s.NoError(err)
+
+		s.Equal(models.EventDraftUpdated, event.Type)
+		s.Equal("Half a thought", event.Data.Content)
+
+		req, err = http.NewRequest("GET", fmt.Sprintf("%s/api/v1/rooms/%s/draft", baseUrl, roomID), nil)
+		s.NoError(err)
+		req.Header.Set("Authorization", s.sender.accessToken)
+
+		resp, err = client.Do(req)
+		s.NoError(err)
+
+		var data utils.Response[map[string]*models.MessageDraft]
+		err = utils.ReadJSON(resp.Body, &data)
+		s.NoError(err)
+		s.Equal("Half a thought", data.Data["draft"].Content)
+
+		err = laptopConn.WriteJSON(&Message{RoomID: roomID, Content: "Half a thought, finished"})
 		s.NoError(err)
 
-		s.Equal(senderMsg.Content, receiverMsg.Content)
+		var message messageEvent
+		err = phoneConn.ReadJSON(&message)
+		s.NoError(err)
+		s.Equal(models.EventMessageCreated, message.Type)
+		s.Equal("Half a thought, finished", message.Data.Content)
+
+		event.Data = models.MessageDraft{}
+		err = phoneConn.ReadJSON(&event)
+		s.NoError(err)
+
+		s.Equal(models.EventDraftUpdated, event.Type)
+		s.Empty(event.Data.Content)
+
+		_, err = s.services.GetMessageDraftService().GetDraft(s.sender.user.ID, roomID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+
+		// messages posted through the API clear the draft too
+		err = s.services.GetMessageDraftService().SaveDraft(&models.MessageDraft{UserID: s.sender.user.ID, RoomID: roomID, Content: "Lunch?"}, nil)
+		s.NoError(err)
+
+		body, err = json.Marshal(map[string]any{"question": "Lunch?", "options": []string{"Yes", "No"}})
+		s.NoError(err)
+
+		req, err = http.NewRequest("POST", fmt.Sprintf("%s/api/v1/rooms/%s/polls", baseUrl, roomID), bytes.NewBuffer(body))
+		s.NoError(err)
+		req.Header.Set("Authorization", s.sender.accessToken)
+
+		resp, err = client.Do(req)
+		s.NoError(err)
+		resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		types := []string{}
+		for range 2 {
+			var event struct {
+				Type string `json:"type"`
+			}
+			err = phoneConn.ReadJSON(&event)
+			s.NoError(err)
+			types = append(types, event.Type)
+		}
+		s.ElementsMatch([]string{models.EventMessageCreated, models.EventDraftUpdated}, types)
+
+		_, err = s.services.GetMessageDraftService().GetDraft(s.sender.user.ID, roomID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
 	})
 }
 
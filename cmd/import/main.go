@@ -0,0 +1,76 @@
+// Command import brings rooms and history over from other chat services.
+//
+// Usage:
+//
+//	import slack [-workspace name] export.zip
+//
+// The slack subcommand imports the public channels of a Slack export zip
+// and prints how Slack users and channels map to local users and rooms.
+// Imports can be re-run; only what was not imported before is added.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/princecee/go_chat/internal/slack"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: import slack [-workspace name] export.zip")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "slack":
+		importSlack(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func importSlack(args []string) {
+	fs := flag.NewFlagSet("slack", flag.ExitOnError)
+	workspace := fs.String("workspace", "default", "name the workspace's imports are recorded under; use a different one per workspace")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatal(err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), os.Getenv("DSN"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+
+	archive, err := slack.OpenArchive(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer archive.Close()
+
+	report, err := slack.NewImporter(services.New(pool), *workspace).Import(archive)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := report.Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+var mentionPattern = regexp.MustCompile(`^<@([0-9a-fA-F-]{36})>$`)
+
+func builtins(r *Registry) []*Command {
+	return []*Command{
+		{
+			Name:        "me",
+			Usage:       "/me <action>",
+			Description: "Describe what you are doing",
+			Handler:     me,
+		},
+		{
+			Name:        "topic",
+			Usage:       "/topic <text>",
+			Description: "Set the room topic",
+			Handler:     topic,
+		},
+		{
+			Name:        "invite",
+			Usage:       "/invite @user",
+			Description: "Add someone to the room",
+			Handler:     invite,
+		},
+		{
+			Name:        "leave",
+			Usage:       "/leave",
+			Description: "Leave the room",
+			Handler:     leave,
+		},
+		{
+			Name:        "help",
+			Usage:       "/help",
+			Description: "List the available commands",
+			Handler: func(ctx *Context) (*Result, error) {
+				lines := []string{}
+				for _, cmd := range r.Commands() {
+					lines = append(lines, fmt.Sprintf("%s - %s", cmd.Usage, cmd.Description))
+				}
+				return &Result{Reply: strings.Join(lines, "\n")}, nil
+			},
+		},
+	}
+}
+
+func usageError(usage string) error {
+	return fmt.Errorf("usage: %s", usage)
+}
+
+func me(ctx *Context) (*Result, error) {
+	if ctx.Args == "" {
+		return nil, usageError("/me <action>")
+	}
+
+	return &Result{
+		Post: fmt.Sprintf("_%s %s %s_", ctx.User.FirstName, ctx.User.LastName, ctx.Args),
+	}, nil
+}
+
+// topic sets the room description. Like the room update endpoint, it needs
+// services.PermissionUpdateRoom.
+func topic(ctx *Context) (*Result, error) {
+	if ctx.Args == "" {
+		return nil, usageError("/topic <text>")
+	}
+	if !ctx.Services.GetAuthorizationService().Can(ctx.Member.Role, services.PermissionUpdateRoom) {
+		return nil, errors.New("you do not have permission to set the topic")
+	}
+
+	ctx.Room.Description = ctx.Args
+	err := ctx.Services.GetRoomService().UpdateRoom(ctx.Room, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Services.GetEventService().Publish(&models.Event{
+		Type:   models.EventRoomUpdated,
+		RoomID: ctx.Room.ID,
+		Data:   ctx.Room,
+	})
+
+	return &Result{Reply: fmt.Sprintf("Topic set to %q", ctx.Args)}, nil
+}
+
+// invite adds a user to the room. The user is given as a mention, as
+// inserted by clients when picking someone, or as @email. Only public rooms
+// can be joined this way; private and restricted rooms go through invite
+// links and join requests.
+func invite(ctx *Context) (*Result, error) {
+	if ctx.Args == "" {
+		return nil, usageError("/invite @user")
+	}
+
+	params := repositories.GetUserParams{}
+	if m := mentionPattern.FindStringSubmatch(ctx.Args); m != nil {
+		params.ID = m[1]
+	} else {
+		params.Email = strings.TrimPrefix(ctx.Args, "@")
+	}
+
+	userService := ctx.Services.GetUserService()
+	user, err := userService.GetUser(params, nil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("no user found for %s", ctx.Args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roomService := ctx.Services.GetRoomService()
+	_, err = roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: user.ID,
+		RoomID: ctx.Room.ID,
+	}, nil)
+	if err == nil {
+		return nil, fmt.Errorf("%s %s is already a member", user.FirstName, user.LastName)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	member := &models.RoomMember{
+		UserID: user.ID,
+		RoomID: ctx.Room.ID,
+	}
+	err = roomService.JoinRoom(member, nil)
+	if errors.Is(err, services.ErrMaxMembersReached) {
+		return nil, errors.New("max room members reached")
+	}
+	if errors.Is(err, services.ErrBanned) {
+		return nil, fmt.Errorf("%s %s is banned from this room", user.FirstName, user.LastName)
+	}
+	if errors.Is(err, services.ErrPrivateRoom) || errors.Is(err, services.ErrApprovalRequired) {
+		return nil, errors.New("only public rooms can be joined with /invite, share an invite link instead")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMemberJoined,
+		RoomID: ctx.Room.ID,
+		Data:   member,
+	})
+	ctx.Services.GetEventService().Publish(&models.Event{
+		Type:   models.EventRoomInvited,
+		RoomID: ctx.Room.ID,
+		UserID: user.ID,
+		Data:   ctx.Room,
+	})
+
+	return &Result{Post: fmt.Sprintf("added <@%s> to the room", user.ID)}, nil
+}
+
+func leave(ctx *Context) (*Result, error) {
+	err := ctx.Services.GetRoomService().LeaveRoom(ctx.Member.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Services.GetEventService().Publish(&models.Event{
+		Type:   models.EventMemberLeft,
+		RoomID: ctx.Room.ID,
+		Data:   ctx.Member,
+	})
+
+	return &Result{Reply: fmt.Sprintf("You left %s", ctx.Room.Name)}, nil
+}
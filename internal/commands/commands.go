@@ -0,0 +1,154 @@
+// Package commands implements the slash commands users can type into the
+// message box, such as "/me waves" or "/topic Release planning". Commands run
+// on the server in place of posting the text as a message.
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+var (
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrDuplicateCommand = errors.New("command already registered")
+	ErrInvalidName      = errors.New("invalid command name")
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Context is what a command runs against: the room it was typed in, the
+// caller's membership of that room and the application services.
+type Context struct {
+	Room     *models.Room
+	Member   *models.RoomMember
+	User     *models.User
+	Services services.Services
+	// Args is the text after the command name, trimmed of spaces.
+	Args string
+}
+
+// Result is the outcome of a command. Either field may be empty.
+type Result struct {
+	// Post is sent to the room as a regular message from the caller.
+	Post string
+	// Reply is shown to the caller only.
+	Reply string
+}
+
+// Handler executes a command. Errors are reported back to the caller only,
+// so their text should make sense to the user.
+type Handler func(ctx *Context) (*Result, error)
+
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Handler     Handler
+}
+
+// Registry holds the commands available to users, keyed by name.
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]*Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{commands: map[string]*Command{}}
+}
+
+// Register adds cmd to the registry. Names are lowercase letters, digits,
+// dashes and underscores, starting with a letter.
+func (r *Registry) Register(cmd *Command) error {
+	if !namePattern.MatchString(cmd.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, cmd.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.commands[cmd.Name]; ok {
+		return fmt.Errorf("%w: /%s", ErrDuplicateCommand, cmd.Name)
+	}
+	r.commands[cmd.Name] = cmd
+	return nil
+}
+
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Commands returns the registered commands sorted by name.
+func (r *Registry) Commands() []*Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+// Execute runs the command named by name with ctx.
+func (r *Registry) Execute(name string, ctx *Context) (*Result, error) {
+	cmd, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: /%s", ErrUnknownCommand, name)
+	}
+
+	return cmd.Handler(ctx)
+}
+
+// Parse splits message content into a command name and its arguments. It
+// reports false for content that is not a command, including text that
+// merely starts with a slash such as a file path.
+func Parse(content string) (name, args string, ok bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "/") {
+		return "", "", false
+	}
+
+	name, args, _ = strings.Cut(content[1:], " ")
+	name = strings.ToLower(name)
+	if !namePattern.MatchString(name) {
+		return "", "", false
+	}
+
+	return name, strings.TrimSpace(args), true
+}
+
+var defaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, cmd := range builtins(r) {
+		if err := r.Register(cmd); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Default returns the registry used by the websocket server, which comes
+// with the built-in commands.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a custom command to the default registry. It is meant to be
+// called during start-up, before clients connect.
+func Register(cmd *Command) error {
+	return defaultRegistry.Register(cmd)
+}
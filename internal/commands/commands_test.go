@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		content string
+		name    string
+		args    string
+		ok      bool
+	}{
+		{"/me waves", "me", "waves", true},
+		{"  /TOPIC  Release planning ", "topic", "Release planning", true},
+		{"/leave", "leave", "", true},
+		{"hello /me", "", "", false},
+		{"/usr/bin/env is missing", "", "", false},
+		{"/", "", "", false},
+		{"/ me", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			name, args, ok := Parse(tt.content)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	deploy := &Command{
+		Name:  "deploy",
+		Usage: "/deploy <service>",
+		Handler: func(ctx *Context) (*Result, error) {
+			return &Result{Reply: "deploying " + ctx.Args}, nil
+		},
+	}
+
+	require.NoError(t, r.Register(deploy))
+	assert.ErrorIs(t, r.Register(deploy), ErrDuplicateCommand)
+	assert.ErrorIs(t, r.Register(&Command{Name: "Deploy"}), ErrInvalidName)
+
+	result, err := r.Execute("deploy", &Context{Args: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "deploying api", result.Reply)
+
+	_, err = r.Execute("rollback", &Context{})
+	assert.ErrorIs(t, err, ErrUnknownCommand)
+}
+
+func TestBuiltins(t *testing.T) {
+	user := &models.User{FirstName: "Ada", LastName: "Lovelace"}
+
+	result, err := Default().Execute("me", &Context{User: user, Args: "waves"})
+	require.NoError(t, err)
+	assert.Equal(t, "_Ada Lovelace waves_", result.Post)
+	assert.Empty(t, result.Reply)
+
+	_, err = Default().Execute("me", &Context{User: user})
+	assert.EqualError(t, err, "usage: /me <action>")
+
+	result, err = Default().Execute("help", &Context{User: user})
+	require.NoError(t, err)
+	assert.Contains(t, result.Reply, "/invite @user - Add someone to the room")
+	assert.Empty(t, result.Post)
+}
@@ -11,6 +11,36 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Attachment struct {
+	ID               uuid.UUID
+	RoomID           uuid.UUID
+	RoomMessageID    uuid.UUID
+	UserID           uuid.UUID
+	FileName         string
+	ContentType      string
+	Size             int64
+	StorageKey       string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Width            pgtype.Int4
+	Height           pgtype.Int4
+	DominantColor    pgtype.Text
+	ProcessingStatus string
+}
+
+type AttachmentThumbnail struct {
+	ID           uuid.UUID
+	AttachmentID uuid.UUID
+	Label        string
+	Width        int32
+	Height       int32
+	ContentType  string
+	Size         int64
+	StorageKey   string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
 type Auth struct {
 	ID        uuid.UUID
 	Password  string
@@ -19,22 +49,187 @@ type Auth struct {
 	UpdatedAt time.Time
 }
 
-type Room struct {
+type IncomingWebhook struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	TokenHash string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ImportMapping struct {
+	ID         uuid.UUID
+	Source     string
+	Kind       string
+	ExternalID string
+	ParentID   string
+	LocalID    uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type LinkPreview struct {
 	ID          uuid.UUID
-	Name        string
-	Description pgtype.Text
-	MaxMembers  int32
-	CreatedBy   uuid.UUID
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+type MessageDraft struct {
+	UserID    uuid.UUID
+	RoomID    uuid.UUID
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ModerationAuditLog struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID pgtype.UUID
+	Filter    string
+	Action    string
+	Reason    string
+	Content   string
+	CreatedAt time.Time
+}
+
+type OutgoingWebhook struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Url        string
+	Secret     string
+	EventTypes []string
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type OutgoingWebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode pgtype.Int4
+	LastError      string
+	DeliveredAt    pgtype.Timestamptz
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type Poll struct {
+	ID             uuid.UUID
+	RoomID         uuid.UUID
+	RoomMessageID  uuid.UUID
+	CreatedBy      uuid.UUID
+	Question       string
+	MultipleChoice bool
+	Anonymous      bool
+	ClosesAt       pgtype.Timestamptz
+	ClosedAt       pgtype.Timestamptz
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PollOption struct {
+	ID        uuid.UUID
+	PollID    uuid.UUID
+	Position  int32
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PollVote struct {
+	ID           uuid.UUID
+	PollID       uuid.UUID
+	PollOptionID uuid.UUID
+	UserID       uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Room struct {
+	ID              uuid.UUID
+	Name            string
+	Description     pgtype.Text
+	MaxMembers      int32
+	CreatedBy       uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	MessageTtl      int32
+	Kind            string
+	ParticipantsKey pgtype.Text
+	RetentionDays   pgtype.Int4
+	LegalHold       bool
+	Visibility      string
+}
+
+type RoomBan struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	Reason    string
+	CreatedBy pgtype.UUID
+	ExpiresAt pgtype.Timestamptz
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RoomInvite struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	Code      string
+	CreatedBy uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+	MaxUses   pgtype.Int4
+	Uses      int32
+	RevokedAt pgtype.Timestamptz
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RoomJoinRequest struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	UserID     uuid.UUID
+	Message    string
+	Status     string
+	ReviewedBy pgtype.UUID
+	ReviewedAt pgtype.Timestamptz
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
 type RoomMember struct {
 	ID        uuid.UUID
 	RoomID    uuid.UUID
 	UserID    uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Role      string
+}
+
+type RoomMemberAction struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	ActorID   pgtype.UUID
+	Action    string
+	Reason    string
+	ExpiresAt pgtype.Timestamptz
+	CreatedAt time.Time
 }
 
 type RoomMessage struct {
@@ -45,6 +240,68 @@ type RoomMessage struct {
 	Content      string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	ContentTsv   interface{}
+	ContentHtml  string
+	Entities     []byte
+	ExpiresAt    pgtype.Timestamptz
+}
+
+type RoomMessageLinkPreview struct {
+	ID            uuid.UUID
+	RoomMessageID uuid.UUID
+	LinkPreviewID uuid.UUID
+	Position      int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type RoomModerationConfig struct {
+	RoomID    uuid.UUID
+	Config    []byte
+	UpdatedBy pgtype.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RoomMute struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	Reason    string
+	CreatedBy pgtype.UUID
+	ExpiresAt pgtype.Timestamptz
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RoomPin struct {
+	ID            uuid.UUID
+	RoomID        uuid.UUID
+	RoomMessageID uuid.UUID
+	PinnedBy      uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type SavedMessage struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	RoomMessageID uuid.UUID
+	Note          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type ScheduledMessage struct {
+	ID            uuid.UUID
+	RoomID        uuid.UUID
+	UserID        uuid.UUID
+	Content       string
+	ScheduledAt   time.Time
+	Status        string
+	RoomMessageID pgtype.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 type User struct {
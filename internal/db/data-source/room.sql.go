@@ -14,8 +14,8 @@ import (
 )
 
 const createRoom = `-- name: CreateRoom :one
-INSERT INTO rooms (name, description, max_members, created_by)
-VALUES ($1, $2, $3, $4)
+INSERT INTO rooms (name, description, max_members, created_by, message_ttl, visibility)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, created_at, updated_at
 `
 
@@ -24,6 +24,8 @@ type CreateRoomParams struct {
 	Description pgtype.Text
 	MaxMembers  int32
 	CreatedBy   uuid.UUID
+	MessageTtl  int32
+	Visibility  string
 }
 
 type CreateRoomRow struct {
@@ -38,20 +40,94 @@ func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (CreateR
 		arg.Description,
 		arg.MaxMembers,
 		arg.CreatedBy,
+		arg.MessageTtl,
+		arg.Visibility,
 	)
 	var i CreateRoomRow
 	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
 	return i, err
 }
 
+const createDirectRoom = `-- name: CreateDirectRoom :one
+INSERT INTO rooms (name, max_members, created_by, kind, participants_key)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (participants_key) DO NOTHING
+RETURNING id, created_at, updated_at
+`
+
+type CreateDirectRoomParams struct {
+	Name            string
+	MaxMembers      int32
+	CreatedBy       uuid.UUID
+	Kind            string
+	ParticipantsKey pgtype.Text
+}
+
+type CreateDirectRoomRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateDirectRoom(ctx context.Context, arg CreateDirectRoomParams) (CreateDirectRoomRow, error) {
+	row := q.db.QueryRow(ctx, createDirectRoom,
+		arg.Name,
+		arg.MaxMembers,
+		arg.CreatedBy,
+		arg.Kind,
+		arg.ParticipantsKey,
+	)
+	var i CreateDirectRoomRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const createImportedRoomMessage = `-- name: CreateImportedRoomMessage :one
+INSERT INTO room_messages (room_id, room_member_id, user_id, content, content_html, entities, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+RETURNING id, created_at, updated_at
+`
+
+type CreateImportedRoomMessageParams struct {
+	RoomID       uuid.UUID
+	RoomMemberID uuid.UUID
+	UserID       uuid.UUID
+	Content      string
+	ContentHtml  string
+	Entities     []byte
+	CreatedAt    time.Time
+}
+
+type CreateImportedRoomMessageRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateImportedRoomMessage(ctx context.Context, arg CreateImportedRoomMessageParams) (CreateImportedRoomMessageRow, error) {
+	row := q.db.QueryRow(ctx, createImportedRoomMessage,
+		arg.RoomID,
+		arg.RoomMemberID,
+		arg.UserID,
+		arg.Content,
+		arg.ContentHtml,
+		arg.Entities,
+		arg.CreatedAt,
+	)
+	var i CreateImportedRoomMessageRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
 const createRoomMember = `-- name: CreateRoomMember :one
-INSERT INTO room_members (room_id, user_id) VALUES($1, $2)
+INSERT INTO room_members (room_id, user_id, role) VALUES($1, $2, $3)
 RETURNING id, created_at, updated_at
 `
 
 type CreateRoomMemberParams struct {
 	RoomID uuid.UUID
 	UserID uuid.UUID
+	Role   string
 }
 
 type CreateRoomMemberRow struct {
@@ -61,15 +137,15 @@ type CreateRoomMemberRow struct {
 }
 
 func (q *Queries) CreateRoomMember(ctx context.Context, arg CreateRoomMemberParams) (CreateRoomMemberRow, error) {
-	row := q.db.QueryRow(ctx, createRoomMember, arg.RoomID, arg.UserID)
+	row := q.db.QueryRow(ctx, createRoomMember, arg.RoomID, arg.UserID, arg.Role)
 	var i CreateRoomMemberRow
 	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
 	return i, err
 }
 
 const createRoomMessage = `-- name: CreateRoomMessage :one
-INSERT INTO room_messages (room_id, room_member_id, user_id, content)
-VALUES ($1, $2, $3, $4)
+INSERT INTO room_messages (room_id, room_member_id, user_id, content, content_html, entities, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING id, created_at, updated_at
 `
 
@@ -78,6 +154,9 @@ type CreateRoomMessageParams struct {
 	RoomMemberID uuid.UUID
 	UserID       uuid.UUID
 	Content      string
+	ContentHtml  string
+	Entities     []byte
+	ExpiresAt    pgtype.Timestamptz
 }
 
 type CreateRoomMessageRow struct {
@@ -92,12 +171,40 @@ func (q *Queries) CreateRoomMessage(ctx context.Context, arg CreateRoomMessagePa
 		arg.RoomMemberID,
 		arg.UserID,
 		arg.Content,
+		arg.ContentHtml,
+		arg.Entities,
+		arg.ExpiresAt,
 	)
 	var i CreateRoomMessageRow
 	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
 	return i, err
 }
 
+const createRoomPin = `-- name: CreateRoomPin :one
+INSERT INTO room_pins (room_id, room_message_id, pinned_by)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, updated_at
+`
+
+type CreateRoomPinParams struct {
+	RoomID        uuid.UUID
+	RoomMessageID uuid.UUID
+	PinnedBy      uuid.UUID
+}
+
+type CreateRoomPinRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateRoomPin(ctx context.Context, arg CreateRoomPinParams) (CreateRoomPinRow, error) {
+	row := q.db.QueryRow(ctx, createRoomPin, arg.RoomID, arg.RoomMessageID, arg.PinnedBy)
+	var i CreateRoomPinRow
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
 const deleteRoom = `-- name: DeleteRoom :exec
 DELETE FROM rooms WHERE id = $1
 `
@@ -125,8 +232,166 @@ func (q *Queries) DeleteRoomMessage(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteRoomMessages = `-- name: DeleteRoomMessages :exec
+DELETE FROM room_messages WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) DeleteRoomMessages(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRoomMessages, ids)
+	return err
+}
+
+const deleteRoomPin = `-- name: DeleteRoomPin :exec
+DELETE FROM room_pins WHERE id = $1
+`
+
+func (q *Queries) DeleteRoomPin(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRoomPin, id)
+	return err
+}
+
+const getConversationParticipants = `-- name: GetConversationParticipants :many
+SELECT room_members.room_id, users.id, users.first_name, users.last_name, users.email, users.created_at, users.updated_at FROM room_members
+JOIN users ON users.id = room_members.user_id
+WHERE room_members.room_id = ANY($1::uuid[])
+ORDER BY room_members.created_at ASC
+`
+
+type GetConversationParticipantsRow struct {
+	RoomID    uuid.UUID
+	ID        uuid.UUID
+	FirstName string
+	LastName  string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) GetConversationParticipants(ctx context.Context, roomIds []uuid.UUID) ([]GetConversationParticipantsRow, error) {
+	rows, err := q.db.Query(ctx, getConversationParticipants, roomIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetConversationParticipantsRow
+	for rows.Next() {
+		var i GetConversationParticipantsRow
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredRoomMessages = `-- name: GetExpiredRoomMessages :many
+SELECT room_messages.id, room_messages.room_id, room_messages.room_member_id, room_messages.user_id, room_messages.content, room_messages.created_at, room_messages.updated_at, room_messages.content_tsv, room_messages.content_html, room_messages.entities, room_messages.expires_at FROM room_messages
+JOIN rooms ON rooms.id = room_messages.room_id
+WHERE NOT rooms.legal_hold
+  AND room_messages.expires_at <= $1
+ORDER BY room_messages.expires_at ASC
+LIMIT $2
+FOR UPDATE OF room_messages SKIP LOCKED
+`
+
+type GetExpiredRoomMessagesParams struct {
+	ExpiredBefore pgtype.Timestamptz
+	BatchSize     int32
+}
+
+func (q *Queries) GetExpiredRoomMessages(ctx context.Context, arg GetExpiredRoomMessagesParams) ([]RoomMessage, error) {
+	rows, err := q.db.Query(ctx, getExpiredRoomMessages, arg.ExpiredBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomMessage
+	for rows.Next() {
+		var i RoomMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.RoomMemberID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContentTsv,
+			&i.ContentHtml,
+			&i.Entities,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRetentionExpiredMessages = `-- name: GetRetentionExpiredMessages :many
+SELECT room_messages.id, room_messages.room_id, room_messages.room_member_id, room_messages.user_id, room_messages.content, room_messages.created_at, room_messages.updated_at, room_messages.content_tsv, room_messages.content_html, room_messages.entities, room_messages.expires_at FROM room_messages
+JOIN rooms ON rooms.id = room_messages.room_id
+WHERE NOT rooms.legal_hold
+  AND COALESCE(rooms.retention_days, $1::int) > 0
+  AND room_messages.created_at < NOW() - make_interval(days => COALESCE(rooms.retention_days, $1::int))
+ORDER BY room_messages.created_at ASC
+LIMIT $2
+FOR UPDATE OF room_messages SKIP LOCKED
+`
+
+type GetRetentionExpiredMessagesParams struct {
+	DefaultRetentionDays int32
+	BatchSize            int32
+}
+
+func (q *Queries) GetRetentionExpiredMessages(ctx context.Context, arg GetRetentionExpiredMessagesParams) ([]RoomMessage, error) {
+	rows, err := q.db.Query(ctx, getRetentionExpiredMessages, arg.DefaultRetentionDays, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomMessage
+	for rows.Next() {
+		var i RoomMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.RoomMemberID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContentTsv,
+			&i.ContentHtml,
+			&i.Entities,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRoom = `-- name: GetRoom :one
-SELECT id, name, description, max_members, created_by, created_at, updated_at FROM rooms WHERE id = $1 LIMIT 1
+SELECT id, name, description, max_members, created_by, created_at, updated_at, message_ttl, kind, participants_key, retention_days, legal_hold, visibility FROM rooms WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetRoom(ctx context.Context, id uuid.UUID) (Room, error) {
@@ -140,12 +405,68 @@ func (q *Queries) GetRoom(ctx context.Context, id uuid.UUID) (Room, error) {
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MessageTtl,
+		&i.Kind,
+		&i.ParticipantsKey,
+		&i.RetentionDays,
+		&i.LegalHold,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getRoomByParticipantsKey = `-- name: GetRoomByParticipantsKey :one
+SELECT id, name, description, max_members, created_by, created_at, updated_at, message_ttl, kind, participants_key, retention_days, legal_hold, visibility FROM rooms WHERE participants_key = $1 LIMIT 1
+`
+
+func (q *Queries) GetRoomByParticipantsKey(ctx context.Context, participantsKey pgtype.Text) (Room, error) {
+	row := q.db.QueryRow(ctx, getRoomByParticipantsKey, participantsKey)
+	var i Room
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.MaxMembers,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MessageTtl,
+		&i.Kind,
+		&i.ParticipantsKey,
+		&i.RetentionDays,
+		&i.LegalHold,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getRoomForUpdate = `-- name: GetRoomForUpdate :one
+SELECT id, name, description, max_members, created_by, created_at, updated_at, message_ttl, kind, participants_key, retention_days, legal_hold, visibility FROM rooms WHERE id = $1 LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetRoomForUpdate(ctx context.Context, id uuid.UUID) (Room, error) {
+	row := q.db.QueryRow(ctx, getRoomForUpdate, id)
+	var i Room
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.MaxMembers,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MessageTtl,
+		&i.Kind,
+		&i.ParticipantsKey,
+		&i.RetentionDays,
+		&i.LegalHold,
+		&i.Visibility,
 	)
 	return i, err
 }
 
 const getRoomMember = `-- name: GetRoomMember :one
-SELECT id, room_id, user_id, created_at, updated_at FROM room_members WHERE id = $1 LIMIT 1
+SELECT id, room_id, user_id, created_at, updated_at, role FROM room_members WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetRoomMember(ctx context.Context, id uuid.UUID) (RoomMember, error) {
@@ -157,12 +478,13 @@ func (q *Queries) GetRoomMember(ctx context.Context, id uuid.UUID) (RoomMember,
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getRoomMemberByWhere = `-- name: GetRoomMemberByWhere :one
-SELECT id, room_id, user_id, created_at, updated_at FROM room_members WHERE user_id = $1 AND room_id = $2
+SELECT id, room_id, user_id, created_at, updated_at, role FROM room_members WHERE user_id = $1 AND room_id = $2
 `
 
 type GetRoomMemberByWhereParams struct {
@@ -179,12 +501,51 @@ func (q *Queries) GetRoomMemberByWhere(ctx context.Context, arg GetRoomMemberByW
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
+const getRoomMemberUsers = `-- name: GetRoomMemberUsers :many
+SELECT users.id, users.first_name, users.last_name, users.email, users.created_at, users.updated_at FROM room_members
+JOIN users ON users.id = room_members.user_id
+WHERE room_members.room_id = $1 AND room_members.user_id = ANY($2::uuid[])
+`
+
+type GetRoomMemberUsersParams struct {
+	RoomID  uuid.UUID
+	UserIds []uuid.UUID
+}
+
+func (q *Queries) GetRoomMemberUsers(ctx context.Context, arg GetRoomMemberUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, getRoomMemberUsers, arg.RoomID, arg.UserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRoomMembers = `-- name: GetRoomMembers :many
-SELECT id, room_id, user_id, created_at, updated_at FROM room_members WHERE room_id = COALESCE($1, room_id) AND user_id = COALESCE($2, user_id)
+SELECT id, room_id, user_id, created_at, updated_at, role FROM room_members WHERE room_id = COALESCE($1, room_id) AND user_id = COALESCE($2, user_id)
 `
 
 type GetRoomMembersParams struct {
@@ -207,6 +568,7 @@ func (q *Queries) GetRoomMembers(ctx context.Context, arg GetRoomMembersParams)
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -219,7 +581,7 @@ func (q *Queries) GetRoomMembers(ctx context.Context, arg GetRoomMembersParams)
 }
 
 const getRoomMessage = `-- name: GetRoomMessage :one
-SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at FROM room_messages WHERE id = $1 LIMIT 1
+SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at, content_tsv, content_html, entities, expires_at FROM room_messages WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetRoomMessage(ctx context.Context, id uuid.UUID) (RoomMessage, error) {
@@ -233,12 +595,16 @@ func (q *Queries) GetRoomMessage(ctx context.Context, id uuid.UUID) (RoomMessage
 		&i.Content,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ContentTsv,
+		&i.ContentHtml,
+		&i.Entities,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
 const getRoomMessages = `-- name: GetRoomMessages :many
-SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at FROM room_messages WHERE
+SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at, content_tsv, content_html, entities, expires_at FROM room_messages WHERE
   room_id = COALESCE($1, room_id) AND
   room_member_id = COALESCE($2, room_member_id) AND
   user_id = COALESCE($3, user_id)
@@ -267,6 +633,251 @@ func (q *Queries) GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams
 			&i.Content,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ContentTsv,
+			&i.ContentHtml,
+			&i.Entities,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomMessagesAfter = `-- name: GetRoomMessagesAfter :many
+SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at, content_tsv, content_html, entities, expires_at FROM room_messages
+WHERE room_id = $1 AND (expires_at IS NULL OR expires_at > NOW()) AND
+  (created_at, id) > ($2::timestamptz, $3::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT $4
+`
+
+type GetRoomMessagesAfterParams struct {
+	RoomID          uuid.UUID
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	PageLimit       int32
+}
+
+func (q *Queries) GetRoomMessagesAfter(ctx context.Context, arg GetRoomMessagesAfterParams) ([]RoomMessage, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesAfter,
+		arg.RoomID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomMessage
+	for rows.Next() {
+		var i RoomMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.RoomMemberID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContentTsv,
+			&i.ContentHtml,
+			&i.Entities,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomMessagesBefore = `-- name: GetRoomMessagesBefore :many
+SELECT id, room_id, room_member_id, user_id, content, created_at, updated_at, content_tsv, content_html, entities, expires_at FROM room_messages
+WHERE room_id = $1 AND (expires_at IS NULL OR expires_at > NOW()) AND (
+  $2::timestamptz IS NULL OR
+  (created_at, id) < ($2::timestamptz, $3::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type GetRoomMessagesBeforeParams struct {
+	RoomID          uuid.UUID
+	CursorCreatedAt pgtype.Timestamptz
+	CursorID        pgtype.UUID
+	PageLimit       int32
+}
+
+func (q *Queries) GetRoomMessagesBefore(ctx context.Context, arg GetRoomMessagesBeforeParams) ([]RoomMessage, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesBefore,
+		arg.RoomID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomMessage
+	for rows.Next() {
+		var i RoomMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.RoomMemberID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContentTsv,
+			&i.ContentHtml,
+			&i.Entities,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomPinByWhere = `-- name: GetRoomPinByWhere :one
+SELECT id, room_id, room_message_id, pinned_by, created_at, updated_at FROM room_pins WHERE room_id = $1 AND room_message_id = $2 LIMIT 1
+`
+
+type GetRoomPinByWhereParams struct {
+	RoomID        uuid.UUID
+	RoomMessageID uuid.UUID
+}
+
+func (q *Queries) GetRoomPinByWhere(ctx context.Context, arg GetRoomPinByWhereParams) (RoomPin, error) {
+	row := q.db.QueryRow(ctx, getRoomPinByWhere, arg.RoomID, arg.RoomMessageID)
+	var i RoomPin
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.RoomMessageID,
+		&i.PinnedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getRoomPins = `-- name: GetRoomPins :many
+SELECT room_pins.id, room_pins.room_id, room_pins.room_message_id, room_pins.pinned_by, room_pins.created_at, room_pins.updated_at, room_messages.id, room_messages.room_id, room_messages.room_member_id, room_messages.user_id, room_messages.content, room_messages.created_at, room_messages.updated_at, room_messages.content_tsv, room_messages.content_html, room_messages.entities, room_messages.expires_at
+FROM room_pins
+JOIN room_messages ON room_messages.id = room_pins.room_message_id
+WHERE room_pins.room_id = $1
+ORDER BY room_pins.created_at DESC
+`
+
+type GetRoomPinsRow struct {
+	RoomPin     RoomPin
+	RoomMessage RoomMessage
+}
+
+func (q *Queries) GetRoomPins(ctx context.Context, roomID uuid.UUID) ([]GetRoomPinsRow, error) {
+	rows, err := q.db.Query(ctx, getRoomPins, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoomPinsRow
+	for rows.Next() {
+		var i GetRoomPinsRow
+		if err := rows.Scan(
+			&i.RoomPin.ID,
+			&i.RoomPin.RoomID,
+			&i.RoomPin.RoomMessageID,
+			&i.RoomPin.PinnedBy,
+			&i.RoomPin.CreatedAt,
+			&i.RoomPin.UpdatedAt,
+			&i.RoomMessage.ID,
+			&i.RoomMessage.RoomID,
+			&i.RoomMessage.RoomMemberID,
+			&i.RoomMessage.UserID,
+			&i.RoomMessage.Content,
+			&i.RoomMessage.CreatedAt,
+			&i.RoomMessage.UpdatedAt,
+			&i.RoomMessage.ContentTsv,
+			&i.RoomMessage.ContentHtml,
+			&i.RoomMessage.Entities,
+			&i.RoomMessage.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomTranscript = `-- name: GetRoomTranscript :many
+SELECT room_messages.id, room_messages.created_at, room_messages.user_id, room_messages.content, users.first_name, users.last_name
+FROM room_messages
+JOIN users ON users.id = room_messages.user_id
+WHERE room_messages.room_id = $1 AND
+  (room_messages.expires_at IS NULL OR room_messages.expires_at > NOW()) AND (
+  $2::timestamptz IS NULL OR
+  (room_messages.created_at, room_messages.id) > ($2::timestamptz, $3::uuid)
+)
+ORDER BY room_messages.created_at ASC, room_messages.id ASC
+LIMIT $4
+`
+
+type GetRoomTranscriptParams struct {
+	RoomID          uuid.UUID
+	CursorCreatedAt pgtype.Timestamptz
+	CursorID        pgtype.UUID
+	PageLimit       int32
+}
+
+type GetRoomTranscriptRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Content   string
+	FirstName string
+	LastName  string
+}
+
+func (q *Queries) GetRoomTranscript(ctx context.Context, arg GetRoomTranscriptParams) ([]GetRoomTranscriptRow, error) {
+	rows, err := q.db.Query(ctx, getRoomTranscript,
+		arg.RoomID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoomTranscriptRow
+	for rows.Next() {
+		var i GetRoomTranscriptRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.Content,
+			&i.FirstName,
+			&i.LastName,
 		); err != nil {
 			return nil, err
 		}
@@ -279,11 +890,19 @@ func (q *Queries) GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams
 }
 
 const getRooms = `-- name: GetRooms :many
-SELECT id, name, description, max_members, created_by, created_at, updated_at FROM rooms WHERE created_by = COALESCE($1, created_by)
+SELECT id, name, description, max_members, created_by, created_at, updated_at, message_ttl, kind, participants_key, retention_days, legal_hold, visibility FROM rooms WHERE kind = 'room' AND created_by = COALESCE($1, created_by) AND (
+  visibility <> 'private' OR
+  EXISTS (SELECT 1 FROM room_members WHERE room_members.room_id = rooms.id AND room_members.user_id = $2)
+)
 `
 
-func (q *Queries) GetRooms(ctx context.Context, createdBy pgtype.UUID) ([]Room, error) {
-	rows, err := q.db.Query(ctx, getRooms, createdBy)
+type GetRoomsParams struct {
+	CreatedBy pgtype.UUID
+	ViewerID  pgtype.UUID
+}
+
+func (q *Queries) GetRooms(ctx context.Context, arg GetRoomsParams) ([]Room, error) {
+	rows, err := q.db.Query(ctx, getRooms, arg.CreatedBy, arg.ViewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -299,6 +918,53 @@ func (q *Queries) GetRooms(ctx context.Context, createdBy pgtype.UUID) ([]Room,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MessageTtl,
+			&i.Kind,
+			&i.ParticipantsKey,
+			&i.RetentionDays,
+			&i.LegalHold,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserConversations = `-- name: GetUserConversations :many
+SELECT rooms.id, rooms.name, rooms.description, rooms.max_members, rooms.created_by, rooms.created_at, rooms.updated_at, rooms.message_ttl, rooms.kind, rooms.participants_key, rooms.retention_days, rooms.legal_hold, rooms.visibility FROM rooms
+JOIN room_members ON room_members.room_id = rooms.id AND room_members.user_id = $1
+WHERE rooms.kind <> 'room'
+ORDER BY rooms.updated_at DESC, rooms.id DESC
+`
+
+func (q *Queries) GetUserConversations(ctx context.Context, userID uuid.UUID) ([]Room, error) {
+	rows, err := q.db.Query(ctx, getUserConversations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Room
+	for rows.Next() {
+		var i Room
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.MaxMembers,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MessageTtl,
+			&i.Kind,
+			&i.ParticipantsKey,
+			&i.RetentionDays,
+			&i.LegalHold,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -321,9 +987,48 @@ func (q *Queries) RoomMembersCount(ctx context.Context, roomID uuid.UUID) (int64
 	return count, err
 }
 
+const roomPinsCount = `-- name: RoomPinsCount :one
+SELECT COUNT(*) AS count FROM room_pins WHERE room_id = $1
+`
+
+func (q *Queries) RoomPinsCount(ctx context.Context, roomID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, roomPinsCount, roomID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const updateConversation = `-- name: UpdateConversation :exec
+UPDATE rooms SET updated_at = $1, kind = $2, participants_key = $3, name = $4, max_members = $5, visibility = $6
+WHERE id = $7
+`
+
+type UpdateConversationParams struct {
+	UpdatedAt       time.Time
+	Kind            string
+	ParticipantsKey pgtype.Text
+	Name            string
+	MaxMembers      int32
+	Visibility      string
+	ID              uuid.UUID
+}
+
+func (q *Queries) UpdateConversation(ctx context.Context, arg UpdateConversationParams) error {
+	_, err := q.db.Exec(ctx, updateConversation,
+		arg.UpdatedAt,
+		arg.Kind,
+		arg.ParticipantsKey,
+		arg.Name,
+		arg.MaxMembers,
+		arg.Visibility,
+		arg.ID,
+	)
+	return err
+}
+
 const updateRoom = `-- name: UpdateRoom :exec
-UPDATE rooms SET updated_at = $1, name = $2, description = $3, max_members = $4
-WHERE id = $5
+UPDATE rooms SET updated_at = $1, name = $2, description = $3, max_members = $4, message_ttl = $5, visibility = $6
+WHERE id = $7
 `
 
 type UpdateRoomParams struct {
@@ -331,6 +1036,8 @@ type UpdateRoomParams struct {
 	Name        string
 	Description pgtype.Text
 	MaxMembers  int32
+	MessageTtl  int32
+	Visibility  string
 	ID          uuid.UUID
 }
 
@@ -340,6 +1047,45 @@ func (q *Queries) UpdateRoom(ctx context.Context, arg UpdateRoomParams) error {
 		arg.Name,
 		arg.Description,
 		arg.MaxMembers,
+		arg.MessageTtl,
+		arg.Visibility,
+		arg.ID,
+	)
+	return err
+}
+
+const updateRoomMemberRole = `-- name: UpdateRoomMemberRole :exec
+UPDATE room_members SET role = $1, updated_at = $2 WHERE id = $3
+`
+
+type UpdateRoomMemberRoleParams struct {
+	Role      string
+	UpdatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateRoomMemberRole(ctx context.Context, arg UpdateRoomMemberRoleParams) error {
+	_, err := q.db.Exec(ctx, updateRoomMemberRole, arg.Role, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateRoomRetention = `-- name: UpdateRoomRetention :exec
+UPDATE rooms SET updated_at = $1, retention_days = $2, legal_hold = $3
+WHERE id = $4
+`
+
+type UpdateRoomRetentionParams struct {
+	UpdatedAt     time.Time
+	RetentionDays pgtype.Int4
+	LegalHold     bool
+	ID            uuid.UUID
+}
+
+func (q *Queries) UpdateRoomRetention(ctx context.Context, arg UpdateRoomRetentionParams) error {
+	_, err := q.db.Exec(ctx, updateRoomRetention,
+		arg.UpdatedAt,
+		arg.RetentionDays,
+		arg.LegalHold,
 		arg.ID,
 	)
 	return err
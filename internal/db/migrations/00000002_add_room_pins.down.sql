@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_pins;
+ALTER TABLE room_members DROP COLUMN IF EXISTS role;
\ No newline at end of file
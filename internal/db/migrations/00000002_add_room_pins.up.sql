@@ -0,0 +1,11 @@
+ALTER TABLE room_members ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member';
+
+CREATE TABLE IF NOT EXISTS room_pins (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  room_message_id UUID REFERENCES room_messages ON DELETE CASCADE NOT NULL,
+  pinned_by UUID REFERENCES users NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (room_id, room_message_id)
+);
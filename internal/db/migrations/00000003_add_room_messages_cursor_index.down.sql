@@ -0,0 +1 @@
+DROP INDEX IF EXISTS room_messages_room_id_created_at_id_idx;
\ No newline at end of file
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS room_messages_room_id_created_at_id_idx
+ON room_messages (room_id, created_at, id);
\ No newline at end of file
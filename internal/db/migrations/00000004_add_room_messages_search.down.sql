@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS room_messages_content_tsv_idx;
+ALTER TABLE room_messages DROP COLUMN IF EXISTS content_tsv;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE room_messages ADD COLUMN IF NOT EXISTS content_tsv TSVECTOR
+  GENERATED ALWAYS AS (to_tsvector('english', content)) STORED;
+
+CREATE INDEX IF NOT EXISTS room_messages_content_tsv_idx
+ON room_messages USING GIN (content_tsv);
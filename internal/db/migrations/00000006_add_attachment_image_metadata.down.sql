@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS attachment_thumbnails;
+ALTER TABLE attachments
+  DROP COLUMN IF EXISTS width,
+  DROP COLUMN IF EXISTS height,
+  DROP COLUMN IF EXISTS dominant_color,
+  DROP COLUMN IF EXISTS processing_status;
\ No newline at end of file
@@ -0,0 +1,19 @@
+ALTER TABLE attachments
+  ADD COLUMN IF NOT EXISTS width INT,
+  ADD COLUMN IF NOT EXISTS height INT,
+  ADD COLUMN IF NOT EXISTS dominant_color VARCHAR(7),
+  ADD COLUMN IF NOT EXISTS processing_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+CREATE TABLE IF NOT EXISTS attachment_thumbnails (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  attachment_id UUID REFERENCES attachments ON DELETE CASCADE NOT NULL,
+  label VARCHAR(20) NOT NULL,
+  width INT NOT NULL,
+  height INT NOT NULL,
+  content_type VARCHAR(255) NOT NULL,
+  size BIGINT NOT NULL,
+  storage_key TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (attachment_id, label)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE room_messages DROP COLUMN IF EXISTS entities;
+ALTER TABLE room_messages DROP COLUMN IF EXISTS content_html;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE room_messages ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+ALTER TABLE room_messages ADD COLUMN IF NOT EXISTS entities JSONB NOT NULL DEFAULT '[]';
+
+-- existing messages predate rich text, render them as escaped plain text
+UPDATE room_messages SET content_html = replace(
+  replace(replace(replace(replace(replace(content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+  E'\n', '<br>'
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_message_link_previews;
+DROP TABLE IF EXISTS link_previews;
\ No newline at end of file
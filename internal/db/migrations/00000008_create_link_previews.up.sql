@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS link_previews (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  url TEXT NOT NULL UNIQUE,
+  title TEXT NOT NULL DEFAULT '',
+  description TEXT NOT NULL DEFAULT '',
+  image_url TEXT NOT NULL DEFAULT '',
+  site_name TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS room_message_link_previews (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_message_id UUID REFERENCES room_messages ON DELETE CASCADE NOT NULL,
+  link_preview_id UUID REFERENCES link_previews ON DELETE CASCADE NOT NULL,
+  position INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (room_message_id, link_preview_id)
+);
\ No newline at end of file
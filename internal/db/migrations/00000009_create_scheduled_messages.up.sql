@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  content TEXT NOT NULL,
+  scheduled_at TIMESTAMPTZ NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  room_message_id UUID REFERENCES room_messages ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_messages_due_idx
+ON scheduled_messages (scheduled_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS scheduled_messages_room_id_user_id_idx
+ON scheduled_messages (room_id, user_id);
\ No newline at end of file
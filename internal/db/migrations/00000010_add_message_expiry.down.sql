@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS room_messages_expires_at_idx;
+ALTER TABLE room_messages DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE rooms DROP COLUMN IF EXISTS message_ttl;
\ No newline at end of file
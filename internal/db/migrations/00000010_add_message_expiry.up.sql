@@ -0,0 +1,5 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS message_ttl INT NOT NULL DEFAULT 0;
+ALTER TABLE room_messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS room_messages_expires_at_idx
+ON room_messages (expires_at) WHERE expires_at IS NOT NULL;
\ No newline at end of file
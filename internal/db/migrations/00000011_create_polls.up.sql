@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS polls (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  room_message_id UUID REFERENCES room_messages ON DELETE CASCADE NOT NULL UNIQUE,
+  created_by UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  question TEXT NOT NULL,
+  multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+  anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+  closes_at TIMESTAMPTZ,
+  closed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  poll_id UUID REFERENCES polls ON DELETE CASCADE NOT NULL,
+  position INT NOT NULL,
+  text TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (poll_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  poll_id UUID REFERENCES polls ON DELETE CASCADE NOT NULL,
+  poll_option_id UUID REFERENCES poll_options ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (poll_option_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_poll_id_user_id_idx ON poll_votes (poll_id, user_id);
\ No newline at end of file
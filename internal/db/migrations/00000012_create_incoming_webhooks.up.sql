@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS incoming_webhooks (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  created_by UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS incoming_webhooks_room_id_idx ON incoming_webhooks (room_id);
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS outgoing_webhooks (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  created_by UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS outgoing_webhooks_room_id_idx ON outgoing_webhooks (room_id);
+
+CREATE TABLE IF NOT EXISTS outgoing_webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  webhook_id UUID REFERENCES outgoing_webhooks ON DELETE CASCADE NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_status_code INT,
+  last_error TEXT NOT NULL DEFAULT '',
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS outgoing_webhook_deliveries_webhook_id_created_at_idx
+ON outgoing_webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS outgoing_webhook_deliveries_due_idx
+ON outgoing_webhook_deliveries (next_attempt_at) WHERE status = 'pending';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS import_mappings;
\ No newline at end of file
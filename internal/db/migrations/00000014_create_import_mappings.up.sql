@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS import_mappings (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  source VARCHAR(100) NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  external_id TEXT NOT NULL,
+  parent_id TEXT NOT NULL DEFAULT '',
+  local_id UUID NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (source, kind, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS import_mappings_parent_idx ON import_mappings (source, kind, parent_id);
\ No newline at end of file
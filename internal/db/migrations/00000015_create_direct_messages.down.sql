@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_blocks;
+DROP INDEX IF EXISTS rooms_kind_idx;
+ALTER TABLE rooms DROP COLUMN IF EXISTS participants_key;
+ALTER TABLE rooms DROP COLUMN IF EXISTS kind;
\ No newline at end of file
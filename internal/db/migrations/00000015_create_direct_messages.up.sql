@@ -0,0 +1,15 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'room';
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS participants_key TEXT UNIQUE;
+
+CREATE INDEX IF NOT EXISTS rooms_kind_idx ON rooms (kind);
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  blocker_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  blocked_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (blocker_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_id_idx ON user_blocks (blocked_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS moderation_audit_logs;
+DROP TABLE IF EXISTS room_moderation_configs;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS room_moderation_configs (
+  room_id UUID PRIMARY KEY REFERENCES rooms ON DELETE CASCADE,
+  config JSONB NOT NULL DEFAULT '{}',
+  updated_by UUID REFERENCES users ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS moderation_audit_logs (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  message_id UUID REFERENCES room_messages ON DELETE SET NULL,
+  filter VARCHAR(50) NOT NULL,
+  action VARCHAR(20) NOT NULL,
+  reason TEXT NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS moderation_audit_logs_room_id_created_at_idx
+ON moderation_audit_logs (room_id, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE rooms
+DROP COLUMN IF EXISTS legal_hold,
+DROP COLUMN IF EXISTS retention_days;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE rooms
+ADD COLUMN IF NOT EXISTS retention_days INT,
+ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS message_drafts;
\ No newline at end of file
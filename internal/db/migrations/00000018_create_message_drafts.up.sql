@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS message_drafts (
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, room_id)
+);
\ No newline at end of file
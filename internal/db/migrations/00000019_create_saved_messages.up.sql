@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS saved_messages (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  room_message_id UUID REFERENCES room_messages ON DELETE CASCADE NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, room_message_id)
+);
+
+CREATE INDEX IF NOT EXISTS saved_messages_user_id_created_at_idx
+ON saved_messages (user_id, created_at DESC, id DESC);
\ No newline at end of file
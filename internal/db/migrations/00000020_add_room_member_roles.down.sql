@@ -0,0 +1 @@
+ALTER TABLE room_members DROP CONSTRAINT IF EXISTS room_members_role_check;
\ No newline at end of file
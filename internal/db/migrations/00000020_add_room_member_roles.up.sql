@@ -0,0 +1,6 @@
+UPDATE room_members SET role = 'owner'
+FROM rooms
+WHERE rooms.id = room_members.room_id AND rooms.created_by = room_members.user_id AND rooms.kind = 'room';
+
+ALTER TABLE room_members
+ADD CONSTRAINT room_members_role_check CHECK (role IN ('owner', 'admin', 'moderator', 'member'));
\ No newline at end of file
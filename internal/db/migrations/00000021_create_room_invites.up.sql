@@ -0,0 +1,19 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'public';
+
+ALTER TABLE rooms
+ADD CONSTRAINT rooms_visibility_check CHECK (visibility IN ('public', 'private'));
+
+CREATE TABLE IF NOT EXISTS room_invites (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  code TEXT NOT NULL UNIQUE,
+  created_by UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  expires_at TIMESTAMPTZ,
+  max_uses INT,
+  uses INT NOT NULL DEFAULT 0,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS room_invites_room_id_idx ON room_invites (room_id);
\ No newline at end of file
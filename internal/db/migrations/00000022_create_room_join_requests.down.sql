@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS room_join_requests;
+
+UPDATE rooms SET visibility = 'private' WHERE visibility = 'restricted';
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_visibility_check;
+
+ALTER TABLE rooms
+ADD CONSTRAINT rooms_visibility_check CHECK (visibility IN ('public', 'private'));
\ No newline at end of file
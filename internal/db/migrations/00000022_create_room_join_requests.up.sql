@@ -0,0 +1,19 @@
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_visibility_check;
+
+ALTER TABLE rooms
+ADD CONSTRAINT rooms_visibility_check CHECK (visibility IN ('public', 'restricted', 'private'));
+
+CREATE TABLE IF NOT EXISTS room_join_requests (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  reviewed_by UUID REFERENCES users ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS room_join_requests_pending_idx
+ON room_join_requests (room_id, user_id) WHERE status = 'pending';
\ No newline at end of file
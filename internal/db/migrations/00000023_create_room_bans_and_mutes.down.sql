@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS room_member_actions;
+DROP TABLE IF EXISTS room_mutes;
+DROP TABLE IF EXISTS room_bans;
\ No newline at end of file
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS room_bans (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  reason TEXT NOT NULL,
+  created_by UUID REFERENCES users ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (room_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS room_mutes (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  reason TEXT NOT NULL,
+  created_by UUID REFERENCES users ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (room_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS room_member_actions (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  room_id UUID REFERENCES rooms ON DELETE CASCADE NOT NULL,
+  user_id UUID REFERENCES users ON DELETE CASCADE NOT NULL,
+  actor_id UUID REFERENCES users ON DELETE SET NULL,
+  action VARCHAR(20) NOT NULL CHECK (action IN ('kick', 'ban', 'unban', 'mute', 'unmute')),
+  reason TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS room_member_actions_room_id_created_at_idx
+ON room_member_actions (room_id, created_at DESC);
\ No newline at end of file
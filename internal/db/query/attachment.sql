@@ -0,0 +1,36 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (room_id, room_message_id, user_id, file_name, content_type, size, storage_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at;
+
+-- name: GetAttachment :one
+SELECT * FROM attachments WHERE id = $1 LIMIT 1;
+
+-- name: GetMessagesAttachments :many
+SELECT * FROM attachments WHERE room_message_id = ANY(sqlc.arg(room_message_ids)::uuid[])
+ORDER BY created_at ASC;
+
+-- name: DeleteAttachment :exec
+DELETE FROM attachments WHERE id = $1;
+
+-- name: GetPendingAttachments :many
+SELECT * FROM attachments WHERE processing_status = 'pending'
+ORDER BY created_at ASC
+LIMIT $1;
+
+-- name: UpdateAttachmentMetadata :exec
+UPDATE attachments
+SET width = $1, height = $2, dominant_color = $3, size = $4, processing_status = $5, updated_at = $6
+WHERE id = $7;
+
+-- name: UpsertAttachmentThumbnail :one
+INSERT INTO attachment_thumbnails (attachment_id, label, width, height, content_type, size, storage_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (attachment_id, label) DO UPDATE
+SET width = EXCLUDED.width, height = EXCLUDED.height, content_type = EXCLUDED.content_type,
+  size = EXCLUDED.size, storage_key = EXCLUDED.storage_key, updated_at = NOW()
+RETURNING id, created_at, updated_at;
+
+-- name: GetAttachmentsThumbnails :many
+SELECT * FROM attachment_thumbnails WHERE attachment_id = ANY(sqlc.arg(attachment_ids)::uuid[])
+ORDER BY width ASC;
\ No newline at end of file
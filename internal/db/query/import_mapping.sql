@@ -0,0 +1,12 @@
+-- name: UpsertImportMapping :one
+INSERT INTO import_mappings (source, kind, external_id, parent_id, local_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (source, kind, external_id) DO UPDATE
+SET parent_id = EXCLUDED.parent_id, local_id = EXCLUDED.local_id, updated_at = NOW()
+RETURNING id, created_at, updated_at;
+
+-- name: GetImportMapping :one
+SELECT * FROM import_mappings WHERE source = $1 AND kind = $2 AND external_id = $3 LIMIT 1;
+
+-- name: DeleteImportMappingsByParent :exec
+DELETE FROM import_mappings WHERE source = $1 AND kind = $2 AND parent_id = $3;
@@ -0,0 +1,17 @@
+-- name: CreateIncomingWebhook :one
+INSERT INTO incoming_webhooks (room_id, user_id, name, token_hash, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at, updated_at;
+
+-- name: GetIncomingWebhook :one
+SELECT * FROM incoming_webhooks WHERE id = $1 LIMIT 1;
+
+-- name: GetIncomingWebhookByTokenHash :one
+SELECT * FROM incoming_webhooks WHERE token_hash = $1 LIMIT 1;
+
+-- name: GetIncomingWebhooks :many
+SELECT * FROM incoming_webhooks WHERE room_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteIncomingWebhook :exec
+DELETE FROM incoming_webhooks WHERE id = $1;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- name: GetLinkPreviewByURL :one
+SELECT * FROM link_previews WHERE url = $1 LIMIT 1;
+
+-- name: UpsertLinkPreview :one
+INSERT INTO link_previews (url, title, description, image_url, site_name)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (url) DO UPDATE
+SET title = EXCLUDED.title, description = EXCLUDED.description, image_url = EXCLUDED.image_url,
+  site_name = EXCLUDED.site_name, updated_at = NOW()
+RETURNING id, created_at, updated_at;
+
+-- name: CreateRoomMessageLinkPreview :exec
+INSERT INTO room_message_link_previews (room_message_id, link_preview_id, position)
+VALUES ($1, $2, $3)
+ON CONFLICT (room_message_id, link_preview_id) DO NOTHING;
+
+-- name: GetMessagesLinkPreviews :many
+SELECT mp.room_message_id, p.id, p.url, p.title, p.description, p.image_url, p.site_name, p.created_at, p.updated_at
+FROM room_message_link_previews mp
+JOIN link_previews p ON p.id = mp.link_preview_id
+WHERE mp.room_message_id = ANY(sqlc.arg(room_message_ids)::uuid[])
+ORDER BY mp.position ASC;
\ No newline at end of file
@@ -0,0 +1,49 @@
+-- name: UpsertRoomBan :one
+INSERT INTO room_bans (room_id, user_id, reason, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (room_id, user_id) DO UPDATE SET
+  reason = EXCLUDED.reason, created_by = EXCLUDED.created_by, expires_at = EXCLUDED.expires_at, updated_at = NOW()
+RETURNING id, created_at, updated_at;
+
+-- name: GetActiveRoomBan :one
+SELECT * FROM room_bans
+WHERE room_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+LIMIT 1;
+
+-- name: GetActiveRoomBans :many
+SELECT * FROM room_bans
+WHERE room_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at DESC;
+
+-- name: DeleteRoomBan :execrows
+DELETE FROM room_bans WHERE room_id = $1 AND user_id = $2;
+
+-- name: UpsertRoomMute :one
+INSERT INTO room_mutes (room_id, user_id, reason, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (room_id, user_id) DO UPDATE SET
+  reason = EXCLUDED.reason, created_by = EXCLUDED.created_by, expires_at = EXCLUDED.expires_at, updated_at = NOW()
+RETURNING id, created_at, updated_at;
+
+-- name: GetActiveRoomMute :one
+SELECT * FROM room_mutes
+WHERE room_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+LIMIT 1;
+
+-- name: GetActiveRoomMutes :many
+SELECT * FROM room_mutes
+WHERE room_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at DESC;
+
+-- name: DeleteRoomMute :execrows
+DELETE FROM room_mutes WHERE room_id = $1 AND user_id = $2;
+
+-- name: CreateRoomMemberAction :one
+INSERT INTO room_member_actions (room_id, user_id, actor_id, action, reason, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at;
+
+-- name: GetRoomMemberActions :many
+SELECT * FROM room_member_actions WHERE room_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- name: GetMessageDraft :one
+SELECT * FROM message_drafts WHERE user_id = $1 AND room_id = $2 LIMIT 1;
+
+-- name: UpsertMessageDraft :one
+INSERT INTO message_drafts (user_id, room_id, content)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, room_id) DO UPDATE SET content = EXCLUDED.content, updated_at = NOW()
+RETURNING created_at, updated_at;
+
+-- name: DeleteMessageDraft :execrows
+DELETE FROM message_drafts WHERE user_id = $1 AND room_id = $2;
\ No newline at end of file
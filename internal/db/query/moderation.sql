@@ -0,0 +1,17 @@
+-- name: GetRoomModerationConfig :one
+SELECT * FROM room_moderation_configs WHERE room_id = $1 LIMIT 1;
+
+-- name: UpsertRoomModerationConfig :one
+INSERT INTO room_moderation_configs (room_id, config, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (room_id) DO UPDATE SET config = EXCLUDED.config, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+RETURNING created_at, updated_at;
+
+-- name: CreateModerationAuditLog :exec
+INSERT INTO moderation_audit_logs (room_id, user_id, message_id, filter, action, reason, content)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetModerationAuditLogs :many
+SELECT * FROM moderation_audit_logs WHERE room_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
\ No newline at end of file
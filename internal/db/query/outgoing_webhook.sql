@@ -0,0 +1,44 @@
+-- name: CreateOutgoingWebhook :one
+INSERT INTO outgoing_webhooks (room_id, url, secret, event_types, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at, updated_at;
+
+-- name: GetOutgoingWebhook :one
+SELECT * FROM outgoing_webhooks WHERE id = $1 LIMIT 1;
+
+-- name: GetOutgoingWebhooks :many
+SELECT * FROM outgoing_webhooks WHERE room_id = $1
+ORDER BY created_at ASC;
+
+-- name: GetRoomEventOutgoingWebhooks :many
+SELECT * FROM outgoing_webhooks
+WHERE room_id = sqlc.arg(room_id) AND sqlc.arg(event_type)::text = ANY(event_types);
+
+-- name: DeleteOutgoingWebhook :exec
+DELETE FROM outgoing_webhooks WHERE id = $1;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO outgoing_webhook_deliveries (webhook_id, event_type, payload)
+VALUES ($1, $2, $3);
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE outgoing_webhook_deliveries SET next_attempt_at = sqlc.arg(lease_until), updated_at = NOW()
+WHERE id IN (
+  SELECT id FROM outgoing_webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= sqlc.arg(due_before)
+  ORDER BY next_attempt_at ASC
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE outgoing_webhook_deliveries
+SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4,
+  last_error = $5, delivered_at = $6, updated_at = $7
+WHERE id = $8;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM outgoing_webhook_deliveries WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- name: CreatePoll :one
+INSERT INTO polls (room_id, room_message_id, created_by, question, multiple_choice, anonymous, closes_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at;
+
+-- name: CreatePollOption :one
+INSERT INTO poll_options (poll_id, position, text)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, updated_at;
+
+-- name: GetPoll :one
+SELECT * FROM polls WHERE id = $1 LIMIT 1;
+
+-- name: GetPollForUpdate :one
+SELECT * FROM polls WHERE id = $1 LIMIT 1 FOR UPDATE;
+
+-- name: GetMessagesPolls :many
+SELECT * FROM polls WHERE room_message_id = ANY(sqlc.arg(room_message_ids)::uuid[]);
+
+-- name: GetPollsOptions :many
+SELECT * FROM poll_options WHERE poll_id = ANY(sqlc.arg(poll_ids)::uuid[])
+ORDER BY position ASC;
+
+-- name: GetPollsVotes :many
+SELECT * FROM poll_votes WHERE poll_id = ANY(sqlc.arg(poll_ids)::uuid[])
+ORDER BY created_at ASC;
+
+-- name: CreatePollVote :exec
+INSERT INTO poll_votes (poll_id, poll_option_id, user_id)
+VALUES ($1, $2, $3);
+
+-- name: DeletePollVotes :exec
+DELETE FROM poll_votes WHERE poll_id = $1 AND user_id = $2;
+
+-- name: ClosePoll :exec
+UPDATE polls SET closed_at = $1, updated_at = $1 WHERE id = $2;
\ No newline at end of file
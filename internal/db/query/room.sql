@@ -1,23 +1,58 @@
 -- name: CreateRoom :one
-INSERT INTO rooms (name, description, max_members, created_by)
-VALUES ($1, $2, $3, $4)
+INSERT INTO rooms (name, description, max_members, created_by, message_ttl, visibility)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING id, created_at, updated_at;
 
 -- name: GetRoom :one
 SELECT * FROM rooms WHERE id = $1 LIMIT 1;
 
+-- name: GetRoomForUpdate :one
+SELECT * FROM rooms WHERE id = $1 LIMIT 1 FOR UPDATE;
+
 -- name: GetRooms :many
-SELECT * FROM rooms WHERE created_by = COALESCE(sqlc.narg(created_by), created_by);
+SELECT * FROM rooms WHERE kind = 'room' AND created_by = COALESCE(sqlc.narg(created_by), created_by) AND (
+  visibility <> 'private' OR
+  EXISTS (SELECT 1 FROM room_members WHERE room_members.room_id = rooms.id AND room_members.user_id = sqlc.narg(viewer_id))
+);
+
+-- name: CreateDirectRoom :one
+INSERT INTO rooms (name, max_members, created_by, kind, participants_key)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (participants_key) DO NOTHING
+RETURNING id, created_at, updated_at;
+
+-- name: GetRoomByParticipantsKey :one
+SELECT * FROM rooms WHERE participants_key = $1 LIMIT 1;
+
+-- name: GetUserConversations :many
+SELECT rooms.* FROM rooms
+JOIN room_members ON room_members.room_id = rooms.id AND room_members.user_id = $1
+WHERE rooms.kind <> 'room'
+ORDER BY rooms.updated_at DESC, rooms.id DESC;
+
+-- name: GetConversationParticipants :many
+SELECT room_members.room_id, users.* FROM room_members
+JOIN users ON users.id = room_members.user_id
+WHERE room_members.room_id = ANY(sqlc.arg(room_ids)::uuid[])
+ORDER BY room_members.created_at ASC;
 
 -- name: DeleteRoom :exec
 DELETE FROM rooms WHERE id = $1;
 
+-- name: UpdateConversation :exec
+UPDATE rooms SET updated_at = $1, kind = $2, participants_key = $3, name = $4, max_members = $5, visibility = $6
+WHERE id = $7;
+
 -- name: UpdateRoom :exec
-UPDATE rooms SET updated_at = $1, name = $2, description = $3, max_members = $4
-WHERE id = $5;
+UPDATE rooms SET updated_at = $1, name = $2, description = $3, max_members = $4, message_ttl = $5, visibility = $6
+WHERE id = $7;
+
+-- name: UpdateRoomRetention :exec
+UPDATE rooms SET updated_at = $1, retention_days = $2, legal_hold = $3
+WHERE id = $4;
 
 -- name: CreateRoomMember :one
-INSERT INTO room_members (room_id, user_id) VALUES($1, $2)
+INSERT INTO room_members (room_id, user_id, role) VALUES($1, $2, $3)
 RETURNING id, created_at, updated_at;
 
 -- name: GetRoomMember :one
@@ -26,9 +61,17 @@ SELECT * FROM room_members WHERE id = $1 LIMIT 1;
 -- name: GetRoomMemberByWhere :one
 SELECT * FROM room_members WHERE user_id = $1 AND room_id = $2;
 
+-- name: GetRoomMemberUsers :many
+SELECT users.* FROM room_members
+JOIN users ON users.id = room_members.user_id
+WHERE room_members.room_id = $1 AND room_members.user_id = ANY(sqlc.arg(user_ids)::uuid[]);
+
 -- name: GetRoomMembers :many
 SELECT * FROM room_members WHERE room_id = COALESCE(sqlc.narg(room_id), room_id) AND user_id = COALESCE(sqlc.narg(user_id), user_id);
 
+-- name: UpdateRoomMemberRole :exec
+UPDATE room_members SET role = $1, updated_at = $2 WHERE id = $3;
+
 -- name: DeleteRoomMember :exec
 DELETE FROM room_members WHERE id = $1;
 
@@ -36,8 +79,13 @@ DELETE FROM room_members WHERE id = $1;
 SELECT COUNT(*) AS count FROM room_members WHERE room_id = $1;
 
 -- name: CreateRoomMessage :one
-INSERT INTO room_messages (room_id, room_member_id, user_id, content)
-VALUES ($1, $2, $3, $4)
+INSERT INTO room_messages (room_id, room_member_id, user_id, content, content_html, entities, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at;
+
+-- name: CreateImportedRoomMessage :one
+INSERT INTO room_messages (room_id, room_member_id, user_id, content, content_html, entities, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, sqlc.arg(created_at), sqlc.arg(created_at))
 RETURNING id, created_at, updated_at;
 
 -- name: GetRoomMessage :one
@@ -49,5 +97,76 @@ SELECT * FROM room_messages WHERE
   room_member_id = COALESCE(sqlc.narg(room_member_id), room_member_id) AND
   user_id = COALESCE(sqlc.narg(user_id), user_id);
 
+-- name: GetRoomMessagesBefore :many
+SELECT * FROM room_messages
+WHERE room_id = sqlc.arg(room_id) AND (expires_at IS NULL OR expires_at > NOW()) AND (
+  sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+  (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetRoomMessagesAfter :many
+SELECT * FROM room_messages
+WHERE room_id = sqlc.arg(room_id) AND (expires_at IS NULL OR expires_at > NOW()) AND
+  (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetRoomTranscript :many
+SELECT room_messages.id, room_messages.created_at, room_messages.user_id, room_messages.content, users.first_name, users.last_name
+FROM room_messages
+JOIN users ON users.id = room_messages.user_id
+WHERE room_messages.room_id = sqlc.arg(room_id) AND
+  (room_messages.expires_at IS NULL OR room_messages.expires_at > NOW()) AND (
+  sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+  (room_messages.created_at, room_messages.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY room_messages.created_at ASC, room_messages.id ASC
+LIMIT sqlc.arg(page_limit);
+
 -- name: DeleteRoomMessage :exec
-DELETE FROM room_messages WHERE id = $1;
\ No newline at end of file
+DELETE FROM room_messages WHERE id = $1;
+
+-- name: GetExpiredRoomMessages :many
+SELECT room_messages.* FROM room_messages
+JOIN rooms ON rooms.id = room_messages.room_id
+WHERE NOT rooms.legal_hold
+  AND room_messages.expires_at <= sqlc.arg(expired_before)
+ORDER BY room_messages.expires_at ASC
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE OF room_messages SKIP LOCKED;
+
+-- name: GetRetentionExpiredMessages :many
+SELECT room_messages.* FROM room_messages
+JOIN rooms ON rooms.id = room_messages.room_id
+WHERE NOT rooms.legal_hold
+  AND COALESCE(rooms.retention_days, sqlc.arg(default_retention_days)::int) > 0
+  AND room_messages.created_at < NOW() - make_interval(days => COALESCE(rooms.retention_days, sqlc.arg(default_retention_days)::int))
+ORDER BY room_messages.created_at ASC
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE OF room_messages SKIP LOCKED;
+
+-- name: DeleteRoomMessages :exec
+DELETE FROM room_messages WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: CreateRoomPin :one
+INSERT INTO room_pins (room_id, room_message_id, pinned_by)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, updated_at;
+
+-- name: GetRoomPinByWhere :one
+SELECT * FROM room_pins WHERE room_id = $1 AND room_message_id = $2 LIMIT 1;
+
+-- name: GetRoomPins :many
+SELECT sqlc.embed(room_pins), sqlc.embed(room_messages)
+FROM room_pins
+JOIN room_messages ON room_messages.id = room_pins.room_message_id
+WHERE room_pins.room_id = $1
+ORDER BY room_pins.created_at DESC;
+
+-- name: RoomPinsCount :one
+SELECT COUNT(*) AS count FROM room_pins WHERE room_id = $1;
+
+-- name: DeleteRoomPin :exec
+DELETE FROM room_pins WHERE id = $1;
\ No newline at end of file
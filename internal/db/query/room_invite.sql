@@ -0,0 +1,21 @@
+-- name: CreateRoomInvite :one
+INSERT INTO room_invites (room_id, code, created_by, expires_at, max_uses)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at, updated_at;
+
+-- name: GetRoomInvite :one
+SELECT * FROM room_invites WHERE id = $1 LIMIT 1;
+
+-- name: GetRoomInviteByCode :one
+SELECT * FROM room_invites WHERE code = $1 LIMIT 1;
+
+-- name: GetRoomInvites :many
+SELECT * FROM room_invites WHERE room_id = $1
+ORDER BY created_at DESC;
+
+-- name: UseRoomInvite :execrows
+UPDATE room_invites SET uses = uses + 1, updated_at = NOW()
+WHERE id = $1 AND (max_uses IS NULL OR uses < max_uses);
+
+-- name: RevokeRoomInvite :exec
+UPDATE room_invites SET revoked_at = $1, updated_at = $1 WHERE id = $2;
\ No newline at end of file
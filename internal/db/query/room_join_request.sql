@@ -0,0 +1,18 @@
+-- name: CreateRoomJoinRequest :one
+INSERT INTO room_join_requests (room_id, user_id, message)
+VALUES ($1, $2, $3)
+RETURNING id, status, created_at, updated_at;
+
+-- name: GetRoomJoinRequest :one
+SELECT * FROM room_join_requests WHERE id = $1 LIMIT 1;
+
+-- name: GetPendingRoomJoinRequest :one
+SELECT * FROM room_join_requests WHERE room_id = $1 AND user_id = $2 AND status = 'pending' LIMIT 1;
+
+-- name: GetRoomJoinRequests :many
+SELECT * FROM room_join_requests WHERE room_id = $1 AND status = $2
+ORDER BY created_at ASC;
+
+-- name: ReviewRoomJoinRequest :execrows
+UPDATE room_join_requests SET status = $1, reviewed_by = $2, reviewed_at = $3, updated_at = $3
+WHERE id = $4 AND status = 'pending';
\ No newline at end of file
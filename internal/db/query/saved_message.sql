@@ -0,0 +1,22 @@
+-- name: UpsertSavedMessage :one
+INSERT INTO saved_messages (user_id, room_message_id, note)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, room_message_id) DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteSavedMessage :execrows
+DELETE FROM saved_messages WHERE user_id = $1 AND room_message_id = $2;
+
+-- name: GetSavedMessages :many
+SELECT saved_messages.id, saved_messages.note, saved_messages.created_at, saved_messages.updated_at, sqlc.embed(room_messages), sqlc.embed(rooms)
+FROM saved_messages
+JOIN room_messages ON room_messages.id = saved_messages.room_message_id
+JOIN rooms ON rooms.id = room_messages.room_id
+JOIN room_members ON room_members.room_id = rooms.id AND room_members.user_id = saved_messages.user_id
+WHERE saved_messages.user_id = sqlc.arg(user_id) AND
+  (room_messages.expires_at IS NULL OR room_messages.expires_at > NOW()) AND (
+  sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+  (saved_messages.created_at, saved_messages.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY saved_messages.created_at DESC, saved_messages.id DESC
+LIMIT sqlc.arg(page_limit);
\ No newline at end of file
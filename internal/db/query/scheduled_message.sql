@@ -0,0 +1,29 @@
+-- name: CreateScheduledMessage :one
+INSERT INTO scheduled_messages (room_id, user_id, content, scheduled_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, status, created_at, updated_at;
+
+-- name: GetScheduledMessage :one
+SELECT * FROM scheduled_messages WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledMessages :many
+SELECT * FROM scheduled_messages WHERE room_id = $1 AND user_id = $2
+ORDER BY scheduled_at ASC;
+
+-- name: UpdateScheduledMessage :execrows
+UPDATE scheduled_messages SET content = $1, scheduled_at = $2, updated_at = $3
+WHERE id = $4 AND status = 'pending';
+
+-- name: DeleteScheduledMessage :execrows
+DELETE FROM scheduled_messages WHERE id = $1 AND status = 'pending';
+
+-- name: GetDueScheduledMessages :many
+SELECT * FROM scheduled_messages
+WHERE status = 'pending' AND scheduled_at <= sqlc.arg(due_before)
+ORDER BY scheduled_at ASC
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateScheduledMessageStatus :exec
+UPDATE scheduled_messages SET status = $1, room_message_id = $2, updated_at = $3
+WHERE id = $4;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- name: SearchRoomMessages :many
+SELECT
+  m.id, m.room_id, m.user_id, m.content, m.created_at,
+  ts_rank(m.content_tsv, websearch_to_tsquery('english', sqlc.arg(query)))::real AS rank,
+  ts_headline(
+    'english', m.content, websearch_to_tsquery('english', sqlc.arg(query)),
+    'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=20, MinWords=5'
+  )::text AS snippet
+FROM room_messages m
+JOIN room_members rm ON rm.room_id = m.room_id AND rm.user_id = sqlc.arg(member_id)
+WHERE m.content_tsv @@ websearch_to_tsquery('english', sqlc.arg(query)) AND
+  (m.expires_at IS NULL OR m.expires_at > NOW()) AND
+  m.room_id = COALESCE(sqlc.narg(room_id), m.room_id) AND
+  m.user_id = COALESCE(sqlc.narg(user_id), m.user_id) AND
+  m.created_at >= COALESCE(sqlc.narg(created_after)::timestamptz, m.created_at) AND
+  m.created_at <= COALESCE(sqlc.narg(created_before)::timestamptz, m.created_at)
+ORDER BY rank DESC, m.created_at DESC, m.id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,18 @@
+-- name: CreateUserBlock :exec
+INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: DeleteUserBlock :exec
+DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: GetBlockedUsers :many
+SELECT users.* FROM user_blocks
+JOIN users ON users.id = user_blocks.blocked_id
+WHERE user_blocks.blocker_id = $1
+ORDER BY user_blocks.created_at DESC;
+
+-- name: UsersBlocked :one
+SELECT EXISTS (
+  SELECT 1 FROM user_blocks
+  WHERE blocker_id = ANY(sqlc.arg(user_ids)::uuid[]) AND blocked_id = ANY(sqlc.arg(user_ids)::uuid[])
+) AS blocked;
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type attachmentRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewAttachmentRepository(conn *pgxpool.Pool) *attachmentRepository {
+	return &attachmentRepository{conn}
+}
+
+func (r *attachmentRepository) CreateAttachment(attachment *models.Attachment, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_attachment, err := ds.CreateAttachment(context.Background(), dataSource.CreateAttachmentParams{
+		RoomID:        utils.StringToUUID(attachment.RoomID),
+		RoomMessageID: utils.StringToUUID(attachment.RoomMessageID),
+		UserID:        utils.StringToUUID(attachment.UserID),
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		Size:          attachment.Size,
+		StorageKey:    attachment.StorageKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	attachment.ID = utils.UUIDToString(_attachment.ID)
+	attachment.CreatedAt = _attachment.CreatedAt
+	attachment.UpdatedAt = _attachment.UpdatedAt
+
+	return nil
+}
+
+func (r *attachmentRepository) GetAttachment(id string, tx pgx.Tx) (*models.Attachment, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_attachment, err := ds.GetAttachment(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toAttachmentModel(_attachment), nil
+}
+
+func (r *attachmentRepository) GetMessagesAttachments(messageIds []string, tx pgx.Tx) ([]*models.Attachment, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range messageIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	_attachments, err := ds.GetMessagesAttachments(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []*models.Attachment{}
+	for _, attachment := range _attachments {
+		attachments = append(attachments, toAttachmentModel(attachment))
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(id string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteAttachment(context.Background(), utils.StringToUUID(id))
+}
+
+func (r *attachmentRepository) GetPendingAttachments(limit int, tx pgx.Tx) ([]*models.Attachment, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_attachments, err := ds.GetPendingAttachments(context.Background(), int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []*models.Attachment{}
+	for _, attachment := range _attachments {
+		attachments = append(attachments, toAttachmentModel(attachment))
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) UpdateAttachmentMetadata(attachment *models.Attachment, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	params := dataSource.UpdateAttachmentMetadataParams{
+		Size:             attachment.Size,
+		ProcessingStatus: attachment.ProcessingStatus,
+		UpdatedAt:        time.Now(),
+		ID:               utils.StringToUUID(attachment.ID),
+	}
+	if attachment.Width > 0 && attachment.Height > 0 {
+		params.Width = pgtype.Int4{Int32: int32(attachment.Width), Valid: true}
+		params.Height = pgtype.Int4{Int32: int32(attachment.Height), Valid: true}
+	}
+	if attachment.DominantColor != "" {
+		params.DominantColor = pgtype.Text{String: attachment.DominantColor, Valid: true}
+	}
+
+	err := ds.UpdateAttachmentMetadata(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	attachment.UpdatedAt = params.UpdatedAt
+	return nil
+}
+
+func (r *attachmentRepository) UpsertAttachmentThumbnail(thumbnail *models.AttachmentThumbnail, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_thumbnail, err := ds.UpsertAttachmentThumbnail(context.Background(), dataSource.UpsertAttachmentThumbnailParams{
+		AttachmentID: utils.StringToUUID(thumbnail.AttachmentID),
+		Label:        thumbnail.Label,
+		Width:        int32(thumbnail.Width),
+		Height:       int32(thumbnail.Height),
+		ContentType:  thumbnail.ContentType,
+		Size:         thumbnail.Size,
+		StorageKey:   thumbnail.StorageKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	thumbnail.ID = utils.UUIDToString(_thumbnail.ID)
+	thumbnail.CreatedAt = _thumbnail.CreatedAt
+	thumbnail.UpdatedAt = _thumbnail.UpdatedAt
+
+	return nil
+}
+
+func (r *attachmentRepository) GetAttachmentsThumbnails(attachmentIds []string, tx pgx.Tx) ([]*models.AttachmentThumbnail, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range attachmentIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	_thumbnails, err := ds.GetAttachmentsThumbnails(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := []*models.AttachmentThumbnail{}
+	for _, thumbnail := range _thumbnails {
+		thumbnails = append(thumbnails, &models.AttachmentThumbnail{
+			ID:           utils.UUIDToString(thumbnail.ID),
+			CreatedAt:    thumbnail.CreatedAt,
+			UpdatedAt:    thumbnail.UpdatedAt,
+			AttachmentID: utils.UUIDToString(thumbnail.AttachmentID),
+			Label:        thumbnail.Label,
+			Width:        int(thumbnail.Width),
+			Height:       int(thumbnail.Height),
+			ContentType:  thumbnail.ContentType,
+			Size:         thumbnail.Size,
+			StorageKey:   thumbnail.StorageKey,
+		})
+	}
+
+	return thumbnails, nil
+}
+
+func toAttachmentModel(attachment dataSource.Attachment) *models.Attachment {
+	return &models.Attachment{
+		ID:               utils.UUIDToString(attachment.ID),
+		CreatedAt:        attachment.CreatedAt,
+		UpdatedAt:        attachment.UpdatedAt,
+		RoomID:           utils.UUIDToString(attachment.RoomID),
+		RoomMessageID:    utils.UUIDToString(attachment.RoomMessageID),
+		UserID:           utils.UUIDToString(attachment.UserID),
+		FileName:         attachment.FileName,
+		ContentType:      attachment.ContentType,
+		Size:             attachment.Size,
+		StorageKey:       attachment.StorageKey,
+		Width:            int(attachment.Width.Int32),
+		Height:           int(attachment.Height.Int32),
+		DominantColor:    attachment.DominantColor.String,
+		ProcessingStatus: attachment.ProcessingStatus,
+	}
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type importMappingRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewImportMappingRepository(conn *pgxpool.Pool) *importMappingRepository {
+	return &importMappingRepository{conn}
+}
+
+func (r *importMappingRepository) UpsertImportMapping(mapping *models.ImportMapping, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_mapping, err := ds.UpsertImportMapping(context.Background(), dataSource.UpsertImportMappingParams{
+		Source:     mapping.Source,
+		Kind:       mapping.Kind,
+		ExternalID: mapping.ExternalID,
+		ParentID:   mapping.ParentID,
+		LocalID:    utils.StringToUUID(mapping.LocalID),
+	})
+	if err != nil {
+		return err
+	}
+
+	mapping.ID = utils.UUIDToString(_mapping.ID)
+	mapping.CreatedAt = _mapping.CreatedAt
+	mapping.UpdatedAt = _mapping.UpdatedAt
+
+	return nil
+}
+
+func (r *importMappingRepository) GetImportMapping(source, kind, externalId string, tx pgx.Tx) (*models.ImportMapping, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_mapping, err := ds.GetImportMapping(context.Background(), dataSource.GetImportMappingParams{
+		Source:     source,
+		Kind:       kind,
+		ExternalID: externalId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ImportMapping{
+		ID:         utils.UUIDToString(_mapping.ID),
+		CreatedAt:  _mapping.CreatedAt,
+		UpdatedAt:  _mapping.UpdatedAt,
+		Source:     _mapping.Source,
+		Kind:       _mapping.Kind,
+		ExternalID: _mapping.ExternalID,
+		ParentID:   _mapping.ParentID,
+		LocalID:    utils.UUIDToString(_mapping.LocalID),
+	}, nil
+}
+
+func (r *importMappingRepository) DeleteImportMappingsByParent(source, kind, parentId string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteImportMappingsByParent(context.Background(), dataSource.DeleteImportMappingsByParentParams{
+		Source:   source,
+		Kind:     kind,
+		ParentID: parentId,
+	})
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type incomingWebhookRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewIncomingWebhookRepository(conn *pgxpool.Pool) *incomingWebhookRepository {
+	return &incomingWebhookRepository{conn}
+}
+
+func (r *incomingWebhookRepository) CreateIncomingWebhook(webhook *models.IncomingWebhook, tokenHash string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhook, err := ds.CreateIncomingWebhook(context.Background(), dataSource.CreateIncomingWebhookParams{
+		RoomID:    utils.StringToUUID(webhook.RoomID),
+		UserID:    utils.StringToUUID(webhook.UserID),
+		Name:      webhook.Name,
+		TokenHash: tokenHash,
+		CreatedBy: utils.StringToUUID(webhook.CreatedBy),
+	})
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = utils.UUIDToString(_webhook.ID)
+	webhook.CreatedAt = _webhook.CreatedAt
+	webhook.UpdatedAt = _webhook.UpdatedAt
+
+	return nil
+}
+
+func (r *incomingWebhookRepository) GetIncomingWebhook(id string, tx pgx.Tx) (*models.IncomingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhook, err := ds.GetIncomingWebhook(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toIncomingWebhookModel(_webhook), nil
+}
+
+func (r *incomingWebhookRepository) GetIncomingWebhookByTokenHash(tokenHash string, tx pgx.Tx) (*models.IncomingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhook, err := ds.GetIncomingWebhookByTokenHash(context.Background(), tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return toIncomingWebhookModel(_webhook), nil
+}
+
+func (r *incomingWebhookRepository) GetIncomingWebhooks(roomId string, tx pgx.Tx) ([]*models.IncomingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhooks, err := ds.GetIncomingWebhooks(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []*models.IncomingWebhook{}
+	for _, webhook := range _webhooks {
+		webhooks = append(webhooks, toIncomingWebhookModel(webhook))
+	}
+
+	return webhooks, nil
+}
+
+func (r *incomingWebhookRepository) DeleteIncomingWebhook(id string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteIncomingWebhook(context.Background(), utils.StringToUUID(id))
+}
+
+func toIncomingWebhookModel(webhook dataSource.IncomingWebhook) *models.IncomingWebhook {
+	return &models.IncomingWebhook{
+		ID:        utils.UUIDToString(webhook.ID),
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+		RoomID:    utils.UUIDToString(webhook.RoomID),
+		UserID:    utils.UUIDToString(webhook.UserID),
+		Name:      webhook.Name,
+		CreatedBy: utils.UUIDToString(webhook.CreatedBy),
+	}
+}
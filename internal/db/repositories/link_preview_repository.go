@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type linkPreviewRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewLinkPreviewRepository(conn *pgxpool.Pool) *linkPreviewRepository {
+	return &linkPreviewRepository{conn}
+}
+
+func (r *linkPreviewRepository) GetLinkPreviewByURL(url string, tx pgx.Tx) (*models.LinkPreview, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_preview, err := ds.GetLinkPreviewByURL(context.Background(), url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LinkPreview{
+		ID:          utils.UUIDToString(_preview.ID),
+		CreatedAt:   _preview.CreatedAt,
+		UpdatedAt:   _preview.UpdatedAt,
+		URL:         _preview.Url,
+		Title:       _preview.Title,
+		Description: _preview.Description,
+		ImageURL:    _preview.ImageUrl,
+		SiteName:    _preview.SiteName,
+	}, nil
+}
+
+func (r *linkPreviewRepository) UpsertLinkPreview(preview *models.LinkPreview, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_preview, err := ds.UpsertLinkPreview(context.Background(), dataSource.UpsertLinkPreviewParams{
+		Url:         preview.URL,
+		Title:       preview.Title,
+		Description: preview.Description,
+		ImageUrl:    preview.ImageURL,
+		SiteName:    preview.SiteName,
+	})
+	if err != nil {
+		return err
+	}
+
+	preview.ID = utils.UUIDToString(_preview.ID)
+	preview.CreatedAt = _preview.CreatedAt
+	preview.UpdatedAt = _preview.UpdatedAt
+
+	return nil
+}
+
+func (r *linkPreviewRepository) CreateRoomMessageLinkPreview(messageId, previewId string, position int, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.CreateRoomMessageLinkPreview(context.Background(), dataSource.CreateRoomMessageLinkPreviewParams{
+		RoomMessageID: utils.StringToUUID(messageId),
+		LinkPreviewID: utils.StringToUUID(previewId),
+		Position:      int32(position),
+	})
+}
+
+// GetMessagesLinkPreviews returns the previews of each message, keyed by
+// message ID and in the order their links appear in the message.
+func (r *linkPreviewRepository) GetMessagesLinkPreviews(messageIds []string, tx pgx.Tx) (map[string][]*models.LinkPreview, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range messageIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	rows, err := ds.GetMessagesLinkPreviews(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := map[string][]*models.LinkPreview{}
+	for _, row := range rows {
+		messageId := utils.UUIDToString(row.RoomMessageID)
+		previews[messageId] = append(previews[messageId], &models.LinkPreview{
+			ID:          utils.UUIDToString(row.ID),
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			URL:         row.Url,
+			Title:       row.Title,
+			Description: row.Description,
+			ImageURL:    row.ImageUrl,
+			SiteName:    row.SiteName,
+		})
+	}
+
+	return previews, nil
+}
@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type memberModerationRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewMemberModerationRepository(conn *pgxpool.Pool) *memberModerationRepository {
+	return &memberModerationRepository{conn}
+}
+
+// UpsertRoomBan bans the user, replacing the reason and expiry of any ban
+// they already have in the room.
+func (r *memberModerationRepository) UpsertRoomBan(ban *models.RoomBan, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.UpsertRoomBan(context.Background(), dataSource.UpsertRoomBanParams{
+		RoomID:    utils.StringToUUID(ban.RoomID),
+		UserID:    utils.StringToUUID(ban.UserID),
+		Reason:    ban.Reason,
+		CreatedBy: utils.StringPtrToUUID(&ban.CreatedBy),
+		ExpiresAt: timeToTimestamptz(ban.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	ban.ID = utils.UUIDToString(row.ID)
+	ban.CreatedAt = row.CreatedAt
+	ban.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *memberModerationRepository) GetActiveRoomBan(roomId, userId string, tx pgx.Tx) (*models.RoomBan, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ban, err := ds.GetActiveRoomBan(context.Background(), dataSource.GetActiveRoomBanParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomBanModel(ban), nil
+}
+
+func (r *memberModerationRepository) GetActiveRoomBans(roomId string, tx pgx.Tx) ([]*models.RoomBan, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_bans, err := ds.GetActiveRoomBans(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	bans := []*models.RoomBan{}
+	for _, ban := range _bans {
+		bans = append(bans, toRoomBanModel(ban))
+	}
+
+	return bans, nil
+}
+
+// DeleteRoomBan lifts the user's ban, reporting false if they had none.
+func (r *memberModerationRepository) DeleteRoomBan(roomId, userId string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	n, err := ds.DeleteRoomBan(context.Background(), dataSource.DeleteRoomBanParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	return n > 0, err
+}
+
+// UpsertRoomMute mutes the user, replacing the reason and expiry of any mute
+// they already have in the room.
+func (r *memberModerationRepository) UpsertRoomMute(mute *models.RoomMute, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.UpsertRoomMute(context.Background(), dataSource.UpsertRoomMuteParams{
+		RoomID:    utils.StringToUUID(mute.RoomID),
+		UserID:    utils.StringToUUID(mute.UserID),
+		Reason:    mute.Reason,
+		CreatedBy: utils.StringPtrToUUID(&mute.CreatedBy),
+		ExpiresAt: timeToTimestamptz(mute.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	mute.ID = utils.UUIDToString(row.ID)
+	mute.CreatedAt = row.CreatedAt
+	mute.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *memberModerationRepository) GetActiveRoomMute(roomId, userId string, tx pgx.Tx) (*models.RoomMute, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	mute, err := ds.GetActiveRoomMute(context.Background(), dataSource.GetActiveRoomMuteParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomMuteModel(mute), nil
+}
+
+func (r *memberModerationRepository) GetActiveRoomMutes(roomId string, tx pgx.Tx) ([]*models.RoomMute, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_mutes, err := ds.GetActiveRoomMutes(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	mutes := []*models.RoomMute{}
+	for _, mute := range _mutes {
+		mutes = append(mutes, toRoomMuteModel(mute))
+	}
+
+	return mutes, nil
+}
+
+// DeleteRoomMute lifts the user's mute, reporting false if they had none.
+func (r *memberModerationRepository) DeleteRoomMute(roomId, userId string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	n, err := ds.DeleteRoomMute(context.Background(), dataSource.DeleteRoomMuteParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	return n > 0, err
+}
+
+func (r *memberModerationRepository) CreateRoomMemberAction(action *models.RoomMemberAction, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.CreateRoomMemberAction(context.Background(), dataSource.CreateRoomMemberActionParams{
+		RoomID:    utils.StringToUUID(action.RoomID),
+		UserID:    utils.StringToUUID(action.UserID),
+		ActorID:   utils.StringPtrToUUID(&action.ActorID),
+		Action:    action.Action,
+		Reason:    action.Reason,
+		ExpiresAt: timeToTimestamptz(action.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	action.ID = utils.UUIDToString(row.ID)
+	action.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *memberModerationRepository) GetRoomMemberActions(roomId string, limit int, tx pgx.Tx) ([]*models.RoomMemberAction, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_actions, err := ds.GetRoomMemberActions(context.Background(), dataSource.GetRoomMemberActionsParams{
+		RoomID: utils.StringToUUID(roomId),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []*models.RoomMemberAction{}
+	for _, action := range _actions {
+		actions = append(actions, &models.RoomMemberAction{
+			ID:        utils.UUIDToString(action.ID),
+			CreatedAt: action.CreatedAt,
+			RoomID:    utils.UUIDToString(action.RoomID),
+			UserID:    utils.UUIDToString(action.UserID),
+			ActorID:   utils.NullUUIDToString(action.ActorID),
+			Action:    action.Action,
+			Reason:    action.Reason,
+			ExpiresAt: timestamptzToTime(action.ExpiresAt),
+		})
+	}
+
+	return actions, nil
+}
+
+func toRoomBanModel(ban dataSource.RoomBan) *models.RoomBan {
+	return &models.RoomBan{
+		ID:        utils.UUIDToString(ban.ID),
+		CreatedAt: ban.CreatedAt,
+		UpdatedAt: ban.UpdatedAt,
+		RoomID:    utils.UUIDToString(ban.RoomID),
+		UserID:    utils.UUIDToString(ban.UserID),
+		Reason:    ban.Reason,
+		CreatedBy: utils.NullUUIDToString(ban.CreatedBy),
+		ExpiresAt: timestamptzToTime(ban.ExpiresAt),
+	}
+}
+
+func toRoomMuteModel(mute dataSource.RoomMute) *models.RoomMute {
+	return &models.RoomMute{
+		ID:        utils.UUIDToString(mute.ID),
+		CreatedAt: mute.CreatedAt,
+		UpdatedAt: mute.UpdatedAt,
+		RoomID:    utils.UUIDToString(mute.RoomID),
+		UserID:    utils.UUIDToString(mute.UserID),
+		Reason:    mute.Reason,
+		CreatedBy: utils.NullUUIDToString(mute.CreatedBy),
+		ExpiresAt: timestamptzToTime(mute.ExpiresAt),
+	}
+}
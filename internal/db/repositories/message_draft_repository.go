@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type messageDraftRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewMessageDraftRepository(conn *pgxpool.Pool) *messageDraftRepository {
+	return &messageDraftRepository{conn}
+}
+
+func (r *messageDraftRepository) GetMessageDraft(userId, roomId string, tx pgx.Tx) (*models.MessageDraft, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	draft, err := ds.GetMessageDraft(context.Background(), dataSource.GetMessageDraftParams{
+		UserID: utils.StringToUUID(userId),
+		RoomID: utils.StringToUUID(roomId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MessageDraft{
+		UserID:    utils.UUIDToString(draft.UserID),
+		RoomID:    utils.UUIDToString(draft.RoomID),
+		Content:   draft.Content,
+		CreatedAt: draft.CreatedAt,
+		UpdatedAt: draft.UpdatedAt,
+	}, nil
+}
+
+func (r *messageDraftRepository) SaveMessageDraft(draft *models.MessageDraft, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.UpsertMessageDraft(context.Background(), dataSource.UpsertMessageDraftParams{
+		UserID:  utils.StringToUUID(draft.UserID),
+		RoomID:  utils.StringToUUID(draft.RoomID),
+		Content: draft.Content,
+	})
+	if err != nil {
+		return err
+	}
+
+	draft.CreatedAt = row.CreatedAt
+	draft.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// DeleteMessageDraft removes the user's draft in the room and reports
+// whether there was one.
+func (r *messageDraftRepository) DeleteMessageDraft(userId, roomId string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	n, err := ds.DeleteMessageDraft(context.Background(), dataSource.DeleteMessageDraftParams{
+		UserID: utils.StringToUUID(userId),
+		RoomID: utils.StringToUUID(roomId),
+	})
+	return n > 0, err
+}
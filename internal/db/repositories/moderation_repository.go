@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/moderation"
+	"github.com/princecee/go_chat/utils"
+)
+
+type moderationRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewModerationRepository(conn *pgxpool.Pool) *moderationRepository {
+	return &moderationRepository{conn}
+}
+
+func (r *moderationRepository) GetRoomModeration(roomId string, tx pgx.Tx) (*models.RoomModeration, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_config, err := ds.GetRoomModerationConfig(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &moderation.Config{}
+	if err := json.Unmarshal(_config.Config, config); err != nil {
+		return nil, err
+	}
+
+	return &models.RoomModeration{
+		RoomID:    utils.UUIDToString(_config.RoomID),
+		Config:    config,
+		UpdatedBy: utils.NullUUIDToString(_config.UpdatedBy),
+		CreatedAt: _config.CreatedAt,
+		UpdatedAt: _config.UpdatedAt,
+	}, nil
+}
+
+func (r *moderationRepository) SaveRoomModeration(settings *models.RoomModeration, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	config, err := json.Marshal(settings.Config)
+	if err != nil {
+		return err
+	}
+
+	var updatedBy *string
+	if settings.UpdatedBy != "" {
+		updatedBy = &settings.UpdatedBy
+	}
+
+	row, err := ds.UpsertRoomModerationConfig(context.Background(), dataSource.UpsertRoomModerationConfigParams{
+		RoomID:    utils.StringToUUID(settings.RoomID),
+		Config:    config,
+		UpdatedBy: utils.StringPtrToUUID(updatedBy),
+	})
+	if err != nil {
+		return err
+	}
+
+	settings.CreatedAt = row.CreatedAt
+	settings.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *moderationRepository) CreateModerationAuditEntry(entry *models.ModerationAuditEntry, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.CreateModerationAuditLog(context.Background(), dataSource.CreateModerationAuditLogParams{
+		RoomID:    utils.StringToUUID(entry.RoomID),
+		UserID:    utils.StringToUUID(entry.UserID),
+		MessageID: utils.StringPtrToUUID(entry.MessageID),
+		Filter:    entry.Filter,
+		Action:    entry.Action,
+		Reason:    entry.Reason,
+		Content:   entry.Content,
+	})
+}
+
+func (r *moderationRepository) GetModerationAuditEntries(roomId string, limit int, tx pgx.Tx) ([]*models.ModerationAuditEntry, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_entries, err := ds.GetModerationAuditLogs(context.Background(), dataSource.GetModerationAuditLogsParams{
+		RoomID: utils.StringToUUID(roomId),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*models.ModerationAuditEntry{}
+	for _, _entry := range _entries {
+		entry := &models.ModerationAuditEntry{
+			ID:        utils.UUIDToString(_entry.ID),
+			CreatedAt: _entry.CreatedAt,
+			RoomID:    utils.UUIDToString(_entry.RoomID),
+			UserID:    utils.UUIDToString(_entry.UserID),
+			Filter:    _entry.Filter,
+			Action:    _entry.Action,
+			Reason:    _entry.Reason,
+			Content:   _entry.Content,
+		}
+		if _entry.MessageID.Valid {
+			messageId := utils.NullUUIDToString(_entry.MessageID)
+			entry.MessageID = &messageId
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
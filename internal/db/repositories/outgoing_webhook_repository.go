@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type outgoingWebhookRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewOutgoingWebhookRepository(conn *pgxpool.Pool) *outgoingWebhookRepository {
+	return &outgoingWebhookRepository{conn}
+}
+
+func (r *outgoingWebhookRepository) CreateOutgoingWebhook(webhook *models.OutgoingWebhook, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhook, err := ds.CreateOutgoingWebhook(context.Background(), dataSource.CreateOutgoingWebhookParams{
+		RoomID:     utils.StringToUUID(webhook.RoomID),
+		Url:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventTypes: webhook.EventTypes,
+		CreatedBy:  utils.StringToUUID(webhook.CreatedBy),
+	})
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = utils.UUIDToString(_webhook.ID)
+	webhook.CreatedAt = _webhook.CreatedAt
+	webhook.UpdatedAt = _webhook.UpdatedAt
+
+	return nil
+}
+
+// GetOutgoingWebhook returns a webhook including its secret.
+func (r *outgoingWebhookRepository) GetOutgoingWebhook(id string, tx pgx.Tx) (*models.OutgoingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhook, err := ds.GetOutgoingWebhook(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toOutgoingWebhookModel(_webhook), nil
+}
+
+func (r *outgoingWebhookRepository) GetOutgoingWebhooks(roomId string, tx pgx.Tx) ([]*models.OutgoingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhooks, err := ds.GetOutgoingWebhooks(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []*models.OutgoingWebhook{}
+	for _, webhook := range _webhooks {
+		webhooks = append(webhooks, toOutgoingWebhookModel(webhook))
+	}
+
+	return webhooks, nil
+}
+
+// GetRoomEventOutgoingWebhooks returns the room's webhooks subscribed to
+// eventType.
+func (r *outgoingWebhookRepository) GetRoomEventOutgoingWebhooks(roomId, eventType string, tx pgx.Tx) ([]*models.OutgoingWebhook, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_webhooks, err := ds.GetRoomEventOutgoingWebhooks(context.Background(), dataSource.GetRoomEventOutgoingWebhooksParams{
+		RoomID:    utils.StringToUUID(roomId),
+		EventType: eventType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []*models.OutgoingWebhook{}
+	for _, webhook := range _webhooks {
+		webhooks = append(webhooks, toOutgoingWebhookModel(webhook))
+	}
+
+	return webhooks, nil
+}
+
+func (r *outgoingWebhookRepository) DeleteOutgoingWebhook(id string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteOutgoingWebhook(context.Background(), utils.StringToUUID(id))
+}
+
+func (r *outgoingWebhookRepository) CreateWebhookDelivery(delivery *models.WebhookDelivery, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.CreateWebhookDelivery(context.Background(), dataSource.CreateWebhookDeliveryParams{
+		WebhookID: utils.StringToUUID(delivery.WebhookID),
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+	})
+}
+
+// ClaimDueWebhookDeliveries leases up to limit pending deliveries due at or
+// before dueBefore by pushing their next attempt to leaseUntil. Other
+// workers skip them until the lease runs out, so a worker that dies while
+// sending only delays them.
+func (r *outgoingWebhookRepository) ClaimDueWebhookDeliveries(dueBefore, leaseUntil time.Time, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_deliveries, err := ds.ClaimDueWebhookDeliveries(context.Background(), dataSource.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: leaseUntil,
+		DueBefore:  dueBefore,
+		BatchSize:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*models.WebhookDelivery{}
+	for _, delivery := range _deliveries {
+		deliveries = append(deliveries, toWebhookDeliveryModel(delivery))
+	}
+
+	return deliveries, nil
+}
+
+func (r *outgoingWebhookRepository) UpdateWebhookDelivery(delivery *models.WebhookDelivery, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var lastStatusCode pgtype.Int4
+	if delivery.LastStatusCode != 0 {
+		lastStatusCode = pgtype.Int4{Int32: int32(delivery.LastStatusCode), Valid: true}
+	}
+
+	updatedAt := time.Now()
+	err := ds.UpdateWebhookDelivery(context.Background(), dataSource.UpdateWebhookDeliveryParams{
+		Status:         delivery.Status,
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: lastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    timeToTimestamptz(delivery.DeliveredAt),
+		UpdatedAt:      updatedAt,
+		ID:             utils.StringToUUID(delivery.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	delivery.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest deliveries of a webhook, newest
+// first.
+func (r *outgoingWebhookRepository) GetWebhookDeliveries(webhookId string, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_deliveries, err := ds.GetWebhookDeliveries(context.Background(), dataSource.GetWebhookDeliveriesParams{
+		WebhookID: utils.StringToUUID(webhookId),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*models.WebhookDelivery{}
+	for _, delivery := range _deliveries {
+		deliveries = append(deliveries, toWebhookDeliveryModel(delivery))
+	}
+
+	return deliveries, nil
+}
+
+func toOutgoingWebhookModel(webhook dataSource.OutgoingWebhook) *models.OutgoingWebhook {
+	return &models.OutgoingWebhook{
+		ID:         utils.UUIDToString(webhook.ID),
+		CreatedAt:  webhook.CreatedAt,
+		UpdatedAt:  webhook.UpdatedAt,
+		RoomID:     utils.UUIDToString(webhook.RoomID),
+		URL:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		CreatedBy:  utils.UUIDToString(webhook.CreatedBy),
+		Secret:     webhook.Secret,
+	}
+}
+
+func toWebhookDeliveryModel(delivery dataSource.OutgoingWebhookDelivery) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             utils.UUIDToString(delivery.ID),
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		WebhookID:      utils.UUIDToString(delivery.WebhookID),
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       int(delivery.Attempts),
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: int(delivery.LastStatusCode.Int32),
+		LastError:      delivery.LastError,
+		DeliveredAt:    timestamptzToTime(delivery.DeliveredAt),
+	}
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type pollRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewPollRepository(conn *pgxpool.Pool) *pollRepository {
+	return &pollRepository{conn}
+}
+
+// CreatePoll creates a poll together with its options, which are stored in
+// the order given.
+func (r *pollRepository) CreatePoll(poll *models.Poll, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_poll, err := ds.CreatePoll(context.Background(), dataSource.CreatePollParams{
+		RoomID:         utils.StringToUUID(poll.RoomID),
+		RoomMessageID:  utils.StringToUUID(poll.RoomMessageID),
+		CreatedBy:      utils.StringToUUID(poll.CreatedBy),
+		Question:       poll.Question,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		ClosesAt:       timeToTimestamptz(poll.ClosesAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	poll.ID = utils.UUIDToString(_poll.ID)
+	poll.CreatedAt = _poll.CreatedAt
+	poll.UpdatedAt = _poll.UpdatedAt
+
+	for i, option := range poll.Options {
+		_option, err := ds.CreatePollOption(context.Background(), dataSource.CreatePollOptionParams{
+			PollID:   _poll.ID,
+			Position: int32(i),
+			Text:     option.Text,
+		})
+		if err != nil {
+			return err
+		}
+
+		option.ID = utils.UUIDToString(_option.ID)
+		option.CreatedAt = _option.CreatedAt
+		option.UpdatedAt = _option.UpdatedAt
+		option.PollID = poll.ID
+		option.Position = i
+	}
+
+	return nil
+}
+
+func (r *pollRepository) GetPoll(id string, tx pgx.Tx) (*models.Poll, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_poll, err := ds.GetPoll(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toPollModel(_poll), nil
+}
+
+// GetPollForUpdate returns a poll and locks it until tx ends, so votes and
+// closing are applied one at a time.
+func (r *pollRepository) GetPollForUpdate(id string, tx pgx.Tx) (*models.Poll, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_poll, err := ds.GetPollForUpdate(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toPollModel(_poll), nil
+}
+
+func (r *pollRepository) GetMessagesPolls(messageIds []string, tx pgx.Tx) ([]*models.Poll, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range messageIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	_polls, err := ds.GetMessagesPolls(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	polls := []*models.Poll{}
+	for _, poll := range _polls {
+		polls = append(polls, toPollModel(poll))
+	}
+
+	return polls, nil
+}
+
+func (r *pollRepository) GetPollsOptions(pollIds []string, tx pgx.Tx) ([]*models.PollOption, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range pollIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	_options, err := ds.GetPollsOptions(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []*models.PollOption{}
+	for _, option := range _options {
+		options = append(options, &models.PollOption{
+			ID:        utils.UUIDToString(option.ID),
+			CreatedAt: option.CreatedAt,
+			UpdatedAt: option.UpdatedAt,
+			PollID:    utils.UUIDToString(option.PollID),
+			Position:  int(option.Position),
+			Text:      option.Text,
+		})
+	}
+
+	return options, nil
+}
+
+func (r *pollRepository) GetPollsVotes(pollIds []string, tx pgx.Tx) ([]*models.PollVote, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range pollIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	_votes, err := ds.GetPollsVotes(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	votes := []*models.PollVote{}
+	for _, vote := range _votes {
+		votes = append(votes, &models.PollVote{
+			ID:           utils.UUIDToString(vote.ID),
+			CreatedAt:    vote.CreatedAt,
+			UpdatedAt:    vote.UpdatedAt,
+			PollID:       utils.UUIDToString(vote.PollID),
+			PollOptionID: utils.UUIDToString(vote.PollOptionID),
+			UserID:       utils.UUIDToString(vote.UserID),
+		})
+	}
+
+	return votes, nil
+}
+
+func (r *pollRepository) CreatePollVote(vote *models.PollVote, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.CreatePollVote(context.Background(), dataSource.CreatePollVoteParams{
+		PollID:       utils.StringToUUID(vote.PollID),
+		PollOptionID: utils.StringToUUID(vote.PollOptionID),
+		UserID:       utils.StringToUUID(vote.UserID),
+	})
+}
+
+func (r *pollRepository) DeletePollVotes(pollId, userId string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeletePollVotes(context.Background(), dataSource.DeletePollVotesParams{
+		PollID: utils.StringToUUID(pollId),
+		UserID: utils.StringToUUID(userId),
+	})
+}
+
+func (r *pollRepository) ClosePoll(poll *models.Poll, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	closedAt := time.Now()
+	err := ds.ClosePoll(context.Background(), dataSource.ClosePollParams{
+		ClosedAt: timeToTimestamptz(&closedAt),
+		ID:       utils.StringToUUID(poll.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	poll.ClosedAt = &closedAt
+	poll.UpdatedAt = closedAt
+	return nil
+}
+
+func toPollModel(poll dataSource.Poll) *models.Poll {
+	return &models.Poll{
+		ID:             utils.UUIDToString(poll.ID),
+		CreatedAt:      poll.CreatedAt,
+		UpdatedAt:      poll.UpdatedAt,
+		RoomID:         utils.UUIDToString(poll.RoomID),
+		RoomMessageID:  utils.UUIDToString(poll.RoomMessageID),
+		CreatedBy:      utils.UUIDToString(poll.CreatedBy),
+		Question:       poll.Question,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		ClosesAt:       timestamptzToTime(poll.ClosesAt),
+		ClosedAt:       timestamptzToTime(poll.ClosedAt),
+	}
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type roomInviteRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewRoomInviteRepository(conn *pgxpool.Pool) *roomInviteRepository {
+	return &roomInviteRepository{conn}
+}
+
+func (r *roomInviteRepository) CreateRoomInvite(invite *models.RoomInvite, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.CreateRoomInvite(context.Background(), dataSource.CreateRoomInviteParams{
+		RoomID:    utils.StringToUUID(invite.RoomID),
+		Code:      invite.Code,
+		CreatedBy: utils.StringToUUID(invite.CreatedBy),
+		ExpiresAt: timeToTimestamptz(invite.ExpiresAt),
+		MaxUses:   intToInt4(invite.MaxUses),
+	})
+	if err != nil {
+		return err
+	}
+
+	invite.ID = utils.UUIDToString(row.ID)
+	invite.CreatedAt = row.CreatedAt
+	invite.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *roomInviteRepository) GetRoomInvite(id string, tx pgx.Tx) (*models.RoomInvite, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	invite, err := ds.GetRoomInvite(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomInviteModel(invite), nil
+}
+
+func (r *roomInviteRepository) GetRoomInviteByCode(code string, tx pgx.Tx) (*models.RoomInvite, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	invite, err := ds.GetRoomInviteByCode(context.Background(), code)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomInviteModel(invite), nil
+}
+
+func (r *roomInviteRepository) GetRoomInvites(roomId string, tx pgx.Tx) ([]*models.RoomInvite, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_invites, err := ds.GetRoomInvites(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	invites := []*models.RoomInvite{}
+	for _, invite := range _invites {
+		invites = append(invites, toRoomInviteModel(invite))
+	}
+
+	return invites, nil
+}
+
+// UseRoomInvite counts a use of the invite. It reports false, leaving the
+// invite untouched, if the invite has no uses left.
+func (r *roomInviteRepository) UseRoomInvite(invite *models.RoomInvite, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	n, err := ds.UseRoomInvite(context.Background(), utils.StringToUUID(invite.ID))
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	invite.Uses++
+	return true, nil
+}
+
+func (r *roomInviteRepository) RevokeRoomInvite(invite *models.RoomInvite, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	now := time.Now()
+	err := ds.RevokeRoomInvite(context.Background(), dataSource.RevokeRoomInviteParams{
+		RevokedAt: timeToTimestamptz(&now),
+		ID:        utils.StringToUUID(invite.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	invite.RevokedAt = &now
+	invite.UpdatedAt = now
+	return nil
+}
+
+func toRoomInviteModel(invite dataSource.RoomInvite) *models.RoomInvite {
+	return &models.RoomInvite{
+		ID:        utils.UUIDToString(invite.ID),
+		CreatedAt: invite.CreatedAt,
+		UpdatedAt: invite.UpdatedAt,
+		RoomID:    utils.UUIDToString(invite.RoomID),
+		Code:      invite.Code,
+		CreatedBy: utils.UUIDToString(invite.CreatedBy),
+		ExpiresAt: timestamptzToTime(invite.ExpiresAt),
+		MaxUses:   int4ToInt(invite.MaxUses),
+		Uses:      int(invite.Uses),
+		RevokedAt: timestamptzToTime(invite.RevokedAt),
+	}
+}
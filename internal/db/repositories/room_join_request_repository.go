@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type roomJoinRequestRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewRoomJoinRequestRepository(conn *pgxpool.Pool) *roomJoinRequestRepository {
+	return &roomJoinRequestRepository{conn}
+}
+
+func (r *roomJoinRequestRepository) CreateRoomJoinRequest(request *models.RoomJoinRequest, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	row, err := ds.CreateRoomJoinRequest(context.Background(), dataSource.CreateRoomJoinRequestParams{
+		RoomID:  utils.StringToUUID(request.RoomID),
+		UserID:  utils.StringToUUID(request.UserID),
+		Message: request.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	request.ID = utils.UUIDToString(row.ID)
+	request.Status = row.Status
+	request.CreatedAt = row.CreatedAt
+	request.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *roomJoinRequestRepository) GetRoomJoinRequest(id string, tx pgx.Tx) (*models.RoomJoinRequest, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	request, err := ds.GetRoomJoinRequest(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomJoinRequestModel(request), nil
+}
+
+func (r *roomJoinRequestRepository) GetPendingRoomJoinRequest(roomId, userId string, tx pgx.Tx) (*models.RoomJoinRequest, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	request, err := ds.GetPendingRoomJoinRequest(context.Background(), dataSource.GetPendingRoomJoinRequestParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomJoinRequestModel(request), nil
+}
+
+// GetRoomJoinRequests returns a room's requests with status, oldest first.
+func (r *roomJoinRequestRepository) GetRoomJoinRequests(roomId, status string, tx pgx.Tx) ([]*models.RoomJoinRequest, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_requests, err := ds.GetRoomJoinRequests(context.Background(), dataSource.GetRoomJoinRequestsParams{
+		RoomID: utils.StringToUUID(roomId),
+		Status: status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requests := []*models.RoomJoinRequest{}
+	for _, request := range _requests {
+		requests = append(requests, toRoomJoinRequestModel(request))
+	}
+
+	return requests, nil
+}
+
+// ReviewRoomJoinRequest records the decision on a pending request. It
+// reports false, leaving the request untouched, if it was already reviewed.
+func (r *roomJoinRequestRepository) ReviewRoomJoinRequest(request *models.RoomJoinRequest, status, reviewerId string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	now := time.Now()
+	n, err := ds.ReviewRoomJoinRequest(context.Background(), dataSource.ReviewRoomJoinRequestParams{
+		Status:     status,
+		ReviewedBy: utils.StringPtrToUUID(&reviewerId),
+		ReviewedAt: timeToTimestamptz(&now),
+		ID:         utils.StringToUUID(request.ID),
+	})
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	request.Status = status
+	request.ReviewedBy = reviewerId
+	request.ReviewedAt = &now
+	request.UpdatedAt = now
+	return true, nil
+}
+
+func toRoomJoinRequestModel(request dataSource.RoomJoinRequest) *models.RoomJoinRequest {
+	return &models.RoomJoinRequest{
+		ID:         utils.UUIDToString(request.ID),
+		CreatedAt:  request.CreatedAt,
+		UpdatedAt:  request.UpdatedAt,
+		RoomID:     utils.UUIDToString(request.RoomID),
+		UserID:     utils.UUIDToString(request.UserID),
+		Message:    request.Message,
+		Status:     request.Status,
+		ReviewedBy: utils.NullUUIDToString(request.ReviewedBy),
+		ReviewedAt: timestamptzToTime(request.ReviewedAt),
+	}
+}
@@ -2,8 +2,11 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -31,6 +34,8 @@ func (r *roomRepository) CreateRoom(room *models.Room, tx pgx.Tx) error {
 		Description: utils.StringToText(room.Description),
 		MaxMembers:  int32(room.MaxMembers),
 		CreatedBy:   utils.StringToUUID(room.CreatedBy),
+		MessageTtl:  int32(room.MessageTTL),
+		Visibility:  room.Visibility,
 	})
 	if err != nil {
 		return err
@@ -39,9 +44,139 @@ func (r *roomRepository) CreateRoom(room *models.Room, tx pgx.Tx) error {
 	room.CreatedAt = _room.CreatedAt
 	room.UpdatedAt = _room.UpdatedAt
 	room.ID = _room.ID.String()
+	room.Kind = models.RoomKindRoom
 	return nil
 }
 
+// CreateDirectRoom creates a conversation room. It returns pgx.ErrNoRows
+// when a room with the same participants key already exists.
+func (r *roomRepository) CreateDirectRoom(room *models.Room, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_room, err := ds.CreateDirectRoom(context.Background(), dataSource.CreateDirectRoomParams{
+		Name:            room.Name,
+		MaxMembers:      int32(room.MaxMembers),
+		CreatedBy:       utils.StringToUUID(room.CreatedBy),
+		Kind:            room.Kind,
+		ParticipantsKey: utils.StringToText(room.ParticipantsKey),
+	})
+	if err != nil {
+		return err
+	}
+
+	room.CreatedAt = _room.CreatedAt
+	room.UpdatedAt = _room.UpdatedAt
+	room.ID = _room.ID.String()
+	return nil
+}
+
+func (r *roomRepository) GetRoomByParticipantsKey(key string, tx pgx.Tx) (*models.Room, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_room, err := ds.GetRoomByParticipantsKey(context.Background(), utils.StringToText(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomModel(_room), nil
+}
+
+// GetUserConversations returns the conversations userId is in, most
+// recently updated first.
+func (r *roomRepository) GetUserConversations(userId string, tx pgx.Tx) ([]*models.Room, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_rooms, err := ds.GetUserConversations(context.Background(), utils.StringToUUID(userId))
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := []*models.Room{}
+	for _, _room := range _rooms {
+		rooms = append(rooms, toRoomModel(_room))
+	}
+
+	return rooms, nil
+}
+
+// GetConversationParticipants returns the users in each of the rooms, by
+// room ID.
+func (r *roomRepository) GetConversationParticipants(roomIds []string, tx pgx.Tx) (map[string][]*models.User, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range roomIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	rows, err := ds.GetConversationParticipants(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := map[string][]*models.User{}
+	for _, row := range rows {
+		roomId := utils.UUIDToString(row.RoomID)
+		participants[roomId] = append(participants[roomId], &models.User{
+			ID:        utils.UUIDToString(row.ID),
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Email:     row.Email,
+		})
+	}
+
+	return participants, nil
+}
+
+// GetRoomMemberUsers returns those of userIds who are members of the room.
+func (r *roomRepository) GetRoomMemberUsers(roomId string, userIds []string, tx pgx.Tx) ([]*models.User, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range userIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	rows, err := ds.GetRoomMemberUsers(context.Background(), dataSource.GetRoomMemberUsersParams{
+		RoomID:  utils.StringToUUID(roomId),
+		UserIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*models.User{}
+	for _, row := range rows {
+		users = append(users, &models.User{
+			ID:        utils.UUIDToString(row.ID),
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Email:     row.Email,
+		})
+	}
+
+	return users, nil
+}
+
 func (r *roomRepository) GetRoom(id string, tx pgx.Tx) (*models.Room, error) {
 	ds := dataSource.New(r.conn)
 	if tx != nil {
@@ -53,42 +188,49 @@ func (r *roomRepository) GetRoom(id string, tx pgx.Tx) (*models.Room, error) {
 		return nil, err
 	}
 
-	return &models.Room{
-		ID:          utils.UUIDToString(_room.ID),
-		CreatedAt:   _room.CreatedAt,
-		UpdatedAt:   _room.UpdatedAt,
-		Name:        _room.Name,
-		Description: _room.Description.String,
-		MaxMembers:  int(_room.MaxMembers),
-		CreatedBy:   utils.UUIDToString(_room.CreatedBy),
-	}, nil
+	return toRoomModel(_room), nil
 }
 
-func (r *roomRepository) GetRooms(createdBy *string, tx pgx.Tx) ([]*models.Room, error) {
+// GetRoomForUpdate returns a room and locks it until tx ends, so changes
+// that depend on counts within the room are applied one at a time.
+func (r *roomRepository) GetRoomForUpdate(id string, tx pgx.Tx) (*models.Room, error) {
 	ds := dataSource.New(r.conn)
 	if tx != nil {
 		ds = ds.WithTx(tx)
 	}
 
-	cUUID := pgtype.UUID{}
-	cUUID.Scan(createdBy)
+	_room, err := ds.GetRoomForUpdate(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomModel(_room), nil
+}
+
+// GetRoomsParams filters the rooms listed. Private rooms are only listed
+// for their members, so they are left out unless ViewerID is one.
+type GetRoomsParams struct {
+	CreatedBy *string
+	ViewerID  *string
+}
 
-	_rooms, err := ds.GetRooms(context.Background(), cUUID)
+func (r *roomRepository) GetRooms(params GetRoomsParams, tx pgx.Tx) ([]*models.Room, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_rooms, err := ds.GetRooms(context.Background(), dataSource.GetRoomsParams{
+		CreatedBy: utils.StringPtrToUUID(params.CreatedBy),
+		ViewerID:  utils.StringPtrToUUID(params.ViewerID),
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	rooms := []*models.Room{}
 	for _, _room := range _rooms {
-		r := &models.Room{
-			ID:          utils.UUIDToString(_room.ID),
-			CreatedAt:   _room.CreatedAt,
-			UpdatedAt:   _room.UpdatedAt,
-			Name:        _room.Name,
-			Description: _room.Description.String,
-			CreatedBy:   utils.UUIDToString(_room.CreatedBy),
-		}
-		rooms = append(rooms, r)
+		rooms = append(rooms, toRoomModel(_room))
 	}
 
 	return rooms, nil
@@ -115,19 +257,62 @@ func (r *roomRepository) UpdateRoom(room *models.Room, tx pgx.Tx) error {
 		Name:        room.Name,
 		Description: utils.StringToText(room.Description),
 		MaxMembers:  int32(room.MaxMembers),
+		MessageTtl:  int32(room.MessageTTL),
+		Visibility:  room.Visibility,
 		ID:          utils.StringToUUID(room.ID),
 	})
 }
 
+// UpdateRoomRetention saves a room's retention period and legal hold.
+func (r *roomRepository) UpdateRoomRetention(room *models.Room, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	room.UpdatedAt = time.Now()
+	return ds.UpdateRoomRetention(context.Background(), dataSource.UpdateRoomRetentionParams{
+		UpdatedAt:     room.UpdatedAt,
+		RetentionDays: intToInt4(room.RetentionDays),
+		LegalHold:     room.LegalHold,
+		ID:            utils.StringToUUID(room.ID),
+	})
+}
+
+// UpdateConversation saves a conversation's kind, participants key, name and
+// member limit. An empty participants key clears it.
+func (r *roomRepository) UpdateConversation(room *models.Room, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	room.UpdatedAt = time.Now()
+	return ds.UpdateConversation(context.Background(), dataSource.UpdateConversationParams{
+		UpdatedAt:       room.UpdatedAt,
+		Kind:            room.Kind,
+		ParticipantsKey: pgtype.Text{String: room.ParticipantsKey, Valid: room.ParticipantsKey != ""},
+		Name:            room.Name,
+		MaxMembers:      int32(room.MaxMembers),
+		Visibility:      room.Visibility,
+		ID:              utils.StringToUUID(room.ID),
+	})
+}
+
 func (r *roomRepository) CreateRoomMember(member *models.RoomMember, tx pgx.Tx) error {
 	ds := dataSource.New(r.conn)
 	if tx != nil {
 		ds = ds.WithTx(tx)
 	}
 
+	if member.Role == "" {
+		member.Role = models.RoomRoleMember
+	}
+
 	_member, err := ds.CreateRoomMember(context.Background(), dataSource.CreateRoomMemberParams{
 		RoomID: utils.StringToUUID(member.RoomID),
 		UserID: utils.StringToUUID(member.UserID),
+		Role:   member.Role,
 	})
 	if err != nil {
 		return err
@@ -157,9 +342,24 @@ func (r *roomRepository) GetRoomMember(id string, tx pgx.Tx) (*models.RoomMember
 		UpdatedAt: _member.UpdatedAt,
 		RoomID:    utils.UUIDToString(_member.RoomID),
 		UserID:    utils.UUIDToString(_member.UserID),
+		Role:      _member.Role,
 	}, nil
 }
 
+func (r *roomRepository) UpdateRoomMemberRole(member *models.RoomMember, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	member.UpdatedAt = time.Now()
+	return ds.UpdateRoomMemberRole(context.Background(), dataSource.UpdateRoomMemberRoleParams{
+		Role:      member.Role,
+		UpdatedAt: member.UpdatedAt,
+		ID:        utils.StringToUUID(member.ID),
+	})
+}
+
 type GetRoomMemberByWhereParams struct {
 	UserID string
 	RoomID string
@@ -185,6 +385,7 @@ func (r *roomRepository) GetRoomMemberByWhere(params GetRoomMemberByWhereParams,
 		UpdatedAt: _member.UpdatedAt,
 		RoomID:    utils.UUIDToString(_member.RoomID),
 		UserID:    utils.UUIDToString(_member.UserID),
+		Role:      _member.Role,
 	}, nil
 }
 
@@ -199,13 +400,9 @@ func (r *roomRepository) GetRoomMembers(params GetRoomMembersParams, tx pgx.Tx)
 		ds = ds.WithTx(tx)
 	}
 
-	var userIDUUID, roomIDUUID pgtype.UUID
-	userIDUUID.Scan(params.UserID)
-	roomIDUUID.Scan(params.RoomID)
-
 	_roomMembers, err := ds.GetRoomMembers(context.Background(), dataSource.GetRoomMembersParams{
-		UserID: userIDUUID,
-		RoomID: roomIDUUID,
+		UserID: utils.StringPtrToUUID(params.UserID),
+		RoomID: utils.StringPtrToUUID(params.RoomID),
 	})
 	if err != nil {
 		return nil, err
@@ -219,6 +416,7 @@ func (r *roomRepository) GetRoomMembers(params GetRoomMembersParams, tx pgx.Tx)
 			UpdatedAt: member.UpdatedAt,
 			RoomID:    utils.UUIDToString(member.RoomID),
 			UserID:    utils.UUIDToString(member.UserID),
+			Role:      member.Role,
 		})
 	}
 
@@ -255,11 +453,58 @@ func (r *roomRepository) CreateRoomMessage(message *models.RoomMessage, tx pgx.T
 		ds = ds.WithTx(tx)
 	}
 
+	if message.Entities == nil {
+		message.Entities = []*models.MessageEntity{}
+	}
+	entities, err := json.Marshal(message.Entities)
+	if err != nil {
+		return err
+	}
+
 	_message, err := ds.CreateRoomMessage(context.Background(), dataSource.CreateRoomMessageParams{
 		RoomID:       utils.StringToUUID(message.RoomID),
 		RoomMemberID: utils.StringToUUID(message.RoomMemberID),
 		UserID:       utils.StringToUUID(message.UserID),
 		Content:      message.Content,
+		ContentHtml:  message.ContentHTML,
+		Entities:     entities,
+		ExpiresAt:    timeToTimestamptz(message.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	message.ID = utils.UUIDToString(_message.ID)
+	message.CreatedAt = _message.CreatedAt
+	message.UpdatedAt = _message.UpdatedAt
+
+	return nil
+}
+
+// CreateImportedRoomMessage stores a message brought over from another
+// system with its original creation time.
+func (r *roomRepository) CreateImportedRoomMessage(message *models.RoomMessage, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	if message.Entities == nil {
+		message.Entities = []*models.MessageEntity{}
+	}
+	entities, err := json.Marshal(message.Entities)
+	if err != nil {
+		return err
+	}
+
+	_message, err := ds.CreateImportedRoomMessage(context.Background(), dataSource.CreateImportedRoomMessageParams{
+		RoomID:       utils.StringToUUID(message.RoomID),
+		RoomMemberID: utils.StringToUUID(message.RoomMemberID),
+		UserID:       utils.StringToUUID(message.UserID),
+		Content:      message.Content,
+		ContentHtml:  message.ContentHTML,
+		Entities:     entities,
+		CreatedAt:    message.CreatedAt,
 	})
 	if err != nil {
 		return err
@@ -283,15 +528,7 @@ func (r *roomRepository) GetRoomMessage(id string, tx pgx.Tx) (*models.RoomMessa
 		return nil, err
 	}
 
-	return &models.RoomMessage{
-		ID:           _message.ID.String(),
-		CreatedAt:    _message.CreatedAt,
-		UpdatedAt:    _message.UpdatedAt,
-		RoomID:       _message.RoomID.String(),
-		RoomMemberID: _message.RoomMemberID.String(),
-		UserID:       _message.UserID.String(),
-		Content:      _message.Content,
-	}, nil
+	return toRoomMessageModel(_message), nil
 }
 
 type GetRoomMessagesParams struct {
@@ -306,15 +543,10 @@ func (r *roomRepository) GetRoomMessages(params GetRoomMessagesParams, tx pgx.Tx
 		ds = ds.WithTx(tx)
 	}
 
-	var roomIDUUID, roomMemberIDUUID, userIDUUID pgtype.UUID
-	roomIDUUID.Scan(params.RoomID)
-	roomMemberIDUUID.Scan(params.RoomMemberID)
-	userIDUUID.Scan(params.UserID)
-
 	_messages, err := ds.GetRoomMessages(context.Background(), dataSource.GetRoomMessagesParams{
-		RoomID:       roomIDUUID,
-		RoomMemberID: roomMemberIDUUID,
-		UserID:       userIDUUID,
+		RoomID:       utils.StringPtrToUUID(params.RoomID),
+		RoomMemberID: utils.StringPtrToUUID(params.RoomMemberID),
+		UserID:       utils.StringPtrToUUID(params.UserID),
 	})
 	if err != nil {
 		return nil, err
@@ -322,20 +554,170 @@ func (r *roomRepository) GetRoomMessages(params GetRoomMessagesParams, tx pgx.Tx
 
 	messages := []*models.RoomMessage{}
 	for _, message := range _messages {
-		messages = append(messages, &models.RoomMessage{
-			ID:           message.ID.String(),
-			CreatedAt:    message.CreatedAt,
-			UpdatedAt:    message.UpdatedAt,
-			RoomID:       message.RoomID.String(),
-			RoomMemberID: message.RoomMemberID.String(),
-			UserID:       message.UserID.String(),
-			Content:      message.Content,
+		messages = append(messages, toRoomMessageModel(message))
+	}
+
+	return messages, nil
+}
+
+type GetRoomMessagesPageParams struct {
+	RoomID string
+	Before *utils.Cursor
+	After  *utils.Cursor
+	Limit  int
+}
+
+// GetRoomMessagesPage returns up to params.Limit messages adjacent to the
+// cursor. Messages after params.After are returned oldest first, everything
+// else newest first, starting from params.Before or the latest message.
+func (r *roomRepository) GetRoomMessagesPage(params GetRoomMessagesPageParams, tx pgx.Tx) ([]*models.RoomMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var _messages []dataSource.RoomMessage
+	var err error
+	if params.After != nil {
+		_messages, err = ds.GetRoomMessagesAfter(context.Background(), dataSource.GetRoomMessagesAfterParams{
+			RoomID:          utils.StringToUUID(params.RoomID),
+			CursorCreatedAt: params.After.CreatedAt,
+			CursorID:        utils.StringToUUID(params.After.ID),
+			PageLimit:       int32(params.Limit),
+		})
+	} else {
+		var cursorCreatedAt pgtype.Timestamptz
+		var cursorID *string
+		if params.Before != nil {
+			cursorCreatedAt.Scan(params.Before.CreatedAt)
+			cursorID = &params.Before.ID
+		}
+
+		_messages, err = ds.GetRoomMessagesBefore(context.Background(), dataSource.GetRoomMessagesBeforeParams{
+			RoomID:          utils.StringToUUID(params.RoomID),
+			CursorCreatedAt: cursorCreatedAt,
+			CursorID:        utils.StringPtrToUUID(cursorID),
+			PageLimit:       int32(params.Limit),
 		})
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*models.RoomMessage{}
+	for _, message := range _messages {
+		messages = append(messages, toRoomMessageModel(message))
+	}
 
 	return messages, nil
 }
 
+// GetRoomTranscript returns up to limit unexpired messages of a room after
+// the cursor, oldest first, with their authors' names. A nil cursor starts
+// at the beginning of the room.
+func (r *roomRepository) GetRoomTranscript(roomId string, after *utils.Cursor, limit int, tx pgx.Tx) ([]*models.TranscriptEntry, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var cursorCreatedAt pgtype.Timestamptz
+	var cursorID *string
+	if after != nil {
+		cursorCreatedAt.Scan(after.CreatedAt)
+		cursorID = &after.ID
+	}
+
+	rows, err := ds.GetRoomTranscript(context.Background(), dataSource.GetRoomTranscriptParams{
+		RoomID:          utils.StringToUUID(roomId),
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        utils.StringPtrToUUID(cursorID),
+		PageLimit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*models.TranscriptEntry{}
+	for _, row := range rows {
+		entries = append(entries, &models.TranscriptEntry{
+			MessageID:  utils.UUIDToString(row.ID),
+			CreatedAt:  row.CreatedAt,
+			AuthorID:   utils.UUIDToString(row.UserID),
+			AuthorName: strings.TrimSpace(row.FirstName + " " + row.LastName),
+			Content:    row.Content,
+		})
+	}
+
+	return entries, nil
+}
+
+// GetExpiredRoomMessages locks and returns up to limit messages that
+// expired at or before expiredBefore in rooms not on legal hold, skipping
+// rows locked by a concurrent reaper. It must run inside the transaction
+// that deletes them.
+func (r *roomRepository) GetExpiredRoomMessages(expiredBefore time.Time, limit int, tx pgx.Tx) ([]*models.RoomMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_messages, err := ds.GetExpiredRoomMessages(context.Background(), dataSource.GetExpiredRoomMessagesParams{
+		ExpiredBefore: timeToTimestamptz(&expiredBefore),
+		BatchSize:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*models.RoomMessage{}
+	for _, message := range _messages {
+		messages = append(messages, toRoomMessageModel(message))
+	}
+
+	return messages, nil
+}
+
+// GetRetentionExpiredMessages locks and returns up to limit messages older
+// than their room's retention period, oldest first. Rooms without one use
+// defaultDays, and rooms on legal hold are skipped. It must run inside the
+// transaction that deletes them.
+func (r *roomRepository) GetRetentionExpiredMessages(defaultDays int, limit int, tx pgx.Tx) ([]*models.RoomMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_messages, err := ds.GetRetentionExpiredMessages(context.Background(), dataSource.GetRetentionExpiredMessagesParams{
+		DefaultRetentionDays: int32(defaultDays),
+		BatchSize:            int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*models.RoomMessage{}
+	for _, message := range _messages {
+		messages = append(messages, toRoomMessageModel(message))
+	}
+
+	return messages, nil
+}
+
+func (r *roomRepository) DeleteRoomMessages(ids []string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_ids := []uuid.UUID{}
+	for _, id := range ids {
+		_ids = append(_ids, utils.StringToUUID(id))
+	}
+
+	return ds.DeleteRoomMessages(context.Background(), _ids)
+}
+
 func (r *roomRepository) DeleteRoomMessage(id string, tx pgx.Tx) error {
 	ds := dataSource.New(r.conn)
 	if tx != nil {
@@ -344,3 +726,171 @@ func (r *roomRepository) DeleteRoomMessage(id string, tx pgx.Tx) error {
 
 	return ds.DeleteRoomMessage(context.Background(), utils.StringToUUID(id))
 }
+
+func (r *roomRepository) CreateRoomPin(pin *models.RoomPin, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_pin, err := ds.CreateRoomPin(context.Background(), dataSource.CreateRoomPinParams{
+		RoomID:        utils.StringToUUID(pin.RoomID),
+		RoomMessageID: utils.StringToUUID(pin.RoomMessageID),
+		PinnedBy:      utils.StringToUUID(pin.PinnedBy),
+	})
+	if err != nil {
+		return err
+	}
+
+	pin.ID = utils.UUIDToString(_pin.ID)
+	pin.CreatedAt = _pin.CreatedAt
+	pin.UpdatedAt = _pin.UpdatedAt
+
+	return nil
+}
+
+func (r *roomRepository) GetRoomPinByWhere(roomId, messageId string, tx pgx.Tx) (*models.RoomPin, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_pin, err := ds.GetRoomPinByWhere(context.Background(), dataSource.GetRoomPinByWhereParams{
+		RoomID:        utils.StringToUUID(roomId),
+		RoomMessageID: utils.StringToUUID(messageId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RoomPin{
+		ID:            utils.UUIDToString(_pin.ID),
+		CreatedAt:     _pin.CreatedAt,
+		UpdatedAt:     _pin.UpdatedAt,
+		RoomID:        utils.UUIDToString(_pin.RoomID),
+		RoomMessageID: utils.UUIDToString(_pin.RoomMessageID),
+		PinnedBy:      utils.UUIDToString(_pin.PinnedBy),
+	}, nil
+}
+
+func (r *roomRepository) GetRoomPins(roomId string, tx pgx.Tx) ([]*models.RoomPin, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_pins, err := ds.GetRoomPins(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	pins := []*models.RoomPin{}
+	for _, row := range _pins {
+		pin := row.RoomPin
+		pins = append(pins, &models.RoomPin{
+			ID:            utils.UUIDToString(pin.ID),
+			CreatedAt:     pin.CreatedAt,
+			UpdatedAt:     pin.UpdatedAt,
+			RoomID:        utils.UUIDToString(pin.RoomID),
+			RoomMessageID: utils.UUIDToString(pin.RoomMessageID),
+			PinnedBy:      utils.UUIDToString(pin.PinnedBy),
+			Message:       toRoomMessageModel(row.RoomMessage),
+		})
+	}
+
+	return pins, nil
+}
+
+func (r *roomRepository) GetRoomPinCount(roomId string, tx pgx.Tx) (*int, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	count, err := ds.RoomPinsCount(context.Background(), utils.StringToUUID(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	_count := int(count)
+	return &_count, nil
+}
+
+func (r *roomRepository) DeleteRoomPin(id string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteRoomPin(context.Background(), utils.StringToUUID(id))
+}
+
+func toRoomMessageModel(message dataSource.RoomMessage) *models.RoomMessage {
+	entities := []*models.MessageEntity{}
+	json.Unmarshal(message.Entities, &entities)
+
+	var expiresAt *time.Time
+	if message.ExpiresAt.Valid {
+		expiresAt = &message.ExpiresAt.Time
+	}
+
+	return &models.RoomMessage{
+		ID:           message.ID.String(),
+		CreatedAt:    message.CreatedAt,
+		UpdatedAt:    message.UpdatedAt,
+		RoomID:       message.RoomID.String(),
+		RoomMemberID: message.RoomMemberID.String(),
+		UserID:       message.UserID.String(),
+		Content:      message.Content,
+		ContentHTML:  message.ContentHtml,
+		Entities:     entities,
+		ExpiresAt:    expiresAt,
+	}
+}
+
+func toRoomModel(room dataSource.Room) *models.Room {
+	return &models.Room{
+		ID:              utils.UUIDToString(room.ID),
+		CreatedAt:       room.CreatedAt,
+		UpdatedAt:       room.UpdatedAt,
+		Name:            room.Name,
+		Description:     room.Description.String,
+		MaxMembers:      int(room.MaxMembers),
+		CreatedBy:       utils.UUIDToString(room.CreatedBy),
+		MessageTTL:      int(room.MessageTtl),
+		Kind:            room.Kind,
+		ParticipantsKey: room.ParticipantsKey.String,
+		RetentionDays:   int4ToInt(room.RetentionDays),
+		LegalHold:       room.LegalHold,
+		Visibility:      room.Visibility,
+	}
+}
+
+func intToInt4(i *int) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*i), Valid: true}
+}
+
+func int4ToInt(i pgtype.Int4) *int {
+	if !i.Valid {
+		return nil
+	}
+	n := int(i.Int32)
+	return &n
+}
+
+func timeToTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func timestamptzToTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
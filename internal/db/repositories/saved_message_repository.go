@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type savedMessageRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewSavedMessageRepository(conn *pgxpool.Pool) *savedMessageRepository {
+	return &savedMessageRepository{conn}
+}
+
+func (r *savedMessageRepository) SaveMessage(saved *models.SavedMessage, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_saved, err := ds.UpsertSavedMessage(context.Background(), dataSource.UpsertSavedMessageParams{
+		UserID:        utils.StringToUUID(saved.UserID),
+		RoomMessageID: utils.StringToUUID(saved.MessageID),
+		Note:          saved.Note,
+	})
+	if err != nil {
+		return err
+	}
+
+	saved.ID = utils.UUIDToString(_saved.ID)
+	saved.CreatedAt = _saved.CreatedAt
+	saved.UpdatedAt = _saved.UpdatedAt
+	return nil
+}
+
+// DeleteSavedMessage removes the user's bookmark of the message and reports
+// whether there was one.
+func (r *savedMessageRepository) DeleteSavedMessage(userId, messageId string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	n, err := ds.DeleteSavedMessage(context.Background(), dataSource.DeleteSavedMessageParams{
+		UserID:        utils.StringToUUID(userId),
+		RoomMessageID: utils.StringToUUID(messageId),
+	})
+	return n > 0, err
+}
+
+type GetSavedMessagesParams struct {
+	UserID string
+	Before *utils.Cursor
+	Limit  int
+}
+
+// GetSavedMessages returns the user's saved messages, most recently saved
+// first, starting before params.Before. Messages in rooms the user is no
+// longer a member of are left out.
+func (r *savedMessageRepository) GetSavedMessages(params GetSavedMessagesParams, tx pgx.Tx) ([]*models.SavedMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var cursorCreatedAt pgtype.Timestamptz
+	var cursorID *string
+	if params.Before != nil {
+		cursorCreatedAt.Scan(params.Before.CreatedAt)
+		cursorID = &params.Before.ID
+	}
+
+	rows, err := ds.GetSavedMessages(context.Background(), dataSource.GetSavedMessagesParams{
+		UserID:          utils.StringToUUID(params.UserID),
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        utils.StringPtrToUUID(cursorID),
+		PageLimit:       int32(params.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved := []*models.SavedMessage{}
+	for _, row := range rows {
+		saved = append(saved, &models.SavedMessage{
+			ID:        utils.UUIDToString(row.ID),
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			UserID:    params.UserID,
+			MessageID: utils.UUIDToString(row.RoomMessage.ID),
+			Note:      row.Note,
+			Message:   toRoomMessageModel(row.RoomMessage),
+			Room:      toRoomModel(row.Room),
+		})
+	}
+
+	return saved, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type scheduledMessageRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewScheduledMessageRepository(conn *pgxpool.Pool) *scheduledMessageRepository {
+	return &scheduledMessageRepository{conn}
+}
+
+func (r *scheduledMessageRepository) CreateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_message, err := ds.CreateScheduledMessage(context.Background(), dataSource.CreateScheduledMessageParams{
+		RoomID:      utils.StringToUUID(message.RoomID),
+		UserID:      utils.StringToUUID(message.UserID),
+		Content:     message.Content,
+		ScheduledAt: message.ScheduledAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	message.ID = utils.UUIDToString(_message.ID)
+	message.Status = _message.Status
+	message.CreatedAt = _message.CreatedAt
+	message.UpdatedAt = _message.UpdatedAt
+
+	return nil
+}
+
+func (r *scheduledMessageRepository) GetScheduledMessage(id string, tx pgx.Tx) (*models.ScheduledMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_message, err := ds.GetScheduledMessage(context.Background(), utils.StringToUUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return toScheduledMessageModel(_message), nil
+}
+
+func (r *scheduledMessageRepository) GetScheduledMessages(roomId, userId string, tx pgx.Tx) ([]*models.ScheduledMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_messages, err := ds.GetScheduledMessages(context.Background(), dataSource.GetScheduledMessagesParams{
+		RoomID: utils.StringToUUID(roomId),
+		UserID: utils.StringToUUID(userId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*models.ScheduledMessage{}
+	for _, message := range _messages {
+		messages = append(messages, toScheduledMessageModel(message))
+	}
+
+	return messages, nil
+}
+
+// UpdateScheduledMessage updates a pending scheduled message and reports
+// whether it was still pending.
+func (r *scheduledMessageRepository) UpdateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	updatedAt := time.Now()
+	rows, err := ds.UpdateScheduledMessage(context.Background(), dataSource.UpdateScheduledMessageParams{
+		Content:     message.Content,
+		ScheduledAt: message.ScheduledAt,
+		UpdatedAt:   updatedAt,
+		ID:          utils.StringToUUID(message.ID),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if rows > 0 {
+		message.UpdatedAt = updatedAt
+	}
+	return rows > 0, nil
+}
+
+// DeleteScheduledMessage deletes a pending scheduled message and reports
+// whether it was still pending.
+func (r *scheduledMessageRepository) DeleteScheduledMessage(id string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	rows, err := ds.DeleteScheduledMessage(context.Background(), utils.StringToUUID(id))
+	return rows > 0, err
+}
+
+// GetDueScheduledMessages locks and returns up to limit pending messages
+// due at or before dueBefore. Rows already locked by another transaction
+// are skipped, so it must run inside the transaction that sends them.
+func (r *scheduledMessageRepository) GetDueScheduledMessages(dueBefore time.Time, limit int, tx pgx.Tx) ([]*models.ScheduledMessage, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_messages, err := ds.GetDueScheduledMessages(context.Background(), dataSource.GetDueScheduledMessagesParams{
+		DueBefore: dueBefore,
+		BatchSize: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []*models.ScheduledMessage{}
+	for _, message := range _messages {
+		messages = append(messages, toScheduledMessageModel(message))
+	}
+
+	return messages, nil
+}
+
+func (r *scheduledMessageRepository) UpdateScheduledMessageStatus(message *models.ScheduledMessage, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var roomMessageId pgtype.UUID
+	if message.RoomMessageID != "" {
+		roomMessageId = utils.StringPtrToUUID(&message.RoomMessageID)
+	}
+
+	updatedAt := time.Now()
+	err := ds.UpdateScheduledMessageStatus(context.Background(), dataSource.UpdateScheduledMessageStatusParams{
+		Status:        message.Status,
+		RoomMessageID: roomMessageId,
+		UpdatedAt:     updatedAt,
+		ID:            utils.StringToUUID(message.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	message.UpdatedAt = updatedAt
+	return nil
+}
+
+func toScheduledMessageModel(message dataSource.ScheduledMessage) *models.ScheduledMessage {
+	return &models.ScheduledMessage{
+		ID:            utils.UUIDToString(message.ID),
+		CreatedAt:     message.CreatedAt,
+		UpdatedAt:     message.UpdatedAt,
+		RoomID:        utils.UUIDToString(message.RoomID),
+		UserID:        utils.UUIDToString(message.UserID),
+		Content:       message.Content,
+		ScheduledAt:   message.ScheduledAt,
+		Status:        message.Status,
+		RoomMessageID: utils.NullUUIDToString(message.RoomMessageID),
+	}
+}
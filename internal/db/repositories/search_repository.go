@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type searchRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewSearchRepository(conn *pgxpool.Pool) *searchRepository {
+	return &searchRepository{conn}
+}
+
+type SearchMessagesParams struct {
+	Query         string
+	MemberID      string
+	RoomID        *string
+	UserID        *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+func (r *searchRepository) SearchMessages(params SearchMessagesParams, tx pgx.Tx) ([]*models.MessageSearchResult, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	var createdAfter, createdBefore pgtype.Timestamptz
+	if params.CreatedAfter != nil {
+		createdAfter.Scan(*params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		createdBefore.Scan(*params.CreatedBefore)
+	}
+
+	_results, err := ds.SearchRoomMessages(context.Background(), dataSource.SearchRoomMessagesParams{
+		Query:         params.Query,
+		MemberID:      utils.StringToUUID(params.MemberID),
+		RoomID:        utils.StringPtrToUUID(params.RoomID),
+		UserID:        utils.StringPtrToUUID(params.UserID),
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		PageLimit:     int32(params.Limit),
+		PageOffset:    int32(params.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*models.MessageSearchResult{}
+	for _, result := range _results {
+		results = append(results, &models.MessageSearchResult{
+			ID:        utils.UUIDToString(result.ID),
+			CreatedAt: result.CreatedAt,
+			RoomID:    utils.UUIDToString(result.RoomID),
+			UserID:    utils.UUIDToString(result.UserID),
+			Content:   result.Content,
+			Rank:      result.Rank,
+			Snippet:   result.Snippet,
+		})
+	}
+
+	return results, nil
+}
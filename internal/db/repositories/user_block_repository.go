@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dataSource "github.com/princecee/go_chat/internal/db/data-source"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+type userBlockRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewUserBlockRepository(conn *pgxpool.Pool) *userBlockRepository {
+	return &userBlockRepository{conn}
+}
+
+func (r *userBlockRepository) CreateUserBlock(blockerId, blockedId string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.CreateUserBlock(context.Background(), dataSource.CreateUserBlockParams{
+		BlockerID: utils.StringToUUID(blockerId),
+		BlockedID: utils.StringToUUID(blockedId),
+	})
+}
+
+func (r *userBlockRepository) DeleteUserBlock(blockerId, blockedId string, tx pgx.Tx) error {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	return ds.DeleteUserBlock(context.Background(), dataSource.DeleteUserBlockParams{
+		BlockerID: utils.StringToUUID(blockerId),
+		BlockedID: utils.StringToUUID(blockedId),
+	})
+}
+
+func (r *userBlockRepository) GetBlockedUsers(blockerId string, tx pgx.Tx) ([]*models.User, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	_users, err := ds.GetBlockedUsers(context.Background(), utils.StringToUUID(blockerId))
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*models.User{}
+	for _, _user := range _users {
+		users = append(users, &models.User{
+			ID:        _user.ID.String(),
+			FirstName: _user.FirstName,
+			LastName:  _user.LastName,
+			Email:     _user.Email,
+			CreatedAt: _user.CreatedAt,
+			UpdatedAt: _user.UpdatedAt,
+		})
+	}
+
+	return users, nil
+}
+
+// UsersBlocked reports whether any of the users has blocked another one of
+// them.
+func (r *userBlockRepository) UsersBlocked(userIds []string, tx pgx.Tx) (bool, error) {
+	ds := dataSource.New(r.conn)
+	if tx != nil {
+		ds = ds.WithTx(tx)
+	}
+
+	ids := []uuid.UUID{}
+	for _, id := range userIds {
+		ids = append(ids, utils.StringToUUID(id))
+	}
+
+	return ds.UsersBlocked(context.Background(), ids)
+}
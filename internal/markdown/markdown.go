@@ -0,0 +1,393 @@
+// Package markdown renders the Markdown subset supported in chat messages.
+//
+// The subset is **bold**, *italic* or _italic_, `inline code`, fenced code
+// blocks, [links](https://example.com), bare http(s) links and user
+// mentions written as <@user-id>. Everything else is treated as plain text
+// and HTML-escaped, so the rendered output never contains markup that did
+// not come from the renderer itself.
+package markdown
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/princecee/go_chat/internal/models"
+)
+
+// MentionResolver returns the display name of the mentioned user, or false
+// if no such user exists. Unresolved mentions are rendered as plain text.
+type MentionResolver func(userID string) (string, bool)
+
+// Render parses content and returns its sanitized HTML rendering along with
+// the entities found in it. Entity offsets and lengths count runes of
+// content and span the full markup, delimiters included.
+func Render(content string, resolve MentionResolver) (string, []*models.MessageEntity) {
+	r := &renderer{
+		src:      []rune(content),
+		resolve:  resolve,
+		entities: []*models.MessageEntity{},
+		found:    map[string]match{},
+	}
+	r.inline(0, len(r.src), false)
+
+	return r.html.String(), r.entities
+}
+
+// Mentions returns the IDs of the users mentioned in content, once each and
+// in the order they first appear, so they can be resolved before rendering.
+func Mentions(content string) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for {
+		start := strings.Index(content, "<@")
+		if start < 0 {
+			return ids
+		}
+		content = content[start+2:]
+
+		end := strings.IndexAny(content, ">\n")
+		if end < 0 {
+			return ids
+		}
+		if end > 0 && content[end] == '>' && !seen[content[:end]] {
+			seen[content[:end]] = true
+			ids = append(ids, content[:end])
+		}
+		content = content[end:]
+	}
+}
+
+type renderer struct {
+	src      []rune
+	resolve  MentionResolver
+	html     strings.Builder
+	entities []*models.MessageEntity
+	// found remembers the last search for each delimiter, so text that has
+	// already been searched is not searched again, as when a message is
+	// full of unclosed brackets
+	found map[string]match
+}
+
+// match records that the first occurrence of a delimiter at or after from
+// is at, or len(src) if there is none.
+type match struct {
+	from, at int
+}
+
+func (r *renderer) inline(start, end int, inLink bool) {
+	for i := start; i < end; {
+		if next, ok := r.element(i, start, end, inLink); ok {
+			i = next
+			continue
+		}
+
+		if r.src[i] == '\n' {
+			r.html.WriteString("<br>")
+		} else {
+			r.escape(r.src[i])
+		}
+		i++
+	}
+}
+
+// element renders the element starting at i, if any, and returns the
+// position just past it.
+func (r *renderer) element(i, start, end int, inLink bool) (int, bool) {
+	src := r.src
+	switch src[i] {
+	case '\\':
+		if i+1 < end && (unicode.IsPunct(src[i+1]) || unicode.IsSymbol(src[i+1])) {
+			r.escape(src[i+1])
+			return i + 2, true
+		}
+
+	case '`':
+		if r.hasPrefix(i, end, "```") && (i == 0 || src[i-1] == '\n') {
+			if next, ok := r.codeBlock(i, end); ok {
+				return next, true
+			}
+		}
+
+		close := r.index(i+1, end, "`")
+		if close > i+1 {
+			r.addEntity(models.EntityCode, i, close+1)
+			r.html.WriteString("<code>")
+			r.escapeRange(i+1, close)
+			r.html.WriteString("</code>")
+			return close + 1, true
+		}
+
+	case '*', '_':
+		if r.hasPrefix(i, end, "**") {
+			close := r.index(i+2, end, "**")
+			if close > i+2 && !unicode.IsSpace(src[i+2]) && !unicode.IsSpace(src[close-1]) {
+				r.addEntity(models.EntityBold, i, close+2)
+				r.html.WriteString("<strong>")
+				r.inline(i+2, close, inLink)
+				r.html.WriteString("</strong>")
+				return close + 2, true
+			}
+			break
+		}
+
+		// underscores inside words, as in snake_case, are not emphasis
+		if src[i] == '_' && i > start && isWordRune(src[i-1]) {
+			break
+		}
+		close := r.index(i+1, end, string(src[i]))
+		if close > i+1 && !unicode.IsSpace(src[i+1]) && !unicode.IsSpace(src[close-1]) &&
+			!(src[i] == '_' && close+1 < end && isWordRune(src[close+1])) {
+			r.addEntity(models.EntityItalic, i, close+1)
+			r.html.WriteString("<em>")
+			r.inline(i+1, close, inLink)
+			r.html.WriteString("</em>")
+			return close + 1, true
+		}
+
+	case '[':
+		if inLink {
+			break
+		}
+		closeText := r.index(i+1, end, "](")
+		if closeText <= i+1 {
+			break
+		}
+		closeURL := r.index(closeText+2, end, ")")
+		if closeURL < 0 {
+			break
+		}
+		href, ok := safeURL(string(src[closeText+2 : closeURL]))
+		if !ok {
+			break
+		}
+
+		entity := r.addEntity(models.EntityLink, i, closeURL+1)
+		entity.URL = href
+		r.openLink(href)
+		r.inline(i+1, closeText, true)
+		r.html.WriteString("</a>")
+		return closeURL + 1, true
+
+	case '<':
+		if !r.hasPrefix(i, end, "<@") || r.resolve == nil {
+			break
+		}
+		close := r.index(i+2, end, ">")
+		if close <= i+2 {
+			break
+		}
+		userID := string(src[i+2 : close])
+		name, ok := r.resolve(userID)
+		if !ok {
+			break
+		}
+
+		entity := r.addEntity(models.EntityMention, i, close+1)
+		entity.UserID = userID
+		r.html.WriteString(`<span class="mention" data-user-id="`)
+		r.html.WriteString(escapeString(userID))
+		r.html.WriteString(`">@`)
+		r.html.WriteString(escapeString(name))
+		r.html.WriteString("</span>")
+		return close + 1, true
+
+	case 'h':
+		if inLink || i > start && isWordRune(src[i-1]) {
+			break
+		}
+		if !r.hasPrefix(i, end, "http://") && !r.hasPrefix(i, end, "https://") {
+			break
+		}
+
+		j := i
+		for j < end && !unicode.IsSpace(src[j]) && src[j] != '<' {
+			j++
+		}
+		// trailing punctuation usually ends the sentence, not the link
+		for j > i && strings.ContainsRune(".,;:!?)'\"", src[j-1]) {
+			j--
+		}
+		href, ok := safeURL(string(src[i:j]))
+		if !ok {
+			break
+		}
+
+		entity := r.addEntity(models.EntityLink, i, j)
+		entity.URL = href
+		r.openLink(href)
+		r.escapeRange(i, j)
+		r.html.WriteString("</a>")
+		return j, true
+	}
+
+	return i, false
+}
+
+// codeBlock renders a fenced code block opening at i. The closing fence
+// must start a line of its own.
+func (r *renderer) codeBlock(i, end int) (int, bool) {
+	bodyStart := r.index(i+3, end, "\n")
+	if bodyStart < 0 {
+		return i, false
+	}
+	bodyStart++
+
+	var close int
+	if r.hasPrefix(bodyStart, end, "```") {
+		close = bodyStart
+	} else {
+		close = r.index(bodyStart, end, "\n```")
+		if close < 0 {
+			return i, false
+		}
+		close++
+	}
+
+	next := close + 3
+	entity := r.addEntity(models.EntityPre, i, next)
+	entity.Language = strings.TrimSpace(string(r.src[i+3 : bodyStart-1]))
+	if !isLanguage(entity.Language) {
+		entity.Language = ""
+	}
+
+	bodyEnd := close
+	if bodyEnd > bodyStart {
+		bodyEnd-- // newline before the closing fence
+	}
+
+	r.html.WriteString("<pre><code")
+	if entity.Language != "" {
+		r.html.WriteString(` class="language-`)
+		r.html.WriteString(entity.Language)
+		r.html.WriteString(`"`)
+	}
+	r.html.WriteString(">")
+	r.escapeRange(bodyStart, bodyEnd)
+	r.html.WriteString("</code></pre>")
+
+	// the line break after the block is implied by the block itself
+	if next < end && r.src[next] == '\n' {
+		next++
+	}
+	return next, true
+}
+
+func (r *renderer) addEntity(kind string, start, end int) *models.MessageEntity {
+	entity := &models.MessageEntity{
+		Type:   kind,
+		Offset: start,
+		Length: end - start,
+	}
+	r.entities = append(r.entities, entity)
+	return entity
+}
+
+func (r *renderer) openLink(href string) {
+	r.html.WriteString(`<a href="`)
+	r.html.WriteString(escapeString(href))
+	r.html.WriteString(`" rel="nofollow noopener noreferrer" target="_blank">`)
+}
+
+// index returns the position of the first occurrence of s in src[from:end],
+// or -1.
+func (r *renderer) index(from, end int, s string) int {
+	m, ok := r.found[s]
+	if !ok || from < m.from || from > m.at {
+		m = match{from: from, at: len(r.src)}
+		for i := from; i < len(r.src); i++ {
+			if r.hasPrefix(i, len(r.src), s) {
+				m.at = i
+				break
+			}
+		}
+		r.found[s] = m
+	}
+
+	if !r.hasPrefix(m.at, end, s) {
+		return -1
+	}
+	return m.at
+}
+
+func (r *renderer) hasPrefix(i, end int, s string) bool {
+	for _, c := range s {
+		if i >= end || r.src[i] != c {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func (r *renderer) escapeRange(start, end int) {
+	for i := start; i < end; i++ {
+		r.escape(r.src[i])
+	}
+}
+
+func (r *renderer) escape(c rune) {
+	switch c {
+	case '&':
+		r.html.WriteString("&amp;")
+	case '<':
+		r.html.WriteString("&lt;")
+	case '>':
+		r.html.WriteString("&gt;")
+	case '"':
+		r.html.WriteString("&#34;")
+	case '\'':
+		r.html.WriteString("&#39;")
+	default:
+		r.html.WriteRune(c)
+	}
+}
+
+func escapeString(s string) string {
+	r := &renderer{}
+	for _, c := range s {
+		r.escape(c)
+	}
+	return r.html.String()
+}
+
+// safeURL reports whether raw is an absolute http(s) or mailto URL and
+// returns it normalised. Anything else, javascript: URLs in particular, is
+// rejected.
+func safeURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", false
+		}
+	case "mailto":
+		if u.Opaque == "" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	return u.String(), true
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func isLanguage(s string) bool {
+	if len(s) == 0 || len(s) > 20 {
+		return false
+	}
+	for _, c := range s {
+		if !isWordRune(c) && !strings.ContainsRune("+#-_.", c) {
+			return false
+		}
+	}
+	return true
+}
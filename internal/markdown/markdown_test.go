@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func resolve(userID string) (string, bool) {
+	if userID == "u1" {
+		return "Ada <Lovelace>", true
+	}
+	return "", false
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		html    string
+	}{
+		{"plain", "hello <b>world</b> & co", "hello &lt;b&gt;world&lt;/b&gt; &amp; co"},
+		{"bold", "a **bold** move", "a <strong>bold</strong> move"},
+		{"italic", "*one* and _two_", "<em>one</em> and <em>two</em>"},
+		{"snake case", "snake_case_name", "snake_case_name"},
+		{"unclosed", "2 * 3 **", "2 * 3 **"},
+		{"code", "run `rm -rf <dir>` now", "run <code>rm -rf &lt;dir&gt;</code> now"},
+		{"code ignores markup", "`**x**`", "<code>**x**</code>"},
+		{"escaped", `\*not italic\*`, "*not italic*"},
+		{"newline", "a\nb", "a<br>b"},
+		{
+			"code block",
+			"```go\nfmt.Println(\"<hi>\")\n```\nafter",
+			`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>after`,
+		},
+		{
+			"link",
+			"see [the **docs**](https://example.com/a?b=1&c=2)",
+			`see <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer" target="_blank">the <strong>docs</strong></a>`,
+		},
+		{"javascript link", "[x](javascript:alert(1))", "[x](javascript:alert(1))"},
+		{
+			"autolink",
+			"go to https://example.com/path.",
+			`go to <a href="https://example.com/path" rel="nofollow noopener noreferrer" target="_blank">https://example.com/path</a>.`,
+		},
+		{
+			"mention",
+			"hi <@u1>",
+			`hi <span class="mention" data-user-id="u1">@Ada &lt;Lovelace&gt;</span>`,
+		},
+		{"unknown mention", "hi <@u2>", "hi &lt;@u2&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, _ := Render(tt.content, resolve)
+			assert.Equal(t, tt.html, html)
+		})
+	}
+}
+
+func TestRenderEntities(t *testing.T) {
+	_, entities := Render("é **b** [l](https://x.io) <@u1> `c`", resolve)
+
+	assert.Equal(t, []*models.MessageEntity{
+		{Type: models.EntityBold, Offset: 2, Length: 5},
+		{Type: models.EntityLink, Offset: 8, Length: 17, URL: "https://x.io"},
+		{Type: models.EntityMention, Offset: 26, Length: 5, UserID: "u1"},
+		{Type: models.EntityCode, Offset: 32, Length: 3},
+	}, entities)
+
+	_, entities = Render("nothing here", resolve)
+	assert.Empty(t, entities)
+	assert.NotNil(t, entities)
+}
+
+func TestRenderUnclosed(t *testing.T) {
+	// unclosed delimiters must not make rendering quadratic
+	for _, content := range []string{
+		strings.Repeat("[", 100000),
+		strings.Repeat("[a](b ", 20000),
+		strings.Repeat("*a ", 30000),
+	} {
+		html, _ := Render(content, resolve)
+		assert.Equal(t, content, html)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	assert.Equal(t, []string{"u1", "u2"}, Mentions("<@u1> and <@u2>, again <@u1>"))
+	assert.Equal(t, []string{}, Mentions("<@> <@u3\n> <@u4"))
+}
@@ -0,0 +1,141 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions too large")
+)
+
+// MaxPixels bounds the decoded size of an image so a small, highly
+// compressed upload cannot exhaust memory.
+const MaxPixels = 40_000_000
+
+// Decode decodes a JPEG, PNG or GIF image and reports its format.
+func Decode(data []byte) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	return img, format, err
+}
+
+// Encode encodes img as PNG when the source was a PNG, to keep transparency,
+// and as JPEG otherwise. It returns the encoded bytes and their content type.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	buf := &bytes.Buffer{}
+	if format == "png" {
+		err := png.Encode(buf, img)
+		return buf.Bytes(), "image/png", err
+	}
+
+	err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+	return buf.Bytes(), "image/jpeg", err
+}
+
+// Resize scales img down so that neither side exceeds maxSide, keeping its
+// aspect ratio. Each destination pixel is the average of the source pixels
+// it covers. Images that already fit are returned unchanged.
+func Resize(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	src := toRGBA(img)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0, sy1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			sx0, sx1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+
+	return dst
+}
+
+// DominantColor returns the most common colour of img as a hex string. Colours
+// are grouped into coarse buckets so that gradients and noise do not split
+// the vote, and the winning bucket's average colour is returned.
+func DominantColor(img image.Image) string {
+	src := toRGBA(Resize(img, 64))
+
+	type bucket struct{ r, g, b, n int }
+	buckets := map[int]*bucket{}
+	var best *bucket
+	for i := 0; i < len(src.Pix); i += 4 {
+		r, g, b, a := int(src.Pix[i]), int(src.Pix[i+1]), int(src.Pix[i+2]), int(src.Pix[i+3])
+		if a < 128 {
+			continue
+		}
+
+		key := r>>4<<8 | g>>4<<4 | b>>4
+		bk, ok := buckets[key]
+		if !ok {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.r, bk.g, bk.b, bk.n = bk.r+r, bk.g+g, bk.b+b, bk.n+1
+
+		if best == nil || bk.n > best.n {
+			best = bk
+		}
+	}
+
+	if best == nil {
+		return "#000000"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.n, best.g/best.n, best.b/best.n)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,103 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+	for y := 0; y < h/4; y++ {
+		for x := 0; x < w/4; x++ {
+			img.Set(x, y, color.RGBA{0, 0, 255, 255})
+		}
+	}
+	return img
+}
+
+func TestResize(t *testing.T) {
+	img := Resize(newImage(400, 200), 100)
+	assert.Equal(t, 100, img.Bounds().Dx())
+	assert.Equal(t, 50, img.Bounds().Dy())
+
+	img = Resize(newImage(100, 300), 30)
+	assert.Equal(t, 10, img.Bounds().Dx())
+	assert.Equal(t, 30, img.Bounds().Dy())
+
+	small := newImage(20, 20)
+	assert.Same(t, small, Resize(small, 100))
+}
+
+func TestDominantColor(t *testing.T) {
+	assert.Equal(t, "#ff0000", DominantColor(newImage(200, 100)))
+}
+
+func TestStripMetadata(t *testing.T) {
+	t.Run("jpeg", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		assert.NoError(t, jpeg.Encode(buf, newImage(16, 16), nil))
+		encoded := buf.Bytes()
+
+		exif := append([]byte("Exif\x00\x00"), []byte("GPS 51.5N 0.12W")...)
+		segment := []byte{0xFF, 0xE1, 0, 0}
+		binary.BigEndian.PutUint16(segment[2:], uint16(len(exif)+2))
+		segment = append(segment, exif...)
+
+		data := append([]byte{}, encoded[:2]...)
+		data = append(data, segment...)
+		data = append(data, encoded[2:]...)
+
+		stripped, err := StripMetadata(data, "jpeg")
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stripped), "GPS")
+		assert.Equal(t, encoded, stripped)
+
+		_, format, err := Decode(stripped)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+	})
+
+	t.Run("png", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		assert.NoError(t, png.Encode(buf, newImage(16, 16)))
+		encoded := buf.Bytes()
+
+		exif := []byte("MM\x00*GPS 51.5N 0.12W")
+		chunk := make([]byte, 8)
+		binary.BigEndian.PutUint32(chunk, uint32(len(exif)))
+		copy(chunk[4:], "eXIf")
+		chunk = append(chunk, exif...)
+		chunk = append(chunk, 0, 0, 0, 0)
+
+		// IHDR is always the first chunk and is 25 bytes long
+		data := append([]byte{}, encoded[:33]...)
+		data = append(data, chunk...)
+		data = append(data, encoded[33:]...)
+
+		stripped, err := StripMetadata(data, "png")
+		assert.NoError(t, err)
+		assert.Equal(t, encoded, stripped)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := StripMetadata([]byte("not an image"), "jpeg")
+		assert.ErrorIs(t, err, ErrMalformedImage)
+
+		for _, length := range []byte{0, 1} {
+			_, err = StripMetadata([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0, length}, "jpeg")
+			assert.ErrorIs(t, err, ErrMalformedImage)
+		}
+	})
+}
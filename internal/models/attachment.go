@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	AttachmentStatusPending   = "pending"
+	AttachmentStatusProcessed = "processed"
+	AttachmentStatusSkipped   = "skipped"
+	AttachmentStatusFailed    = "failed"
+)
+
+type Attachment struct {
+	ID               string                 `json:"id"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	RoomID           string                 `json:"room_id"`
+	RoomMessageID    string                 `json:"room_message_id"`
+	UserID           string                 `json:"user_id"`
+	FileName         string                 `json:"file_name"`
+	ContentType      string                 `json:"content_type"`
+	Size             int64                  `json:"size"`
+	StorageKey       string                 `json:"-"`
+	URL              string                 `json:"url"`
+	Width            int                    `json:"width,omitempty"`
+	Height           int                    `json:"height,omitempty"`
+	DominantColor    string                 `json:"dominant_color,omitempty"`
+	ProcessingStatus string                 `json:"processing_status"`
+	Thumbnails       []*AttachmentThumbnail `json:"thumbnails,omitempty"`
+}
+
+type AttachmentThumbnail struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AttachmentID string    `json:"attachment_id"`
+	Label        string    `json:"label"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	StorageKey   string    `json:"-"`
+	URL          string    `json:"url"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// MessageDraft is the unsent message a user is writing in a room, kept on
+// the server so it follows them between devices. A cleared draft has no
+// content.
+type MessageDraft struct {
+	UserID    string    `json:"user_id"`
+	RoomID    string    `json:"room_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+const (
+	EventMessageCreated  = "message.created"
+	EventMessagePinned   = "message.pinned"
+	EventMessageUnpinned = "message.unpinned"
+	EventMessageUnfurled = "message.unfurled"
+	EventMessageExpired  = "message.expired"
+	EventMessageRejected = "message.rejected"
+	EventMessageDeleted  = "message.deleted"
+
+	EventAttachmentProcessed = "attachment.processed"
+
+	EventPollUpdated = "poll.updated"
+
+	EventRoomUpdated = "room.updated"
+	EventRoomInvited = "room.invited"
+
+	EventMemberJoined = "member.joined"
+	EventMemberLeft   = "member.left"
+
+	EventMemberKicked   = "member.kicked"
+	EventMemberBanned   = "member.banned"
+	EventMemberUnbanned = "member.unbanned"
+	EventMemberMuted    = "member.muted"
+	EventMemberUnmuted  = "member.unmuted"
+
+	EventJoinRequestCreated  = "join_request.created"
+	EventJoinRequestReviewed = "join_request.reviewed"
+
+	EventCommandResponse = "command.response"
+
+	EventDraftUpdated = "draft.updated"
+)
+
+// Event is a real-time notification pushed to connected websocket clients.
+// Events with a UserID are delivered to that user only, otherwise they are
+// delivered to every member of RoomID. Events with a SkipSession are not
+// delivered to that session, which caused them.
+type Event struct {
+	Type        string `json:"type"`
+	RoomID      string `json:"room_id,omitempty"`
+	UserID      string `json:"-"`
+	SkipSession string `json:"-"`
+	Data        any    `json:"data,omitempty"`
+}
+
+// MessagesRemoved is the payload of events reporting messages that were
+// deleted from a room and should be dropped from view.
+type MessagesRemoved struct {
+	MessageIDs []string `json:"message_ids"`
+}
+
+// MessageRejected is the payload of EventMessageRejected, sent only to the
+// user whose message was not posted.
+type MessageRejected struct {
+	Error string `json:"error"`
+}
+
+// CommandResponse is the payload of EventCommandResponse, the reply to a
+// slash command which is only sent to the user who ran it.
+type CommandResponse struct {
+	Command string `json:"command"`
+	Text    string `json:"text,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	ImportKindUser    = "user"
+	ImportKindChannel = "channel"
+	ImportKindMessage = "message"
+)
+
+// ImportMapping records the local row an object from another system was
+// imported as, so imports can be re-run without creating duplicates.
+// ParentID groups mappings under another external object, such as the
+// messages of a channel.
+type ImportMapping struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Source     string    `json:"source"`
+	Kind       string    `json:"kind"`
+	ExternalID string    `json:"external_id"`
+	ParentID   string    `json:"parent_id"`
+	LocalID    string    `json:"local_id"`
+}
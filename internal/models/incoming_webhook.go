@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// IncomingWebhook lets an external system post into a room as a named
+// integration. Messages are authored by a dedicated integration user, UserID,
+// that is a member of the room.
+type IncomingWebhook struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by"`
+	// Token is the secret part of the webhook URL. It is only known when
+	// the webhook is created, after that only its hash is stored.
+	Token string `json:"token,omitempty"`
+}
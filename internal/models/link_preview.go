@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type LinkPreview struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+}
+
+// MessageUnfurled is the payload of EventMessageUnfurled.
+type MessageUnfurled struct {
+	MessageID string         `json:"message_id"`
+	Previews  []*LinkPreview `json:"previews"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	MemberActionKick   = "kick"
+	MemberActionBan    = "ban"
+	MemberActionUnban  = "unban"
+	MemberActionMute   = "mute"
+	MemberActionUnmute = "unmute"
+)
+
+// RoomBan keeps a user out of a room until it expires or is lifted. A ban
+// without ExpiresAt is permanent.
+type RoomBan struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	RoomID    string     `json:"room_id"`
+	UserID    string     `json:"user_id"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RoomMute stops a member from posting to a room until it expires or is
+// lifted. The member can still read the room.
+type RoomMute struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	RoomID    string     `json:"room_id"`
+	UserID    string     `json:"user_id"`
+	Reason    string     `json:"reason"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RoomMemberAction is the audit record of a moderator kicking, banning or
+// muting a user, or lifting a ban or mute. It is also the payload of the
+// event telling the user about it.
+type RoomMemberAction struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RoomID    string     `json:"room_id"`
+	UserID    string     `json:"user_id"`
+	ActorID   string     `json:"actor_id,omitempty"`
+	Action    string     `json:"action"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/princecee/go_chat/internal/moderation"
+)
+
+// RoomModeration is a room's moderation settings. Rooms without any let
+// every message through.
+type RoomModeration struct {
+	RoomID    string             `json:"room_id"`
+	Config    *moderation.Config `json:"config"`
+	UpdatedBy string             `json:"updated_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ModerationAuditEntry records a moderation filter matching a message.
+// Content is the message as it was sent, before any redaction. Rejected
+// messages have no MessageID.
+type ModerationAuditEntry struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	MessageID *string   `json:"message_id"`
+	Filter    string    `json:"filter"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason"`
+	Content   string    `json:"content"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type Poll struct {
+	ID             string        `json:"id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	RoomID         string        `json:"room_id"`
+	RoomMessageID  string        `json:"room_message_id"`
+	CreatedBy      string        `json:"created_by"`
+	Question       string        `json:"question"`
+	MultipleChoice bool          `json:"multiple_choice"`
+	Anonymous      bool          `json:"anonymous"`
+	ClosesAt       *time.Time    `json:"closes_at,omitempty"`
+	ClosedAt       *time.Time    `json:"closed_at,omitempty"`
+	Closed         bool          `json:"closed"`
+	Options        []*PollOption `json:"options"`
+	TotalVoters    int           `json:"total_voters"`
+	VotedOptionIDs []string      `json:"voted_option_ids,omitempty"`
+}
+
+type PollOption struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PollID    string    `json:"poll_id"`
+	Position  int       `json:"position"`
+	Text      string    `json:"text"`
+	VoteCount int       `json:"vote_count"`
+	VoterIDs  []string  `json:"voter_ids,omitempty"`
+}
+
+type PollVote struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	PollID       string    `json:"poll_id"`
+	PollOptionID string    `json:"poll_option_id"`
+	UserID       string    `json:"user_id"`
+}
@@ -10,22 +10,107 @@ type Room struct {
 	Description string    `json:"description"`
 	MaxMembers  int       `json:"max_members"`
 	CreatedBy   string    `json:"created_by"`
+	MessageTTL  int       `json:"message_ttl"`
+	Kind        string    `json:"kind"`
+	// ParticipantsKey identifies a conversation by who is in it, so it can
+	// be reopened. Rooms have none.
+	ParticipantsKey string `json:"-"`
+	// RetentionDays is how long messages are kept before they are purged.
+	// Nil falls back to the server default and zero keeps them forever.
+	RetentionDays *int `json:"retention_days"`
+	// LegalHold suspends retention and expiry purges while set.
+	LegalHold bool `json:"legal_hold"`
+	// Visibility decides whether the room is listed and who may join it.
+	Visibility string `json:"visibility"`
 }
 
+const (
+	RoomKindRoom   = "room"
+	RoomKindDirect = "direct"
+	RoomKindGroup  = "group"
+)
+
+// Public rooms are listed and open to anyone. Restricted rooms are listed
+// but joining them needs an approved join request. Private rooms are hidden
+// and only joinable through an invite.
+const (
+	RoomVisibilityPublic     = "public"
+	RoomVisibilityRestricted = "restricted"
+	RoomVisibilityPrivate    = "private"
+)
+
+// DirectMessage is a conversation, one-to-one or group, along with everyone
+// in it.
+type DirectMessage struct {
+	Room         *Room   `json:"room"`
+	Participants []*User `json:"participants"`
+}
+
+const (
+	RoomRoleOwner     = "owner"
+	RoomRoleAdmin     = "admin"
+	RoomRoleModerator = "moderator"
+	RoomRoleMember    = "member"
+)
+
 type RoomMember struct {
 	ID        string    `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	RoomID    string    `json:"room_id"`
 	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
 }
 
 type RoomMessage struct {
-	ID           string    `json:"id"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	RoomID       string    `json:"room_id"`
-	RoomMemberID string    `json:"room_member_id"`
-	UserID       string    `json:"user_id"`
-	Content      string    `json:"content"`
+	ID           string           `json:"id"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	RoomID       string           `json:"room_id"`
+	RoomMemberID string           `json:"room_member_id"`
+	UserID       string           `json:"user_id"`
+	Content      string           `json:"content"`
+	ContentHTML  string           `json:"content_html"`
+	Entities     []*MessageEntity `json:"entities"`
+	Attachments  []*Attachment    `json:"attachments,omitempty"`
+	Previews     []*LinkPreview   `json:"previews,omitempty"`
+	ExpiresAt    *time.Time       `json:"expires_at,omitempty"`
+	Poll         *Poll            `json:"poll,omitempty"`
+}
+
+const (
+	EntityBold    = "bold"
+	EntityItalic  = "italic"
+	EntityCode    = "code"
+	EntityPre     = "pre"
+	EntityLink    = "link"
+	EntityMention = "mention"
+)
+
+// MessageEntity marks a span of rich text in a message's content. Offset
+// and Length count runes of the raw content and cover the whole markup,
+// delimiters included.
+type MessageEntity struct {
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`
+	UserID   string `json:"user_id,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type RoomMessagePage struct {
+	Messages   []*RoomMessage
+	NextCursor string
+	PrevCursor string
+}
+
+type RoomPin struct {
+	ID            string       `json:"id"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	RoomID        string       `json:"room_id"`
+	RoomMessageID string       `json:"room_message_id"`
+	PinnedBy      string       `json:"pinned_by"`
+	Message       *RoomMessage `json:"message,omitempty"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RoomInvite is a link that lets whoever holds its code join a room,
+// including private ones. It stops working once it expires, runs out of
+// uses or is revoked.
+type RoomInvite struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	RoomID    string     `json:"room_id"`
+	Code      string     `json:"code"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	// MaxUses is how many people may join with the invite. Nil is no limit.
+	MaxUses   *int       `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// RoomJoinRequest is a user asking to join a restricted room. It stays
+// pending until someone who can approve members reviews it.
+type RoomJoinRequest struct {
+	ID         string     `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	RoomID     string     `json:"room_id"`
+	UserID     string     `json:"user_id"`
+	Message    string     `json:"message"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SavedMessage is a message a user bookmarked, with an optional note only
+// they can see. Room is where the message was posted.
+type SavedMessage struct {
+	ID        string       `json:"id"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	UserID    string       `json:"-"`
+	MessageID string       `json:"message_id"`
+	Note      string       `json:"note"`
+	Message   *RoomMessage `json:"message,omitempty"`
+	Room      *Room        `json:"room,omitempty"`
+}
+
+type SavedMessagePage struct {
+	Items      []*SavedMessage
+	NextCursor string
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	ScheduledMessageStatusPending = "pending"
+	ScheduledMessageStatusSent    = "sent"
+	ScheduledMessageStatusFailed  = "failed"
+)
+
+type ScheduledMessage struct {
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	RoomID        string    `json:"room_id"`
+	UserID        string    `json:"user_id"`
+	Content       string    `json:"content"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Status        string    `json:"status"`
+	RoomMessageID string    `json:"room_message_id,omitempty"`
+}
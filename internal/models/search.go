@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type MessageSearchResult struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	Content   string    `json:"content"`
+	Rank      float32   `json:"rank"`
+	Snippet   string    `json:"snippet"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TranscriptEntry is a message as it appears in a room transcript, with
+// its author's name resolved.
+type TranscriptEntry struct {
+	MessageID  string    `json:"message_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	AuthorID   string    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Content    string    `json:"content"`
+}
@@ -0,0 +1,306 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrEmptyBlocklist   = errors.New("blocklist must have at least one word")
+	ErrInvalidPattern   = errors.New("invalid regex pattern")
+	ErrInvalidCapsRatio = errors.New("caps max_ratio must be between 0 and 1")
+	ErrInvalidRepeats   = errors.New("max_repeats must be at least 1")
+)
+
+const (
+	defaultCapsRatio   = 0.7
+	defaultCapsLetters = 10
+	defaultMaxRepeats  = 4
+
+	linkPlaceholder = "[link removed]"
+)
+
+var (
+	linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()\[\]]+`)
+	wordPattern = regexp.MustCompile(`\S+`)
+)
+
+type blocklistFilter struct {
+	pattern *regexp.Regexp
+	action  Action
+}
+
+// NewBlocklistFilter matches any of words, ignoring case, where they appear
+// as whole words. Words starting or ending in punctuation, like "c++" or
+// "@here", only need a word boundary on their other side.
+func NewBlocklistFilter(words []string, action Action) (Filter, error) {
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+
+	quoted := []string{}
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, wordBoundary(word[0])+regexp.QuoteMeta(word)+wordBoundary(word[len(word)-1]))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil, ErrEmptyBlocklist
+	}
+
+	return &blocklistFilter{
+		pattern: regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)`),
+		action:  action,
+	}, nil
+}
+
+// wordBoundary returns the \b to put next to c, if it is a word character
+// and so can have one.
+func wordBoundary(c byte) string {
+	if c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return `\b`
+	}
+	return ""
+}
+
+func (f *blocklistFilter) Name() string {
+	return "blocklist"
+}
+
+func (f *blocklistFilter) Check(content string) *Decision {
+	if !f.pattern.MatchString(content) {
+		return nil
+	}
+	return &Decision{Action: f.action, Reason: "contains a blocked word", Content: redact(f.pattern, content)}
+}
+
+type regexFilter struct {
+	name    string
+	pattern *regexp.Regexp
+	action  Action
+}
+
+// NewRegexFilter matches pattern, using RE2 syntax. The rule's name is
+// given as the reason for its decisions and defaults to the pattern.
+func NewRegexFilter(name, pattern string, action Action) (Filter, error) {
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	if name == "" {
+		name = pattern
+	}
+
+	return &regexFilter{name: name, pattern: re, action: action}, nil
+}
+
+func (f *regexFilter) Name() string {
+	return "regex"
+}
+
+func (f *regexFilter) Check(content string) *Decision {
+	if !f.pattern.MatchString(content) {
+		return nil
+	}
+	return &Decision{Action: f.action, Reason: "matches rule " + f.name, Content: redact(f.pattern, content)}
+}
+
+type linkFilter struct {
+	allowed []string
+	action  Action
+}
+
+// NewLinkFilter matches links to anywhere other than allowedDomains and
+// their subdomains. Redacted links are replaced with a placeholder.
+func NewLinkFilter(allowedDomains []string, action Action) (Filter, error) {
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+
+	allowed := []string{}
+	for _, domain := range allowedDomains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "*.")
+		if domain != "" {
+			allowed = append(allowed, domain)
+		}
+	}
+
+	return &linkFilter{allowed: allowed, action: action}, nil
+}
+
+func (f *linkFilter) Name() string {
+	return "links"
+}
+
+func (f *linkFilter) Check(content string) *Decision {
+	matched := false
+	redacted := linkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		if f.isAllowed(link) {
+			return link
+		}
+		matched = true
+		return linkPlaceholder
+	})
+	if !matched {
+		return nil
+	}
+	return &Decision{Action: f.action, Reason: "contains a link", Content: redacted}
+}
+
+func (f *linkFilter) isAllowed(link string) bool {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range f.allowed {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+type capsFilter struct {
+	maxRatio   float64
+	minLetters int
+	action     Action
+}
+
+// NewCapsFilter matches messages with more than maxRatio of their letters
+// in upper case, once they have at least minLetters letters. Zero values
+// select the defaults. Redacting lowers the case of the whole message.
+func NewCapsFilter(maxRatio float64, minLetters int, action Action) (Filter, error) {
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+	if maxRatio < 0 || maxRatio > 1 {
+		return nil, ErrInvalidCapsRatio
+	}
+	if maxRatio == 0 {
+		maxRatio = defaultCapsRatio
+	}
+	if minLetters <= 0 {
+		minLetters = defaultCapsLetters
+	}
+
+	return &capsFilter{maxRatio: maxRatio, minLetters: minLetters, action: action}, nil
+}
+
+func (f *capsFilter) Name() string {
+	return "caps"
+}
+
+func (f *capsFilter) Check(content string) *Decision {
+	letters, upper := 0, 0
+	for _, c := range content {
+		if unicode.IsLetter(c) {
+			letters++
+			if unicode.IsUpper(c) {
+				upper++
+			}
+		}
+	}
+	if letters < f.minLetters || float64(upper)/float64(letters) <= f.maxRatio {
+		return nil
+	}
+	return &Decision{Action: f.action, Reason: "too many capital letters", Content: strings.ToLower(content)}
+}
+
+type repetitionFilter struct {
+	maxRepeats int
+	action     Action
+}
+
+// NewRepetitionFilter matches characters or words repeated more than
+// maxRepeats times in a row, such as "nooooooo" or "spam spam spam spam
+// spam". Digits and whitespace are not counted. A zero maxRepeats selects
+// the default. Redacting cuts runs down to maxRepeats.
+func NewRepetitionFilter(maxRepeats int, action Action) (Filter, error) {
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+	if maxRepeats < 0 {
+		return nil, ErrInvalidRepeats
+	}
+	if maxRepeats == 0 {
+		maxRepeats = defaultMaxRepeats
+	}
+
+	return &repetitionFilter{maxRepeats: maxRepeats, action: action}, nil
+}
+
+func (f *repetitionFilter) Name() string {
+	return "repetition"
+}
+
+func (f *repetitionFilter) Check(content string) *Decision {
+	redacted, chars := f.collapseCharacters(content)
+	redacted, words := f.collapseWords(redacted)
+	if !chars && !words {
+		return nil
+	}
+	return &Decision{Action: f.action, Reason: "too much repetition", Content: redacted}
+}
+
+func (f *repetitionFilter) collapseCharacters(content string) (string, bool) {
+	var b strings.Builder
+	collapsed := false
+
+	var prev rune
+	run := 0
+	for _, c := range content {
+		if c == prev {
+			run++
+		} else {
+			prev, run = c, 1
+		}
+
+		if run > f.maxRepeats && !unicode.IsSpace(c) && !unicode.IsDigit(c) {
+			collapsed = true
+			continue
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String(), collapsed
+}
+
+func (f *repetitionFilter) collapseWords(content string) (string, bool) {
+	var b strings.Builder
+	collapsed := false
+
+	prev, run, last := "", 0, 0
+	for _, loc := range wordPattern.FindAllStringIndex(content, -1) {
+		word := strings.ToLower(content[loc[0]:loc[1]])
+		if word == prev {
+			run++
+		} else {
+			prev, run = word, 1
+		}
+
+		if run > f.maxRepeats {
+			// drop the word along with the space before it
+			last = loc[1]
+			collapsed = true
+			continue
+		}
+		b.WriteString(content[last:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), collapsed
+}
@@ -0,0 +1,220 @@
+// Package moderation inspects message content before it is posted. A room's
+// Config selects the filters that run, in order, and what each does with
+// content it matches: let it through and only record it, redact the match,
+// or reject the message outright.
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionRedact Action = "redact"
+	ActionReject Action = "reject"
+)
+
+var ErrInvalidAction = errors.New("action must be one of allow, redact or reject")
+
+// severity orders actions so a pipeline's result is its strictest decision.
+func (a Action) severity() int {
+	switch a {
+	case ActionRedact:
+		return 1
+	case ActionReject:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func (a Action) validate() error {
+	switch a {
+	case ActionAllow, ActionRedact, ActionReject:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidAction, a)
+	}
+}
+
+// Decision is what a filter did with content it matched. Content is the
+// redacted content when Action is ActionRedact.
+type Decision struct {
+	Filter  string `json:"filter"`
+	Action  Action `json:"action"`
+	Reason  string `json:"reason"`
+	Content string `json:"-"`
+}
+
+// Filter checks message content. Check returns nil when the content passes.
+type Filter interface {
+	Name() string
+	Check(content string) *Decision
+}
+
+// Result is the outcome of running a pipeline over a message.
+type Result struct {
+	// Action is the strictest action taken by any filter.
+	Action Action
+	// Content is the message content after redactions.
+	Content string
+	// Decisions holds one entry per filter that matched, in the order
+	// they ran.
+	Decisions []*Decision
+}
+
+// Rejection returns the decision that rejected the message, if any.
+func (r *Result) Rejection() *Decision {
+	for _, decision := range r.Decisions {
+		if decision.Action == ActionReject {
+			return decision
+		}
+	}
+	return nil
+}
+
+// Pipeline runs filters in order. Each filter sees the content as redacted
+// by the ones before it, and a rejection stops the pipeline.
+type Pipeline []Filter
+
+func (p Pipeline) Run(content string) *Result {
+	result := &Result{Action: ActionAllow, Content: content, Decisions: []*Decision{}}
+
+	for _, filter := range p {
+		decision := filter.Check(result.Content)
+		if decision == nil {
+			continue
+		}
+
+		decision.Filter = filter.Name()
+		result.Decisions = append(result.Decisions, decision)
+		if decision.Action.severity() > result.Action.severity() {
+			result.Action = decision.Action
+		}
+
+		switch decision.Action {
+		case ActionRedact:
+			result.Content = decision.Content
+		case ActionReject:
+			return result
+		}
+	}
+
+	return result
+}
+
+// Config is a room's moderation settings. Filters left unset do not run.
+type Config struct {
+	Blocklist  *BlocklistConfig  `json:"blocklist,omitempty"`
+	Regex      []*RegexConfig    `json:"regex,omitempty"`
+	Links      *LinksConfig      `json:"links,omitempty"`
+	Caps       *CapsConfig       `json:"caps,omitempty"`
+	Repetition *RepetitionConfig `json:"repetition,omitempty"`
+}
+
+type BlocklistConfig struct {
+	Words  []string `json:"words"`
+	Action Action   `json:"action"`
+}
+
+type RegexConfig struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Action  Action `json:"action"`
+}
+
+type LinksConfig struct {
+	// AllowedDomains are let through, along with their subdomains.
+	AllowedDomains []string `json:"allowed_domains"`
+	Action         Action   `json:"action"`
+}
+
+type CapsConfig struct {
+	// MaxRatio is the largest share of letters allowed to be upper case.
+	MaxRatio float64 `json:"max_ratio"`
+	// MinLetters is how many letters a message needs before it is checked,
+	// so short acronyms and shouted one-word replies pass.
+	MinLetters int    `json:"min_letters"`
+	Action     Action `json:"action"`
+}
+
+type RepetitionConfig struct {
+	// MaxRepeats is how many times in a row a character or word may
+	// appear.
+	MaxRepeats int    `json:"max_repeats"`
+	Action     Action `json:"action"`
+}
+
+// New builds the pipeline for config. Filters run in the order blocklist,
+// regex rules, links, caps and repetition.
+func New(config *Config) (Pipeline, error) {
+	pipeline := Pipeline{}
+	if config == nil {
+		return pipeline, nil
+	}
+
+	if c := config.Blocklist; c != nil {
+		filter, err := NewBlocklistFilter(c.Words, c.Action)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, filter)
+	}
+
+	for _, c := range config.Regex {
+		filter, err := NewRegexFilter(c.Name, c.Pattern, c.Action)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, filter)
+	}
+
+	if c := config.Links; c != nil {
+		filter, err := NewLinkFilter(c.AllowedDomains, c.Action)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, filter)
+	}
+
+	if c := config.Caps; c != nil {
+		filter, err := NewCapsFilter(c.MaxRatio, c.MinLetters, c.Action)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, filter)
+	}
+
+	if c := config.Repetition; c != nil {
+		filter, err := NewRepetitionFilter(c.MaxRepeats, c.Action)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, filter)
+	}
+
+	return pipeline, nil
+}
+
+// Validate reports whether config builds a pipeline.
+func (config *Config) Validate() error {
+	_, err := New(config)
+	return err
+}
+
+// redact masks every match of pattern in content.
+func redact(pattern *regexp.Regexp, content string) string {
+	return pattern.ReplaceAllStringFunc(content, mask)
+}
+
+func mask(s string) string {
+	masked := []rune(s)
+	for i := range masked {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
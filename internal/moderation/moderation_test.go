@@ -0,0 +1,117 @@
+package moderation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters(t *testing.T) {
+	blocklist, err := NewBlocklistFilter([]string{"darn", " heck "}, ActionRedact)
+	require.NoError(t, err)
+	symbols, err := NewBlocklistFilter([]string{"c++", "$$$", "@here"}, ActionRedact)
+	require.NoError(t, err)
+	regex, err := NewRegexFilter("card number", `\b\d{4}-\d{4}-\d{4}-\d{4}\b`, ActionRedact)
+	require.NoError(t, err)
+	links, err := NewLinkFilter([]string{"example.com"}, ActionRedact)
+	require.NoError(t, err)
+	caps, err := NewCapsFilter(0, 0, ActionRedact)
+	require.NoError(t, err)
+	repetition, err := NewRepetitionFilter(3, ActionRedact)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		content string
+		want    string
+	}{
+		{"blocklist", blocklist, "Darn it, what the HECK", "**** it, what the ****"},
+		{"blocklist whole words", blocklist, "darning checks", ""},
+		{"blocklist punctuation", symbols, "C++ for $$$, ping @here", "*** for ***, ping *****"},
+		{"blocklist punctuation whole words", symbols, "abc++ and @heretic", ""},
+		{"regex", regex, "card 1234-5678-9012-3456 ok", "card ******************* ok"},
+		{"regex no match", regex, "call 555-1234", ""},
+		{"links", links, "see https://evil.test/x and www.spam.test", "see [link removed] and [link removed]"},
+		{"links allowed", links, "docs at https://docs.example.com/a and (https://example.com)", ""},
+		{"links in markdown", links, "[click](https://evil.test)", "[click]([link removed])"},
+		{"caps", caps, "WHY IS NOBODY ANSWERING", "why is nobody answering"},
+		{"caps short", caps, "OK LGTM", ""},
+		{"caps mixed", caps, "Deploying API v2 to PROD now", ""},
+		{"repeated characters", repetition, "nooooooo!!!!!!", "nooo!!!"},
+		{"repeated digits", repetition, "costs 1000000", ""},
+		{"repeated words", repetition, "spam Spam spam spam spam eggs", "spam Spam spam eggs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := tt.filter.Check(tt.content)
+			if tt.want == "" {
+				assert.Nil(t, decision)
+				return
+			}
+			require.NotNil(t, decision)
+			assert.Equal(t, ActionRedact, decision.Action)
+			assert.Equal(t, tt.want, decision.Content)
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var config Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"blocklist": {"words": ["darn"], "action": "redact"},
+		"regex": [{"name": "invite spam", "pattern": "(?i)join my server", "action": "reject"}],
+		"links": {"action": "allow"}
+	}`), &config))
+
+	pipeline, err := New(&config)
+	require.NoError(t, err)
+	require.Len(t, pipeline, 3)
+
+	t.Run("allow", func(t *testing.T) {
+		result := pipeline.Run("hello there")
+		assert.Equal(t, ActionAllow, result.Action)
+		assert.Equal(t, "hello there", result.Content)
+		assert.Empty(t, result.Decisions)
+	})
+
+	t.Run("redact and record", func(t *testing.T) {
+		result := pipeline.Run("darn, see https://example.com")
+		assert.Equal(t, ActionRedact, result.Action)
+		assert.Equal(t, "****, see https://example.com", result.Content)
+		require.Len(t, result.Decisions, 2)
+		assert.Equal(t, "blocklist", result.Decisions[0].Filter)
+		assert.Equal(t, "links", result.Decisions[1].Filter)
+		assert.Equal(t, ActionAllow, result.Decisions[1].Action)
+		assert.Nil(t, result.Rejection())
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		result := pipeline.Run("darn, JOIN MY SERVER https://spam.test")
+		assert.Equal(t, ActionReject, result.Action)
+		require.Len(t, result.Decisions, 2)
+		require.NotNil(t, result.Rejection())
+		assert.Equal(t, "matches rule invite spam", result.Rejection().Reason)
+	})
+}
+
+func TestInvalidConfig(t *testing.T) {
+	configs := map[string]*Config{
+		"action":    {Blocklist: &BlocklistConfig{Words: []string{"x"}, Action: "delete"}},
+		"blocklist": {Blocklist: &BlocklistConfig{Words: []string{" "}, Action: ActionReject}},
+		"pattern":   {Regex: []*RegexConfig{{Pattern: "(", Action: ActionReject}}},
+		"caps":      {Caps: &CapsConfig{MaxRatio: 2, Action: ActionReject}},
+		"repeats":   {Repetition: &RepetitionConfig{MaxRepeats: -1, Action: ActionReject}},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, config.Validate())
+		})
+	}
+
+	assert.NoError(t, (*Config)(nil).Validate())
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/media"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/storage"
+)
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+	ErrEmptyAttachment    = errors.New("attachment is empty")
+)
+
+const defaultMaxAttachmentSize = 10 << 20
+
+// MaxAttachmentSize reads the upload limit in bytes from
+// MAX_ATTACHMENT_SIZE, falling back to 10MB when it is unset or invalid.
+func MaxAttachmentSize() int64 {
+	max, err := strconv.ParseInt(os.Getenv("MAX_ATTACHMENT_SIZE"), 10, 64)
+	if err != nil || max <= 0 {
+		return defaultMaxAttachmentSize
+	}
+	return max
+}
+
+// thumbnailSizes are the longest side, in pixels, of each thumbnail
+// generated for an image attachment.
+var thumbnailSizes = []struct {
+	label   string
+	maxSide int
+}{
+	{"small", 96},
+	{"medium", 320},
+	{"large", 800},
+}
+
+const (
+	processingBatchSize = 20
+	processingInterval  = time.Minute
+)
+
+type attachmentService struct {
+	conn                 *pgxpool.Pool
+	blobStore            storage.BlobStore
+	eventService         EventService
+	queue                chan string
+	AttachmentRepository AttachmentRepository
+}
+
+func NewAttachmentService(conn *pgxpool.Pool, blobStore storage.BlobStore, eventService EventService) AttachmentService {
+	return &attachmentService{
+		conn:                 conn,
+		blobStore:            blobStore,
+		eventService:         eventService,
+		queue:                make(chan string, 100),
+		AttachmentRepository: repositories.NewAttachmentRepository(conn),
+	}
+}
+
+// CreateAttachment stores the attachment body in the blob store and records
+// it against its message. The content type is sniffed from the body rather
+// than trusted from the client.
+func (s *attachmentService) CreateAttachment(attachment *models.Attachment, body io.Reader, tx pgx.Tx) error {
+	if attachment.Size <= 0 {
+		return ErrEmptyAttachment
+	}
+	if attachment.Size > MaxAttachmentSize() {
+		return ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	head = head[:n]
+
+	attachment.ContentType = http.DetectContentType(head)
+	attachment.StorageKey = fmt.Sprintf("rooms/%s/%s", attachment.RoomID, uuid.NewString())
+
+	ctx := context.Background()
+	err = s.blobStore.Put(ctx, attachment.StorageKey, io.MultiReader(bytes.NewReader(head), body), attachment.Size, attachment.ContentType)
+	if err != nil {
+		return err
+	}
+
+	err = s.AttachmentRepository.CreateAttachment(attachment, tx)
+	if err != nil {
+		s.blobStore.Delete(ctx, attachment.StorageKey)
+		return err
+	}
+
+	attachment.ProcessingStatus = models.AttachmentStatusPending
+	attachment.URL = attachmentURL(attachment)
+	return nil
+}
+
+// GetAttachment returns the attachment along with its thumbnails.
+func (s *attachmentService) GetAttachment(id string, tx pgx.Tx) (*models.Attachment, error) {
+	attachment, err := s.AttachmentRepository.GetAttachment(id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadThumbnails([]*models.Attachment{attachment}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.URL = attachmentURL(attachment)
+	return attachment, nil
+}
+
+func (s *attachmentService) OpenAttachment(attachment *models.Attachment) (io.ReadCloser, error) {
+	return s.blobStore.Get(context.Background(), attachment.StorageKey)
+}
+
+func (s *attachmentService) OpenThumbnail(thumbnail *models.AttachmentThumbnail) (io.ReadCloser, error) {
+	return s.blobStore.Get(context.Background(), thumbnail.StorageKey)
+}
+
+// EnqueueProcessing hands a newly uploaded attachment to the background
+// processor. It never blocks; attachments that do not fit in the queue are
+// picked up by the next sweep of pending attachments.
+func (s *attachmentService) EnqueueProcessing(id string) {
+	select {
+	case s.queue <- id:
+	default:
+	}
+}
+
+// ProcessAttachments processes queued attachments until ctx is cancelled.
+// Pending attachments left over from a previous run are swept up on start
+// and then periodically.
+func (s *attachmentService) ProcessAttachments(ctx context.Context) {
+	ticker := time.NewTicker(processingInterval)
+	defer ticker.Stop()
+
+	s.processPending(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.ProcessAttachment(id); err != nil {
+				log.Printf("failed to process attachment %s: %v", id, err)
+			}
+		case <-ticker.C:
+			s.processPending(ctx)
+		}
+	}
+}
+
+func (s *attachmentService) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		attachments, err := s.AttachmentRepository.GetPendingAttachments(processingBatchSize, nil)
+		if err != nil {
+			log.Printf("failed to fetch pending attachments: %v", err)
+			return
+		}
+
+		saved := 0
+		for _, attachment := range attachments {
+			if err := s.process(attachment); err != nil {
+				log.Printf("failed to process attachment %s: %v", attachment.ID, err)
+				continue
+			}
+			saved++
+		}
+
+		// attachments that could not be saved stay pending and would be
+		// fetched again straight away, so leave them for the next tick
+		if len(attachments) < processingBatchSize || saved < len(attachments) {
+			return
+		}
+	}
+}
+
+// ProcessAttachment extracts the dimensions and dominant colour of an image
+// attachment, strips location and other metadata from the stored original
+// and generates its thumbnails. Attachments that are not images are marked
+// as skipped.
+func (s *attachmentService) ProcessAttachment(id string) error {
+	attachment, err := s.AttachmentRepository.GetAttachment(id, nil)
+	if err != nil {
+		return err
+	}
+	if attachment.ProcessingStatus != models.AttachmentStatusPending {
+		return nil
+	}
+
+	return s.process(attachment)
+}
+
+func (s *attachmentService) process(attachment *models.Attachment) error {
+	err := s.processImage(attachment)
+	switch {
+	case errors.Is(err, media.ErrUnsupportedFormat):
+		attachment.ProcessingStatus = models.AttachmentStatusSkipped
+	case err != nil:
+		log.Printf("failed to process image %s: %v", attachment.ID, err)
+		attachment.ProcessingStatus = models.AttachmentStatusFailed
+	default:
+		attachment.ProcessingStatus = models.AttachmentStatusProcessed
+	}
+
+	err = s.AttachmentRepository.UpdateAttachmentMetadata(attachment, nil)
+	if err != nil {
+		return err
+	}
+
+	attachment.URL = attachmentURL(attachment)
+	for _, thumbnail := range attachment.Thumbnails {
+		thumbnail.URL = thumbnailURL(attachment, thumbnail)
+	}
+
+	s.eventService.Publish(&models.Event{
+		Type:   models.EventAttachmentProcessed,
+		RoomID: attachment.RoomID,
+		Data:   attachment,
+	})
+	return nil
+}
+
+func (s *attachmentService) processImage(attachment *models.Attachment) error {
+	if !strings.HasPrefix(attachment.ContentType, "image/") {
+		return media.ErrUnsupportedFormat
+	}
+
+	ctx := context.Background()
+	body, err := s.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	img, format, err := media.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	stripped, err := media.StripMetadata(data, format)
+	if err != nil {
+		return err
+	}
+	if len(stripped) != len(data) {
+		err = s.blobStore.Put(ctx, attachment.StorageKey, bytes.NewReader(stripped), int64(len(stripped)), attachment.ContentType)
+		if err != nil {
+			return err
+		}
+		attachment.Size = int64(len(stripped))
+	}
+
+	bounds := img.Bounds()
+	attachment.Width = bounds.Dx()
+	attachment.Height = bounds.Dy()
+	attachment.DominantColor = media.DominantColor(img)
+	attachment.Thumbnails = nil
+
+	for _, size := range thumbnailSizes {
+		if attachment.Width <= size.maxSide && attachment.Height <= size.maxSide {
+			break
+		}
+
+		thumb := media.Resize(img, size.maxSide)
+		encoded, contentType, err := media.Encode(thumb, format)
+		if err != nil {
+			return err
+		}
+
+		thumbnail := &models.AttachmentThumbnail{
+			AttachmentID: attachment.ID,
+			Label:        size.label,
+			Width:        thumb.Bounds().Dx(),
+			Height:       thumb.Bounds().Dy(),
+			ContentType:  contentType,
+			Size:         int64(len(encoded)),
+			StorageKey:   fmt.Sprintf("%s_thumb_%s", attachment.StorageKey, size.label),
+		}
+
+		err = s.blobStore.Put(ctx, thumbnail.StorageKey, bytes.NewReader(encoded), thumbnail.Size, contentType)
+		if err != nil {
+			return err
+		}
+
+		err = s.AttachmentRepository.UpsertAttachmentThumbnail(thumbnail, nil)
+		if err != nil {
+			return err
+		}
+		attachment.Thumbnails = append(attachment.Thumbnails, thumbnail)
+	}
+
+	return nil
+}
+
+// LoadMessageAttachments populates the Attachments of each message.
+func (s *attachmentService) LoadMessageAttachments(messages []*models.RoomMessage, tx pgx.Tx) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	messageIds := []string{}
+	byID := map[string]*models.RoomMessage{}
+	for _, message := range messages {
+		messageIds = append(messageIds, message.ID)
+		byID[message.ID] = message
+	}
+
+	attachments, err := s.AttachmentRepository.GetMessagesAttachments(messageIds, tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.loadThumbnails(attachments, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		attachment.URL = attachmentURL(attachment)
+		message := byID[attachment.RoomMessageID]
+		message.Attachments = append(message.Attachments, attachment)
+	}
+
+	return nil
+}
+
+// DeleteAttachmentBlobs removes the stored files of attachments whose rows
+// are gone, along with their thumbnails. Failures are logged and skipped so
+// that one failed delete does not keep the other blobs around.
+func (s *attachmentService) DeleteAttachmentBlobs(attachments []*models.Attachment) {
+	ctx := context.Background()
+	for _, attachment := range attachments {
+		keys := []string{attachment.StorageKey}
+		for _, thumbnail := range attachment.Thumbnails {
+			keys = append(keys, thumbnail.StorageKey)
+		}
+
+		for _, key := range keys {
+			err := s.blobStore.Delete(ctx, key)
+			if err != nil {
+				log.Printf("failed to delete blob %s: %v", key, err)
+			}
+		}
+	}
+}
+
+func (s *attachmentService) loadThumbnails(attachments []*models.Attachment, tx pgx.Tx) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	attachmentIds := []string{}
+	byID := map[string]*models.Attachment{}
+	for _, attachment := range attachments {
+		attachmentIds = append(attachmentIds, attachment.ID)
+		byID[attachment.ID] = attachment
+	}
+
+	thumbnails, err := s.AttachmentRepository.GetAttachmentsThumbnails(attachmentIds, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, thumbnail := range thumbnails {
+		attachment := byID[thumbnail.AttachmentID]
+		thumbnail.URL = thumbnailURL(attachment, thumbnail)
+		attachment.Thumbnails = append(attachment.Thumbnails, thumbnail)
+	}
+
+	return nil
+}
+
+func attachmentURL(attachment *models.Attachment) string {
+	return fmt.Sprintf("/api/v1/rooms/%s/attachments/%s", attachment.RoomID, attachment.ID)
+}
+
+func thumbnailURL(attachment *models.Attachment, thumbnail *models.AttachmentThumbnail) string {
+	return fmt.Sprintf("%s/thumbnails/%s", attachmentURL(attachment), thumbnail.Label)
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *models.Attachment, tx pgx.Tx) error
+	GetAttachment(id string, tx pgx.Tx) (*models.Attachment, error)
+	GetMessagesAttachments(messageIds []string, tx pgx.Tx) ([]*models.Attachment, error)
+	DeleteAttachment(id string, tx pgx.Tx) error
+	GetPendingAttachments(limit int, tx pgx.Tx) ([]*models.Attachment, error)
+	UpdateAttachmentMetadata(attachment *models.Attachment, tx pgx.Tx) error
+	UpsertAttachmentThumbnail(thumbnail *models.AttachmentThumbnail, tx pgx.Tx) error
+	GetAttachmentsThumbnails(attachmentIds []string, tx pgx.Tx) ([]*models.AttachmentThumbnail, error)
+}
+
+type AttachmentService interface {
+	CreateAttachment(attachment *models.Attachment, body io.Reader, tx pgx.Tx) error
+	GetAttachment(id string, tx pgx.Tx) (*models.Attachment, error)
+	OpenAttachment(attachment *models.Attachment) (io.ReadCloser, error)
+	OpenThumbnail(thumbnail *models.AttachmentThumbnail) (io.ReadCloser, error)
+	LoadMessageAttachments(messages []*models.RoomMessage, tx pgx.Tx) error
+	DeleteAttachmentBlobs(attachments []*models.Attachment)
+	EnqueueProcessing(id string)
+	ProcessAttachments(ctx context.Context)
+	ProcessAttachment(id string) error
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// stuckAttachmentRepository always has a full batch of pending attachments
+// and fails to save any of them.
+type stuckAttachmentRepository struct {
+	AttachmentRepository
+	fetches int
+}
+
+func (r *stuckAttachmentRepository) GetPendingAttachments(limit int, tx pgx.Tx) ([]*models.Attachment, error) {
+	r.fetches++
+	attachments := []*models.Attachment{}
+	for i := 0; i < limit; i++ {
+		attachments = append(attachments, &models.Attachment{
+			ID:               fmt.Sprint(i),
+			ContentType:      "text/plain",
+			ProcessingStatus: models.AttachmentStatusPending,
+		})
+	}
+	return attachments, nil
+}
+
+func (r *stuckAttachmentRepository) UpdateAttachmentMetadata(attachment *models.Attachment, tx pgx.Tx) error {
+	return errors.New("database unavailable")
+}
+
+func TestProcessPendingStopsWithoutProgress(t *testing.T) {
+	repository := &stuckAttachmentRepository{}
+	s := &attachmentService{eventService: NewEventService(), AttachmentRepository: repository}
+
+	s.processPending(context.Background())
+	assert.Equal(t, 1, repository.fetches)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrPermissionDenied = errors.New("you do not have permission to do this")
+	ErrInvalidRole      = errors.New("role must be one of admin, moderator or member")
+)
+
+// Permission is something a room member may be allowed to do.
+type Permission string
+
+const (
+	// PermissionReadRoom covers seeing the room, its members and its
+	// messages, and is held by every member.
+	PermissionReadRoom    Permission = "read_room"
+	PermissionSendMessage Permission = "send_message"
+	// PermissionUpdateRoom covers the room's details and its settings, such
+	// as webhooks, moderation and retention.
+	PermissionUpdateRoom     Permission = "update_room"
+	PermissionDeleteRoom     Permission = "delete_room"
+	PermissionManageRoles    Permission = "manage_roles"
+	PermissionKickMember     Permission = "kick_member"
+	PermissionBanMember      Permission = "ban_member"
+	PermissionMuteMember     Permission = "mute_member"
+	PermissionPinMessage     Permission = "pin_message"
+	PermissionDeleteMessages Permission = "delete_messages"
+	PermissionManageInvites  Permission = "manage_invites"
+	// PermissionManagePolls covers closing polls started by other members.
+	PermissionManagePolls Permission = "manage_polls"
+	// PermissionReviewJoinRequests is held by the approvers of restricted
+	// rooms.
+	PermissionReviewJoinRequests Permission = "review_join_requests"
+)
+
+// rolePermissions is the permission matrix. Members can always delete their
+// own messages; PermissionDeleteMessages is for other people's.
+var rolePermissions = map[string][]Permission{
+	models.RoomRoleOwner: {
+		PermissionReadRoom, PermissionSendMessage, PermissionUpdateRoom, PermissionDeleteRoom, PermissionManageRoles,
+		PermissionKickMember, PermissionBanMember, PermissionMuteMember, PermissionPinMessage,
+		PermissionDeleteMessages, PermissionManageInvites, PermissionManagePolls, PermissionReviewJoinRequests,
+	},
+	models.RoomRoleAdmin: {
+		PermissionReadRoom, PermissionSendMessage, PermissionUpdateRoom, PermissionManageRoles,
+		PermissionKickMember, PermissionBanMember, PermissionMuteMember, PermissionPinMessage,
+		PermissionDeleteMessages, PermissionManageInvites, PermissionManagePolls, PermissionReviewJoinRequests,
+	},
+	models.RoomRoleModerator: {
+		PermissionReadRoom, PermissionSendMessage, PermissionKickMember, PermissionBanMember, PermissionMuteMember,
+		PermissionPinMessage, PermissionDeleteMessages, PermissionManageInvites, PermissionManagePolls,
+	},
+	models.RoomRoleMember: {
+		PermissionReadRoom, PermissionSendMessage,
+	},
+}
+
+// roleRanks orders roles so members can only act on those below them.
+var roleRanks = map[string]int{
+	models.RoomRoleMember:    1,
+	models.RoomRoleModerator: 2,
+	models.RoomRoleAdmin:     3,
+	models.RoomRoleOwner:     4,
+}
+
+type authorizationService struct {
+	conn           *pgxpool.Pool
+	RoomRepository RoomRepository
+}
+
+func NewAuthorizationService(conn *pgxpool.Pool) AuthorizationService {
+	return &authorizationService{
+		conn:           conn,
+		RoomRepository: repositories.NewRoomRepository(conn),
+	}
+}
+
+// Can reports whether role holds permission.
+func (s *authorizationService) Can(role string, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// Authorize returns the room and the user's membership of it if they hold
+// permission there. It returns pgx.ErrNoRows if the room does not exist and
+// ErrPermissionDenied if the user is not a member or lacks the permission.
+func (s *authorizationService) Authorize(roomId, userId string, permission Permission, tx pgx.Tx) (*models.Room, *models.RoomMember, error) {
+	room, err := s.RoomRepository.GetRoom(roomId, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := s.RoomRepository.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: userId,
+		RoomID: roomId,
+	}, tx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrPermissionDenied
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !s.Can(member.Role, permission) {
+		return nil, nil, ErrPermissionDenied
+	}
+	return room, member, nil
+}
+
+// AuthorizeOver checks that actor holds permission and outranks target, so
+// moderators cannot act on admins and nobody can act on their equals.
+func (s *authorizationService) AuthorizeOver(actor, target *models.RoomMember, permission Permission) error {
+	if !s.Can(actor.Role, permission) || roleRanks[actor.Role] <= roleRanks[target.Role] {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// AuthorizeRoleChange checks that actor may give target role. The owner
+// role cannot be given, and nobody can grant a role at or above their own.
+func (s *authorizationService) AuthorizeRoleChange(actor, target *models.RoomMember, role string) error {
+	if _, ok := roleRanks[role]; !ok || role == models.RoomRoleOwner {
+		return ErrInvalidRole
+	}
+
+	err := s.AuthorizeOver(actor, target, PermissionManageRoles)
+	if err != nil {
+		return err
+	}
+	if roleRanks[actor.Role] <= roleRanks[role] {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+type AuthorizationService interface {
+	Can(role string, permission Permission) bool
+	Authorize(roomId, userId string, permission Permission, tx pgx.Tx) (*models.Room, *models.RoomMember, error)
+	AuthorizeOver(actor, target *models.RoomMember, permission Permission) error
+	AuthorizeRoleChange(actor, target *models.RoomMember, role string) error
+}
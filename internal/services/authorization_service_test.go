@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionMatrix(t *testing.T) {
+	s := &authorizationService{}
+
+	tests := []struct {
+		role    string
+		allowed []Permission
+		denied  []Permission
+	}{
+		{models.RoomRoleOwner, []Permission{PermissionDeleteRoom, PermissionUpdateRoom, PermissionManageRoles}, nil},
+		{models.RoomRoleAdmin, []Permission{PermissionUpdateRoom, PermissionKickMember, PermissionReviewJoinRequests}, []Permission{PermissionDeleteRoom}},
+		{models.RoomRoleModerator, []Permission{PermissionPinMessage, PermissionDeleteMessages, PermissionKickMember, PermissionBanMember, PermissionMuteMember, PermissionManageInvites, PermissionManagePolls}, []Permission{PermissionUpdateRoom, PermissionManageRoles, PermissionReviewJoinRequests}},
+		{models.RoomRoleMember, []Permission{PermissionReadRoom, PermissionSendMessage}, []Permission{PermissionPinMessage, PermissionKickMember, PermissionBanMember, PermissionMuteMember, PermissionManageInvites, PermissionManagePolls}},
+		{"", nil, []Permission{PermissionReadRoom, PermissionSendMessage}},
+	}
+
+	for _, tt := range tests {
+		for _, permission := range tt.allowed {
+			assert.True(t, s.Can(tt.role, permission), "%s should %s", tt.role, permission)
+		}
+		for _, permission := range tt.denied {
+			assert.False(t, s.Can(tt.role, permission), "%s should not %s", tt.role, permission)
+		}
+	}
+}
+
+func TestAuthorizeOver(t *testing.T) {
+	s := &authorizationService{}
+	owner := &models.RoomMember{Role: models.RoomRoleOwner}
+	admin := &models.RoomMember{Role: models.RoomRoleAdmin}
+	moderator := &models.RoomMember{Role: models.RoomRoleModerator}
+	member := &models.RoomMember{Role: models.RoomRoleMember}
+
+	assert.NoError(t, s.AuthorizeOver(moderator, member, PermissionKickMember))
+	assert.ErrorIs(t, s.AuthorizeOver(moderator, moderator, PermissionKickMember), ErrPermissionDenied)
+	assert.ErrorIs(t, s.AuthorizeOver(moderator, admin, PermissionKickMember), ErrPermissionDenied)
+	assert.ErrorIs(t, s.AuthorizeOver(member, member, PermissionKickMember), ErrPermissionDenied)
+
+	assert.NoError(t, s.AuthorizeRoleChange(owner, member, models.RoomRoleAdmin))
+	assert.NoError(t, s.AuthorizeRoleChange(admin, member, models.RoomRoleModerator))
+	assert.ErrorIs(t, s.AuthorizeRoleChange(admin, member, models.RoomRoleAdmin), ErrPermissionDenied)
+	assert.ErrorIs(t, s.AuthorizeRoleChange(admin, owner, models.RoomRoleMember), ErrPermissionDenied)
+	assert.ErrorIs(t, s.AuthorizeRoleChange(moderator, member, models.RoomRoleMember), ErrPermissionDenied)
+	assert.ErrorIs(t, s.AuthorizeRoleChange(owner, admin, models.RoomRoleOwner), ErrInvalidRole)
+	assert.ErrorIs(t, s.AuthorizeRoleChange(owner, admin, "superuser"), ErrInvalidRole)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrBlockSelf = errors.New("you cannot block yourself")
+	ErrBlocked   = errors.New("you cannot message this user")
+)
+
+type blockService struct {
+	conn                *pgxpool.Pool
+	UserBlockRepository UserBlockRepository
+}
+
+func NewBlockService(conn *pgxpool.Pool) BlockService {
+	return &blockService{
+		conn:                conn,
+		UserBlockRepository: repositories.NewUserBlockRepository(conn),
+	}
+}
+
+// BlockUser stops blockedId and blockerId from messaging each other
+// directly. Blocking someone already blocked does nothing.
+func (s *blockService) BlockUser(blockerId, blockedId string, tx pgx.Tx) error {
+	if blockerId == blockedId {
+		return ErrBlockSelf
+	}
+	return s.UserBlockRepository.CreateUserBlock(blockerId, blockedId, tx)
+}
+
+func (s *blockService) UnblockUser(blockerId, blockedId string, tx pgx.Tx) error {
+	return s.UserBlockRepository.DeleteUserBlock(blockerId, blockedId, tx)
+}
+
+func (s *blockService) GetBlockedUsers(blockerId string, tx pgx.Tx) ([]*models.User, error) {
+	return s.UserBlockRepository.GetBlockedUsers(blockerId, tx)
+}
+
+// UsersBlocked reports whether any of the users has blocked another one of
+// them. Blocks apply both ways.
+func (s *blockService) UsersBlocked(userIds []string, tx pgx.Tx) (bool, error) {
+	return s.UserBlockRepository.UsersBlocked(userIds, tx)
+}
+
+type UserBlockRepository interface {
+	CreateUserBlock(blockerId, blockedId string, tx pgx.Tx) error
+	DeleteUserBlock(blockerId, blockedId string, tx pgx.Tx) error
+	GetBlockedUsers(blockerId string, tx pgx.Tx) ([]*models.User, error)
+	UsersBlocked(userIds []string, tx pgx.Tx) (bool, error)
+}
+
+type BlockService interface {
+	BlockUser(blockerId, blockedId string, tx pgx.Tx) error
+	UnblockUser(blockerId, blockedId string, tx pgx.Tx) error
+	GetBlockedUsers(blockerId string, tx pgx.Tx) ([]*models.User, error)
+	UsersBlocked(userIds []string, tx pgx.Tx) (bool, error)
+}
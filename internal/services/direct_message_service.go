@@ -0,0 +1,315 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrDirectMessageSelf   = errors.New("you cannot message yourself")
+	ErrTooFewParticipants  = errors.New("group messages need at least two other participants")
+	ErrTooManyParticipants = errors.New("too many participants for a group message, create a room instead")
+	ErrNotGroupMessage     = errors.New("participants can only be added to group messages")
+	ErrConversationExists  = errors.New("a conversation with these participants already exists")
+	ErrNoParticipants      = errors.New("no participants to add")
+)
+
+const (
+	// maxGroupParticipants is the largest a group message gets. Adding
+	// participants past it converts the group into a room.
+	maxGroupParticipants = 9
+	// convertedRoomMaxMembers is the member limit given to rooms converted
+	// from group messages, unless they already have more participants.
+	convertedRoomMaxMembers = 100
+	maxRoomNameLength       = 255
+)
+
+type directMessageService struct {
+	conn                *pgxpool.Pool
+	RoomRepository      RoomRepository
+	UserRepository      UserRepository
+	UserBlockRepository UserBlockRepository
+}
+
+func NewDirectMessageService(conn *pgxpool.Pool) DirectMessageService {
+	return &directMessageService{
+		conn:                conn,
+		RoomRepository:      repositories.NewRoomRepository(conn),
+		UserRepository:      repositories.NewUserRepository(conn),
+		UserBlockRepository: repositories.NewUserBlockRepository(conn),
+	}
+}
+
+// participantSet returns userIds sorted and without duplicates.
+func participantSet(userIds []string) []string {
+	ids := slices.Clone(userIds)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// participantsKey identifies a conversation by the set of its participants,
+// independent of their order.
+func participantsKey(userIds []string) string {
+	return strings.Join(participantSet(userIds), ",")
+}
+
+// OpenDirectMessage returns the direct message between userId and otherId,
+// creating it the first time either of them opens it. The returned bool
+// reports whether it was created. Opening is rejected with ErrBlocked when
+// either user has blocked the other.
+func (s *directMessageService) OpenDirectMessage(userId, otherId string, tx pgx.Tx) (*models.DirectMessage, bool, error) {
+	if userId == otherId {
+		return nil, false, ErrDirectMessageSelf
+	}
+
+	if err := s.checkParticipants(userId, []string{otherId}, tx); err != nil {
+		return nil, false, err
+	}
+
+	return s.open(models.RoomKindDirect, userId, []string{userId, otherId}, tx)
+}
+
+// OpenGroupMessage returns the group message between userId and otherIds,
+// creating it the first time anyone opens a conversation with exactly these
+// participants. Groups too large for a group message must be rooms instead.
+func (s *directMessageService) OpenGroupMessage(userId string, otherIds []string, tx pgx.Tx) (*models.DirectMessage, bool, error) {
+	userIds := participantSet(append([]string{userId}, otherIds...))
+	if len(userIds) < 3 {
+		return nil, false, ErrTooFewParticipants
+	}
+	if len(userIds) > maxGroupParticipants {
+		return nil, false, ErrTooManyParticipants
+	}
+
+	others := slices.DeleteFunc(slices.Clone(userIds), func(id string) bool { return id == userId })
+	if err := s.checkParticipants(userId, others, tx); err != nil {
+		return nil, false, err
+	}
+
+	return s.open(models.RoomKindGroup, userId, userIds, tx)
+}
+
+// AddParticipants adds userIds to the group message roomId on behalf of
+// userId, who must already be in it. A group that grows past
+// maxGroupParticipants is converted into a private room named after its
+// participants and owned by userId; the returned bool reports whether that
+// happened.
+func (s *directMessageService) AddParticipants(roomId, userId string, userIds []string, tx pgx.Tx) (*models.DirectMessage, bool, error) {
+	room, err := s.RoomRepository.GetRoom(roomId, tx)
+	if err != nil {
+		return nil, false, err
+	}
+	if room.Kind != models.RoomKindGroup {
+		return nil, false, ErrNotGroupMessage
+	}
+
+	if len(userIds) == 0 {
+		return nil, false, ErrNoParticipants
+	}
+
+	if err := s.checkParticipants(userId, userIds, tx); err != nil {
+		return nil, false, err
+	}
+
+	// participants who left are still part of the conversation's key, so
+	// adding them back only restores their membership
+	participants := participantSet(slices.Concat(strings.Split(room.ParticipantsKey, ","), userIds))
+	converted := len(participants) > maxGroupParticipants
+	if key := strings.Join(participants, ","); !converted && key != room.ParticipantsKey {
+		_, err := s.RoomRepository.GetRoomByParticipantsKey(key, tx)
+		if err == nil {
+			return nil, false, ErrConversationExists
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, err
+		}
+		room.ParticipantsKey = key
+	}
+
+	if err := s.addMembers(room.ID, userIds, tx); err != nil {
+		return nil, false, err
+	}
+
+	users, err := s.RoomRepository.GetConversationParticipants([]string{room.ID}, tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if converted {
+		room.Kind = models.RoomKindRoom
+		room.ParticipantsKey = ""
+		room.Name = conversationName(users[room.ID])
+		room.MaxMembers = max(len(participants), convertedRoomMaxMembers)
+		// the conversation was only visible to its participants, so the
+		// room it becomes stays closed to everyone else
+		room.Visibility = models.RoomVisibilityPrivate
+	}
+
+	err = s.RoomRepository.UpdateConversation(room, tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if converted {
+		// conversations have no owner, the room goes to whoever grew it
+		member, err := s.RoomRepository.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: userId,
+			RoomID: room.ID,
+		}, tx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		member.Role = models.RoomRoleOwner
+		err = s.RoomRepository.UpdateRoomMemberRole(member, tx)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return &models.DirectMessage{Room: room, Participants: users[room.ID]}, converted, nil
+}
+
+// GetDirectMessages returns the direct and group messages userId is in, most
+// recently active first.
+func (s *directMessageService) GetDirectMessages(userId string, tx pgx.Tx) ([]*models.DirectMessage, error) {
+	rooms, err := s.RoomRepository.GetUserConversations(userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomIds := []string{}
+	for _, room := range rooms {
+		roomIds = append(roomIds, room.ID)
+	}
+
+	participants, err := s.RoomRepository.GetConversationParticipants(roomIds, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	dms := []*models.DirectMessage{}
+	for _, room := range rooms {
+		dms = append(dms, &models.DirectMessage{Room: room, Participants: participants[room.ID]})
+	}
+
+	return dms, nil
+}
+
+// checkParticipants checks that the users userId is bringing into a
+// conversation exist and that none of them blocked userId, or were blocked
+// by them.
+func (s *directMessageService) checkParticipants(userId string, otherIds []string, tx pgx.Tx) error {
+	for _, id := range otherIds {
+		if id == userId {
+			return ErrDirectMessageSelf
+		}
+
+		if _, err := s.UserRepository.GetUser(repositories.GetUserParams{ID: id}, tx); err != nil {
+			return err
+		}
+
+		blocked, err := s.UserBlockRepository.UsersBlocked([]string{userId, id}, tx)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrBlocked
+		}
+	}
+
+	return nil
+}
+
+// open returns the conversation of kind between userIds, creating it if
+// there is none yet.
+func (s *directMessageService) open(kind, userId string, userIds []string, tx pgx.Tx) (*models.DirectMessage, bool, error) {
+	room := &models.Room{
+		Kind:            kind,
+		MaxMembers:      maxGroupParticipants,
+		CreatedBy:       userId,
+		ParticipantsKey: participantsKey(userIds),
+	}
+	if kind == models.RoomKindDirect {
+		room.MaxMembers = len(userIds)
+	}
+
+	created := true
+	err := s.RoomRepository.CreateDirectRoom(room, tx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		created = false
+		room, err = s.RoomRepository.GetRoomByParticipantsKey(room.ParticipantsKey, tx)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// participants who left are added back, so anyone in the conversation
+	// can always reopen it
+	if err := s.addMembers(room.ID, userIds, tx); err != nil {
+		return nil, false, err
+	}
+
+	participants, err := s.RoomRepository.GetConversationParticipants([]string{room.ID}, tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &models.DirectMessage{Room: room, Participants: participants[room.ID]}, created, nil
+}
+
+// addMembers makes each of userIds a member of roomId unless they already
+// are.
+func (s *directMessageService) addMembers(roomId string, userIds []string, tx pgx.Tx) error {
+	for _, id := range userIds {
+		_, err := s.RoomRepository.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: id,
+			RoomID: roomId,
+		}, tx)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		err = s.RoomRepository.CreateRoomMember(&models.RoomMember{
+			RoomID: roomId,
+			UserID: id,
+			Role:   models.RoomRoleMember,
+		}, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conversationName names a room converted from a group message after the
+// people in it.
+func conversationName(users []*models.User) string {
+	names := []string{}
+	for _, user := range users {
+		names = append(names, user.FirstName)
+	}
+
+	name := []rune(strings.Join(names, ", "))
+	if len(name) > maxRoomNameLength {
+		return strings.TrimRight(string(name[:maxRoomNameLength-3]), ", ") + "..."
+	}
+	return string(name)
+}
+
+type DirectMessageService interface {
+	OpenDirectMessage(userId, otherId string, tx pgx.Tx) (*models.DirectMessage, bool, error)
+	OpenGroupMessage(userId string, otherIds []string, tx pgx.Tx) (*models.DirectMessage, bool, error)
+	AddParticipants(roomId, userId string, userIds []string, tx pgx.Tx) (*models.DirectMessage, bool, error)
+	GetDirectMessages(userId string, tx pgx.Tx) ([]*models.DirectMessage, error)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/princecee/go_chat/internal/models"
+)
+
+type EventHandler func(event *models.Event)
+
+type eventService struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+func NewEventService() EventService {
+	return &eventService{}
+}
+
+func (s *eventService) Subscribe(handler EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
+
+// Publish calls every handler with event. Handlers may publish events of
+// their own.
+func (s *eventService) Publish(event *models.Event) {
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+type EventService interface {
+	Subscribe(handler EventHandler)
+	Publish(event *models.Event)
+}
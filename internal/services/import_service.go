@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+type importService struct {
+	conn                    *pgxpool.Pool
+	ImportMappingRepository ImportMappingRepository
+}
+
+func NewImportService(conn *pgxpool.Pool) ImportService {
+	return &importService{
+		conn:                    conn,
+		ImportMappingRepository: repositories.NewImportMappingRepository(conn),
+	}
+}
+
+func (s *importService) GetImportMapping(source, kind, externalId string, tx pgx.Tx) (*models.ImportMapping, error) {
+	return s.ImportMappingRepository.GetImportMapping(source, kind, externalId, tx)
+}
+
+// SaveImportMapping records mapping, replacing the local ID of an existing
+// mapping of the same external object.
+func (s *importService) SaveImportMapping(mapping *models.ImportMapping, tx pgx.Tx) error {
+	return s.ImportMappingRepository.UpsertImportMapping(mapping, tx)
+}
+
+// DeleteImportMappings forgets the mappings of kind grouped under parentId,
+// so they are imported afresh.
+func (s *importService) DeleteImportMappings(source, kind, parentId string, tx pgx.Tx) error {
+	return s.ImportMappingRepository.DeleteImportMappingsByParent(source, kind, parentId, tx)
+}
+
+type ImportMappingRepository interface {
+	UpsertImportMapping(mapping *models.ImportMapping, tx pgx.Tx) error
+	GetImportMapping(source, kind, externalId string, tx pgx.Tx) (*models.ImportMapping, error)
+	DeleteImportMappingsByParent(source, kind, parentId string, tx pgx.Tx) error
+}
+
+type ImportService interface {
+	GetImportMapping(source, kind, externalId string, tx pgx.Tx) (*models.ImportMapping, error)
+	SaveImportMapping(mapping *models.ImportMapping, tx pgx.Tx) error
+	DeleteImportMappings(source, kind, parentId string, tx pgx.Tx) error
+}
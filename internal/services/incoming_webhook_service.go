@@ -0,0 +1,170 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrInvalidWebhookName = errors.New("webhook name must be between 1 and 50 characters")
+	ErrWebhookRoomFull    = errors.New("room is full: a webhook needs a free member slot for its integration user")
+)
+
+const maxWebhookNameLength = 50
+
+type incomingWebhookService struct {
+	conn                      *pgxpool.Pool
+	userService               UserService
+	roomService               RoomService
+	IncomingWebhookRepository IncomingWebhookRepository
+}
+
+func NewIncomingWebhookService(conn *pgxpool.Pool, userService UserService, roomService RoomService) IncomingWebhookService {
+	return &incomingWebhookService{
+		conn:                      conn,
+		userService:               userService,
+		roomService:               roomService,
+		IncomingWebhookRepository: repositories.NewIncomingWebhookRepository(conn),
+	}
+}
+
+// CreateIncomingWebhook creates a webhook for a room along with the
+// integration user it posts as, and adds that user to the room. The
+// integration user counts towards the room's max members like any other
+// member, so a full room is refused with ErrWebhookRoomFull. The
+// generated token is set on webhook and cannot be recovered later.
+func (s *incomingWebhookService) CreateIncomingWebhook(webhook *models.IncomingWebhook, tx pgx.Tx) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" || len([]rune(webhook.Name)) > maxWebhookNameLength {
+		return ErrInvalidWebhookName
+	}
+
+	token, err := newWebhookToken()
+	if err != nil {
+		return err
+	}
+
+	// integration users have no auth, so nobody can sign in as them; the
+	// address is unique and on a reserved domain so it never matches a
+	// real sign-up
+	user := &models.User{
+		FirstName: webhook.Name,
+		Email:     fmt.Sprintf("webhook-%s@integrations.invalid", uuid.NewString()),
+	}
+	err = s.userService.CreateUser(user, tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.roomService.AdmitMember(&models.RoomMember{
+		RoomID: webhook.RoomID,
+		UserID: user.ID,
+	}, tx)
+	if errors.Is(err, ErrMaxMembersReached) {
+		return ErrWebhookRoomFull
+	}
+	if err != nil {
+		return err
+	}
+
+	webhook.UserID = user.ID
+	err = s.IncomingWebhookRepository.CreateIncomingWebhook(webhook, hashWebhookToken(token), tx)
+	if err != nil {
+		return err
+	}
+
+	webhook.Token = token
+	return nil
+}
+
+func (s *incomingWebhookService) GetIncomingWebhook(id string, tx pgx.Tx) (*models.IncomingWebhook, error) {
+	return s.IncomingWebhookRepository.GetIncomingWebhook(id, tx)
+}
+
+func (s *incomingWebhookService) GetIncomingWebhooks(roomId string, tx pgx.Tx) ([]*models.IncomingWebhook, error) {
+	return s.IncomingWebhookRepository.GetIncomingWebhooks(roomId, tx)
+}
+
+// DeleteIncomingWebhook revokes a webhook's token. The integration user
+// stays in the room so the messages it posted keep their author.
+func (s *incomingWebhookService) DeleteIncomingWebhook(id string, tx pgx.Tx) error {
+	return s.IncomingWebhookRepository.DeleteIncomingWebhook(id, tx)
+}
+
+// PostMessage creates a message in the room of the webhook identified by
+// token, authored by its integration user. It returns pgx.ErrNoRows when
+// the token is unknown or the integration was removed from the room.
+func (s *incomingWebhookService) PostMessage(token, content string, tx pgx.Tx) (*models.RoomMessage, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	webhook, err := s.IncomingWebhookRepository.GetIncomingWebhookByTokenHash(hashWebhookToken(token), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: webhook.UserID,
+		RoomID: webhook.RoomID,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.RoomMessage{
+		RoomID:       webhook.RoomID,
+		UserID:       webhook.UserID,
+		RoomMemberID: member.ID,
+		Content:      content,
+	}
+	err = s.roomService.CreateMessage(message, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func newWebhookToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashWebhookToken returns the form tokens are stored and looked up in.
+// Tokens are random, so a fast unsalted hash is enough to keep them out of
+// the database.
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type IncomingWebhookRepository interface {
+	CreateIncomingWebhook(webhook *models.IncomingWebhook, tokenHash string, tx pgx.Tx) error
+	GetIncomingWebhook(id string, tx pgx.Tx) (*models.IncomingWebhook, error)
+	GetIncomingWebhookByTokenHash(tokenHash string, tx pgx.Tx) (*models.IncomingWebhook, error)
+	GetIncomingWebhooks(roomId string, tx pgx.Tx) ([]*models.IncomingWebhook, error)
+	DeleteIncomingWebhook(id string, tx pgx.Tx) error
+}
+
+type IncomingWebhookService interface {
+	CreateIncomingWebhook(webhook *models.IncomingWebhook, tx pgx.Tx) error
+	GetIncomingWebhook(id string, tx pgx.Tx) (*models.IncomingWebhook, error)
+	GetIncomingWebhooks(roomId string, tx pgx.Tx) ([]*models.IncomingWebhook, error)
+	DeleteIncomingWebhook(id string, tx pgx.Tx) error
+	PostMessage(token, content string, tx pgx.Tx) (*models.RoomMessage, error)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrReasonRequired          = errors.New("a reason is required")
+	ErrReasonTooLong           = errors.New("reason must be at most 500 characters")
+	ErrModerationExpiresInPast = errors.New("expiry must be in the future")
+	ErrBanned                  = errors.New("you are banned from this room")
+	ErrMuted                   = errors.New("you are muted in this room")
+)
+
+const maxMemberActionReasonLength = 500
+
+type memberModerationService struct {
+	conn                       *pgxpool.Pool
+	roomService                RoomService
+	MemberModerationRepository MemberModerationRepository
+}
+
+func NewMemberModerationService(conn *pgxpool.Pool, roomService RoomService) MemberModerationService {
+	return &memberModerationService{
+		conn:                       conn,
+		roomService:                roomService,
+		MemberModerationRepository: repositories.NewMemberModerationRepository(conn),
+	}
+}
+
+// KickMember removes member from their room on actorId's behalf. Unlike a
+// ban, nothing stops them from joining again.
+func (s *memberModerationService) KickMember(member *models.RoomMember, actorId, reason string, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	reason, err := memberActionReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.roomService.LeaveRoom(member.ID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.recordAction(&models.RoomMemberAction{
+		RoomID:  member.RoomID,
+		UserID:  member.UserID,
+		ActorID: actorId,
+		Action:  models.MemberActionKick,
+		Reason:  reason,
+	}, tx)
+}
+
+// BanUser keeps the banned user out of the room, removing member if they
+// are in it. Banning someone again replaces their ban's reason and expiry.
+func (s *memberModerationService) BanUser(ban *models.RoomBan, member *models.RoomMember, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	reason, err := memberActionReason(ban.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if ban.ExpiresAt != nil && !ban.ExpiresAt.After(time.Now()) {
+		return nil, ErrModerationExpiresInPast
+	}
+	ban.Reason = reason
+
+	room, err := s.roomService.GetRoom(ban.RoomID, tx)
+	if err != nil {
+		return nil, err
+	}
+	if room.Kind != models.RoomKindRoom {
+		return nil, ErrDirectMessageRoom
+	}
+
+	err = s.MemberModerationRepository.UpsertRoomBan(ban, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if member != nil {
+		err = s.roomService.LeaveRoom(member.ID, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.recordAction(&models.RoomMemberAction{
+		RoomID:    ban.RoomID,
+		UserID:    ban.UserID,
+		ActorID:   ban.CreatedBy,
+		Action:    models.MemberActionBan,
+		Reason:    ban.Reason,
+		ExpiresAt: ban.ExpiresAt,
+	}, tx)
+}
+
+// UnbanUser lifts userId's ban from the room. It returns pgx.ErrNoRows if
+// they were not banned.
+func (s *memberModerationService) UnbanUser(roomId, userId, actorId string, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	ok, err := s.MemberModerationRepository.DeleteRoomBan(roomId, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+
+	return s.recordAction(&models.RoomMemberAction{
+		RoomID:  roomId,
+		UserID:  userId,
+		ActorID: actorId,
+		Action:  models.MemberActionUnban,
+	}, tx)
+}
+
+// MuteMember stops the muted user from posting to the room while leaving
+// them in it. Muting someone again replaces their mute's reason and expiry.
+func (s *memberModerationService) MuteMember(mute *models.RoomMute, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	reason, err := memberActionReason(mute.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if mute.ExpiresAt != nil && !mute.ExpiresAt.After(time.Now()) {
+		return nil, ErrModerationExpiresInPast
+	}
+	mute.Reason = reason
+
+	err = s.MemberModerationRepository.UpsertRoomMute(mute, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.recordAction(&models.RoomMemberAction{
+		RoomID:    mute.RoomID,
+		UserID:    mute.UserID,
+		ActorID:   mute.CreatedBy,
+		Action:    models.MemberActionMute,
+		Reason:    mute.Reason,
+		ExpiresAt: mute.ExpiresAt,
+	}, tx)
+}
+
+// UnmuteMember lifts userId's mute in the room. It returns pgx.ErrNoRows if
+// they were not muted.
+func (s *memberModerationService) UnmuteMember(roomId, userId, actorId string, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	ok, err := s.MemberModerationRepository.DeleteRoomMute(roomId, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+
+	return s.recordAction(&models.RoomMemberAction{
+		RoomID:  roomId,
+		UserID:  userId,
+		ActorID: actorId,
+		Action:  models.MemberActionUnmute,
+	}, tx)
+}
+
+// GetBans returns the room's bans that have not expired, newest first.
+func (s *memberModerationService) GetBans(roomId string, tx pgx.Tx) ([]*models.RoomBan, error) {
+	return s.MemberModerationRepository.GetActiveRoomBans(roomId, tx)
+}
+
+// GetMutes returns the room's mutes that have not expired, newest first.
+func (s *memberModerationService) GetMutes(roomId string, tx pgx.Tx) ([]*models.RoomMute, error) {
+	return s.MemberModerationRepository.GetActiveRoomMutes(roomId, tx)
+}
+
+// GetMemberActions returns the room's most recent kicks, bans and mutes,
+// newest first.
+func (s *memberModerationService) GetMemberActions(roomId string, limit int, tx pgx.Tx) ([]*models.RoomMemberAction, error) {
+	return s.MemberModerationRepository.GetRoomMemberActions(roomId, limit, tx)
+}
+
+func (s *memberModerationService) recordAction(action *models.RoomMemberAction, tx pgx.Tx) (*models.RoomMemberAction, error) {
+	err := s.MemberModerationRepository.CreateRoomMemberAction(action, tx)
+	if err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+func memberActionReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", ErrReasonRequired
+	}
+	if len([]rune(reason)) > maxMemberActionReasonLength {
+		return "", ErrReasonTooLong
+	}
+	return reason, nil
+}
+
+type MemberModerationRepository interface {
+	UpsertRoomBan(ban *models.RoomBan, tx pgx.Tx) error
+	GetActiveRoomBan(roomId, userId string, tx pgx.Tx) (*models.RoomBan, error)
+	GetActiveRoomBans(roomId string, tx pgx.Tx) ([]*models.RoomBan, error)
+	DeleteRoomBan(roomId, userId string, tx pgx.Tx) (bool, error)
+	UpsertRoomMute(mute *models.RoomMute, tx pgx.Tx) error
+	GetActiveRoomMute(roomId, userId string, tx pgx.Tx) (*models.RoomMute, error)
+	GetActiveRoomMutes(roomId string, tx pgx.Tx) ([]*models.RoomMute, error)
+	DeleteRoomMute(roomId, userId string, tx pgx.Tx) (bool, error)
+	CreateRoomMemberAction(action *models.RoomMemberAction, tx pgx.Tx) error
+	GetRoomMemberActions(roomId string, limit int, tx pgx.Tx) ([]*models.RoomMemberAction, error)
+}
+
+type MemberModerationService interface {
+	KickMember(member *models.RoomMember, actorId, reason string, tx pgx.Tx) (*models.RoomMemberAction, error)
+	BanUser(ban *models.RoomBan, member *models.RoomMember, tx pgx.Tx) (*models.RoomMemberAction, error)
+	UnbanUser(roomId, userId, actorId string, tx pgx.Tx) (*models.RoomMemberAction, error)
+	MuteMember(mute *models.RoomMute, tx pgx.Tx) (*models.RoomMemberAction, error)
+	UnmuteMember(roomId, userId, actorId string, tx pgx.Tx) (*models.RoomMemberAction, error)
+	GetBans(roomId string, tx pgx.Tx) ([]*models.RoomBan, error)
+	GetMutes(roomId string, tx pgx.Tx) ([]*models.RoomMute, error)
+	GetMemberActions(roomId string, limit int, tx pgx.Tx) ([]*models.RoomMemberAction, error)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+type messageDraftService struct {
+	conn                   *pgxpool.Pool
+	eventService           EventService
+	MessageDraftRepository MessageDraftRepository
+}
+
+// NewMessageDraftService returns a service that publishes draft updates on
+// eventService.
+func NewMessageDraftService(conn *pgxpool.Pool, eventService EventService) MessageDraftService {
+	return &messageDraftService{
+		conn:                   conn,
+		eventService:           eventService,
+		MessageDraftRepository: repositories.NewMessageDraftRepository(conn),
+	}
+}
+
+func (s *messageDraftService) GetDraft(userId, roomId string, tx pgx.Tx) (*models.MessageDraft, error) {
+	return s.MessageDraftRepository.GetMessageDraft(userId, roomId, tx)
+}
+
+// SaveDraft stores draft as the user's only draft in the room. Saving a
+// blank draft clears it instead.
+func (s *messageDraftService) SaveDraft(draft *models.MessageDraft, tx pgx.Tx) error {
+	if strings.TrimSpace(draft.Content) == "" {
+		draft.Content = ""
+		_, err := s.MessageDraftRepository.DeleteMessageDraft(draft.UserID, draft.RoomID, tx)
+		return err
+	}
+
+	return s.MessageDraftRepository.SaveMessageDraft(draft, tx)
+}
+
+// ClearDraft removes the user's draft in the room and reports whether they
+// had one.
+func (s *messageDraftService) ClearDraft(userId, roomId string, tx pgx.Tx) (bool, error) {
+	return s.MessageDraftRepository.DeleteMessageDraft(userId, roomId, tx)
+}
+
+// ClearSentDraft drops the author's draft in the room of a message they
+// just sent and tells their other sessions to empty their composers. The
+// session that sent the message is not told. It is only called for messages
+// users write themselves, so scheduled messages and integrations leave
+// drafts alone.
+func (s *messageDraftService) ClearSentDraft(message *models.RoomMessage, session string) {
+	cleared, err := s.ClearDraft(message.UserID, message.RoomID, nil)
+	if err != nil {
+		log.Printf("failed to clear draft of user %s in room %s: %v", message.UserID, message.RoomID, err)
+		return
+	}
+	if !cleared {
+		return
+	}
+
+	s.eventService.Publish(&models.Event{
+		Type:        models.EventDraftUpdated,
+		RoomID:      message.RoomID,
+		UserID:      message.UserID,
+		SkipSession: session,
+		Data:        &models.MessageDraft{UserID: message.UserID, RoomID: message.RoomID},
+	})
+}
+
+type MessageDraftRepository interface {
+	GetMessageDraft(userId, roomId string, tx pgx.Tx) (*models.MessageDraft, error)
+	SaveMessageDraft(draft *models.MessageDraft, tx pgx.Tx) error
+	DeleteMessageDraft(userId, roomId string, tx pgx.Tx) (bool, error)
+}
+
+type MessageDraftService interface {
+	GetDraft(userId, roomId string, tx pgx.Tx) (*models.MessageDraft, error)
+	SaveDraft(draft *models.MessageDraft, tx pgx.Tx) error
+	ClearDraft(userId, roomId string, tx pgx.Tx) (bool, error)
+	ClearSentDraft(message *models.RoomMessage, session string)
+}
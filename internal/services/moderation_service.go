@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/moderation"
+)
+
+var (
+	ErrMessageRejected         = errors.New("message rejected")
+	ErrInvalidModerationConfig = errors.New("invalid moderation config")
+)
+
+type moderationService struct {
+	conn                 *pgxpool.Pool
+	ModerationRepository ModerationRepository
+}
+
+func NewModerationService(conn *pgxpool.Pool) ModerationService {
+	return &moderationService{
+		conn:                 conn,
+		ModerationRepository: repositories.NewModerationRepository(conn),
+	}
+}
+
+// GetRoomModeration returns a room's moderation settings, which are empty
+// for rooms that never saved any.
+func (s *moderationService) GetRoomModeration(roomId string, tx pgx.Tx) (*models.RoomModeration, error) {
+	settings, err := s.ModerationRepository.GetRoomModeration(roomId, tx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.RoomModeration{RoomID: roomId, Config: &moderation.Config{}}, nil
+	}
+	return settings, err
+}
+
+func (s *moderationService) SaveRoomModeration(settings *models.RoomModeration, tx pgx.Tx) error {
+	if settings.Config == nil {
+		settings.Config = &moderation.Config{}
+	}
+	if err := settings.Config.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidModerationConfig, err)
+	}
+	return s.ModerationRepository.SaveRoomModeration(settings, tx)
+}
+
+// Moderate runs message through its room's moderation pipeline. The result
+// holds the content to store and the decisions to record against the stored
+// message with RecordDecisions.
+//
+// Rejected messages return an error wrapping ErrMessageRejected. Their
+// decisions are recorded straight away and outside tx, so the audit trail
+// survives callers rolling back.
+func (s *moderationService) Moderate(message *models.RoomMessage, tx pgx.Tx) (*moderation.Result, error) {
+	settings, err := s.GetRoomModeration(message.RoomID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := moderation.New(settings.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	result := pipeline.Run(message.Content)
+	if rejection := result.Rejection(); rejection != nil {
+		err := s.RecordDecisions(message, message.Content, result.Decisions, nil)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrMessageRejected, rejection.Reason)
+	}
+
+	return result, nil
+}
+
+// RecordDecisions adds decisions made on message to its room's audit log.
+// content is the message as it was sent.
+func (s *moderationService) RecordDecisions(message *models.RoomMessage, content string, decisions []*moderation.Decision, tx pgx.Tx) error {
+	var messageId *string
+	if message.ID != "" {
+		messageId = &message.ID
+	}
+
+	for _, decision := range decisions {
+		err := s.ModerationRepository.CreateModerationAuditEntry(&models.ModerationAuditEntry{
+			RoomID:    message.RoomID,
+			UserID:    message.UserID,
+			MessageID: messageId,
+			Filter:    decision.Filter,
+			Action:    string(decision.Action),
+			Reason:    decision.Reason,
+			Content:   content,
+		}, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *moderationService) GetAuditLog(roomId string, limit int, tx pgx.Tx) ([]*models.ModerationAuditEntry, error) {
+	return s.ModerationRepository.GetModerationAuditEntries(roomId, limit, tx)
+}
+
+type ModerationRepository interface {
+	GetRoomModeration(roomId string, tx pgx.Tx) (*models.RoomModeration, error)
+	SaveRoomModeration(settings *models.RoomModeration, tx pgx.Tx) error
+	CreateModerationAuditEntry(entry *models.ModerationAuditEntry, tx pgx.Tx) error
+	GetModerationAuditEntries(roomId string, limit int, tx pgx.Tx) ([]*models.ModerationAuditEntry, error)
+}
+
+type ModerationService interface {
+	GetRoomModeration(roomId string, tx pgx.Tx) (*models.RoomModeration, error)
+	SaveRoomModeration(settings *models.RoomModeration, tx pgx.Tx) error
+	Moderate(message *models.RoomMessage, tx pgx.Tx) (*moderation.Result, error)
+	RecordDecisions(message *models.RoomMessage, content string, decisions []*moderation.Decision, tx pgx.Tx) error
+	GetAuditLog(roomId string, limit int, tx pgx.Tx) ([]*models.ModerationAuditEntry, error)
+}
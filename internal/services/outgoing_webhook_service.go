@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/webhooks"
+)
+
+var (
+	ErrInvalidWebhookURL        = errors.New("webhook url must be an absolute http or https url")
+	ErrInvalidWebhookEventTypes = errors.New("webhook must subscribe to at least one supported event type")
+)
+
+// WebhookEventTypes are the room events outgoing webhooks can subscribe to.
+var WebhookEventTypes = []string{
+	models.EventMessageCreated,
+	models.EventMessagePinned,
+	models.EventMessageUnpinned,
+	models.EventMessageExpired,
+	models.EventPollUpdated,
+	models.EventRoomUpdated,
+	models.EventMemberJoined,
+	models.EventMemberLeft,
+}
+
+const (
+	maxWebhookDeliveryAttempts = 8
+	webhookDeliveryBatchSize   = 20
+	webhookDeliveryInterval    = 5 * time.Second
+	webhookDeliveryLease       = time.Minute
+	webhookQueueSize           = 256
+	maxWebhookErrorLength      = 500
+)
+
+// webhookEvent is an event serialized when it is published, so later
+// changes to its data by the publisher cannot race with delivery.
+type webhookEvent struct {
+	eventType string
+	roomID    string
+	payload   []byte
+}
+
+type outgoingWebhookService struct {
+	conn                      *pgxpool.Pool
+	client                    webhooks.Doer
+	queue                     chan *webhookEvent
+	OutgoingWebhookRepository OutgoingWebhookRepository
+}
+
+// NewOutgoingWebhookService returns a service that records a delivery for
+// every room event published on eventService that a webhook subscribes to.
+// Deliveries are sent with client.
+func NewOutgoingWebhookService(conn *pgxpool.Pool, client webhooks.Doer, eventService EventService) OutgoingWebhookService {
+	s := &outgoingWebhookService{
+		conn:                      conn,
+		client:                    client,
+		queue:                     make(chan *webhookEvent, webhookQueueSize),
+		OutgoingWebhookRepository: repositories.NewOutgoingWebhookRepository(conn),
+	}
+	eventService.Subscribe(s.EnqueueEvent)
+	return s
+}
+
+// CreateOutgoingWebhook validates and stores a webhook with a freshly
+// generated signing secret, which is set on webhook.
+func (s *outgoingWebhookService) CreateOutgoingWebhook(webhook *models.OutgoingWebhook, tx pgx.Tx) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	eventTypes := []string{}
+	for _, eventType := range webhook.EventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return ErrInvalidWebhookEventTypes
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return ErrInvalidWebhookEventTypes
+	}
+	webhook.EventTypes = eventTypes
+
+	webhook.Secret, err = newWebhookToken()
+	if err != nil {
+		return err
+	}
+
+	return s.OutgoingWebhookRepository.CreateOutgoingWebhook(webhook, tx)
+}
+
+// GetOutgoingWebhook returns a webhook without its secret.
+func (s *outgoingWebhookService) GetOutgoingWebhook(id string, tx pgx.Tx) (*models.OutgoingWebhook, error) {
+	webhook, err := s.OutgoingWebhookRepository.GetOutgoingWebhook(id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// GetOutgoingWebhooks returns the webhooks of a room without their secrets.
+func (s *outgoingWebhookService) GetOutgoingWebhooks(roomId string, tx pgx.Tx) ([]*models.OutgoingWebhook, error) {
+	hooks, err := s.OutgoingWebhookRepository.GetOutgoingWebhooks(roomId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range hooks {
+		webhook.Secret = ""
+	}
+	return hooks, nil
+}
+
+func (s *outgoingWebhookService) DeleteOutgoingWebhook(id string, tx pgx.Tx) error {
+	return s.OutgoingWebhookRepository.DeleteOutgoingWebhook(id, tx)
+}
+
+func (s *outgoingWebhookService) GetWebhookDeliveries(webhookId string, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error) {
+	return s.OutgoingWebhookRepository.GetWebhookDeliveries(webhookId, limit, tx)
+}
+
+// EnqueueEvent queues a room event to be recorded for the webhooks
+// subscribed to it. Events addressed to a single user are private and never
+// leave the server. If the queue is full the event is dropped rather than
+// blocking the publisher.
+func (s *outgoingWebhookService) EnqueueEvent(event *models.Event) {
+	if event.UserID != "" || event.RoomID == "" || !slices.Contains(WebhookEventTypes, event.Type) {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to encode %s event for webhooks: %v", event.Type, err)
+		return
+	}
+
+	select {
+	case s.queue <- &webhookEvent{eventType: event.Type, roomID: event.RoomID, payload: payload}:
+	default:
+		log.Printf("webhook queue full, dropping %s event for room %s", event.Type, event.RoomID)
+	}
+}
+
+// DeliverWebhooks records queued events and sends due deliveries until ctx
+// is cancelled.
+func (s *outgoingWebhookService) DeliverWebhooks(ctx context.Context) {
+	ticker := time.NewTicker(webhookDeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.queue:
+			if err := s.recordEvent(event); err != nil {
+				log.Printf("failed to record %s event for webhooks: %v", event.eventType, err)
+			}
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			n, err := s.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("failed to deliver webhooks: %v", err)
+				break
+			}
+			if n < webhookDeliveryBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// recordEvent adds a pending delivery of event to each webhook of its room
+// subscribed to its type.
+func (s *outgoingWebhookService) recordEvent(event *webhookEvent) error {
+	hooks, err := s.OutgoingWebhookRepository.GetRoomEventOutgoingWebhooks(event.roomID, event.eventType, nil)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	tx, err := s.conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	for _, webhook := range hooks {
+		err = s.OutgoingWebhookRepository.CreateWebhookDelivery(&models.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventType: event.eventType,
+			Payload:   event.payload,
+		}, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// DeliverDue sends one batch of due deliveries and returns how many it
+// attempted. Failed deliveries are retried with exponential backoff until
+// they have been attempted maxWebhookDeliveryAttempts times.
+func (s *outgoingWebhookService) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.OutgoingWebhookRepository.ClaimDueWebhookDeliveries(now, now.Add(webhookDeliveryLease), webhookDeliveryBatchSize, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooksById := map[string]*models.OutgoingWebhook{}
+	for _, delivery := range deliveries {
+		webhook, ok := webhooksById[delivery.WebhookID]
+		if !ok {
+			webhook, err = s.OutgoingWebhookRepository.GetOutgoingWebhook(delivery.WebhookID, nil)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// deleted since the delivery was claimed, which also
+				// deleted the delivery
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			webhooksById[webhook.ID] = webhook
+		}
+
+		statusCode, sendErr := webhooks.Send(ctx, s.client, &webhooks.Delivery{
+			ID:        delivery.ID,
+			EventType: delivery.EventType,
+			URL:       webhook.URL,
+			Secret:    webhook.Secret,
+			Payload:   delivery.Payload,
+		}, time.Now())
+
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		switch {
+		case sendErr == nil:
+			deliveredAt := time.Now()
+			delivery.Status = models.WebhookDeliveryStatusSucceeded
+			delivery.DeliveredAt = &deliveredAt
+			delivery.LastError = ""
+		case delivery.Attempts >= maxWebhookDeliveryAttempts:
+			delivery.Status = models.WebhookDeliveryStatusFailed
+			delivery.LastError = truncateError(sendErr)
+		default:
+			delivery.NextAttemptAt = time.Now().Add(webhooks.Backoff(delivery.Attempts))
+			delivery.LastError = truncateError(sendErr)
+		}
+
+		err = s.OutgoingWebhookRepository.UpdateWebhookDelivery(delivery, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func truncateError(err error) string {
+	msg := err.Error()
+	if len(msg) > maxWebhookErrorLength {
+		msg = msg[:maxWebhookErrorLength]
+	}
+	return msg
+}
+
+type OutgoingWebhookRepository interface {
+	CreateOutgoingWebhook(webhook *models.OutgoingWebhook, tx pgx.Tx) error
+	GetOutgoingWebhook(id string, tx pgx.Tx) (*models.OutgoingWebhook, error)
+	GetOutgoingWebhooks(roomId string, tx pgx.Tx) ([]*models.OutgoingWebhook, error)
+	GetRoomEventOutgoingWebhooks(roomId, eventType string, tx pgx.Tx) ([]*models.OutgoingWebhook, error)
+	DeleteOutgoingWebhook(id string, tx pgx.Tx) error
+	CreateWebhookDelivery(delivery *models.WebhookDelivery, tx pgx.Tx) error
+	ClaimDueWebhookDeliveries(dueBefore, leaseUntil time.Time, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *models.WebhookDelivery, tx pgx.Tx) error
+	GetWebhookDeliveries(webhookId string, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error)
+}
+
+type OutgoingWebhookService interface {
+	CreateOutgoingWebhook(webhook *models.OutgoingWebhook, tx pgx.Tx) error
+	GetOutgoingWebhook(id string, tx pgx.Tx) (*models.OutgoingWebhook, error)
+	GetOutgoingWebhooks(roomId string, tx pgx.Tx) ([]*models.OutgoingWebhook, error)
+	DeleteOutgoingWebhook(id string, tx pgx.Tx) error
+	GetWebhookDeliveries(webhookId string, limit int, tx pgx.Tx) ([]*models.WebhookDelivery, error)
+	EnqueueEvent(event *models.Event)
+	DeliverWebhooks(ctx context.Context)
+	DeliverDue(ctx context.Context) (int, error)
+}
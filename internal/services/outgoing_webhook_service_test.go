@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/webhooks"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutgoingWebhookServiceTestSuite struct {
+	suite.Suite
+	conn         *pgxpool.Pool
+	userService  UserService
+	roomService  RoomService
+	eventService EventService
+}
+
+func (s *OutgoingWebhookServiceTestSuite) SetupSuite() {
+	if err := godotenv.Load("../../.env"); err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(context.Background(), os.Getenv("DSN"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s.conn = conn
+	s.userService = NewUserService(conn)
+	s.roomService = NewRoomService(conn, NewModerationService(conn))
+	s.eventService = NewEventService()
+}
+
+func (s *OutgoingWebhookServiceTestSuite) TearDownSuite() {
+	defer s.conn.Close()
+
+	teardownQuery := `
+		DELETE FROM outgoing_webhook_deliveries;
+		DELETE FROM outgoing_webhooks;
+		DELETE FROM room_members;
+		DELETE FROM rooms;
+		DELETE FROM users;
+	`
+
+	_, err := s.conn.Exec(context.Background(), teardownQuery)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			panic(err)
+		}
+	}
+}
+
+// makeDue moves every pending delivery's next attempt into the past so
+// DeliverDue picks it up regardless of clock skew with the database.
+func (s *OutgoingWebhookServiceTestSuite) makeDue() {
+	_, err := s.conn.Exec(context.Background(), `
+		UPDATE outgoing_webhook_deliveries
+		SET next_attempt_at = NOW() - INTERVAL '1 minute'
+		WHERE status = 'pending'
+	`)
+	s.Require().NoError(err)
+}
+
+func (s *OutgoingWebhookServiceTestSuite) TestOutgoingWebhookService() {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan *received, 10)
+	var failing atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- &received{header: r.Header.Clone(), body: body}
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewOutgoingWebhookService(s.conn, server.Client(), s.eventService).(*outgoingWebhookService)
+
+	user := &models.User{
+		FirstName: "Webhook",
+		LastName:  "Owner",
+		Email:     "webhook.owner@example.com",
+	}
+	s.Require().NoError(s.userService.CreateUser(user, nil))
+
+	room := &models.Room{
+		Name:       "Webhooks",
+		MaxMembers: 3,
+		CreatedBy:  user.ID,
+	}
+	s.Require().NoError(s.roomService.CreateRoom(room, nil))
+
+	var webhook *models.OutgoingWebhook
+
+	s.Run("create webhook", func() {
+		err := service.CreateOutgoingWebhook(&models.OutgoingWebhook{
+			RoomID:     room.ID,
+			URL:        "ftp://example.com",
+			EventTypes: []string{models.EventMessageCreated},
+			CreatedBy:  user.ID,
+		}, nil)
+		s.ErrorIs(err, ErrInvalidWebhookURL)
+
+		err = service.CreateOutgoingWebhook(&models.OutgoingWebhook{
+			RoomID:     room.ID,
+			URL:        server.URL,
+			EventTypes: []string{"user.deleted"},
+			CreatedBy:  user.ID,
+		}, nil)
+		s.ErrorIs(err, ErrInvalidWebhookEventTypes)
+
+		webhook = &models.OutgoingWebhook{
+			RoomID:     room.ID,
+			URL:        server.URL,
+			EventTypes: []string{models.EventMessageCreated, models.EventMessageCreated},
+			CreatedBy:  user.ID,
+		}
+		err = service.CreateOutgoingWebhook(webhook, nil)
+		s.Require().NoError(err)
+		s.NotEmpty(webhook.ID)
+		s.NotEmpty(webhook.Secret)
+		s.Equal([]string{models.EventMessageCreated}, webhook.EventTypes)
+
+		hooks, err := service.GetOutgoingWebhooks(room.ID, nil)
+		s.NoError(err)
+		s.Len(hooks, 1)
+		s.Empty(hooks[0].Secret)
+	})
+
+	s.Run("deliver signed event", func() {
+		s.eventService.Publish(&models.Event{
+			Type:   models.EventMessageCreated,
+			RoomID: room.ID,
+			Data:   map[string]string{"content": "hello"},
+		})
+		// private and unsubscribed events are not delivered
+		s.eventService.Publish(&models.Event{
+			Type:   models.EventMessageCreated,
+			RoomID: room.ID,
+			UserID: user.ID,
+		})
+		s.eventService.Publish(&models.Event{
+			Type:   models.EventMessagePinned,
+			RoomID: room.ID,
+		})
+
+		for len(service.queue) > 0 {
+			s.Require().NoError(service.recordEvent(<-service.queue))
+		}
+		s.makeDue()
+
+		n, err := service.DeliverDue(context.Background())
+		s.NoError(err)
+		s.Equal(1, n)
+
+		req := <-requests
+		s.Equal(models.EventMessageCreated, req.header.Get(webhooks.HeaderEvent))
+		s.NotEmpty(req.header.Get(webhooks.HeaderDelivery))
+		ts, err := strconv.ParseInt(req.header.Get(webhooks.HeaderTimestamp), 10, 64)
+		s.NoError(err)
+		s.True(webhooks.Verify(webhook.Secret, ts, req.body, req.header.Get(webhooks.HeaderSignature)))
+		s.Contains(string(req.body), "hello")
+
+		deliveries, err := service.GetWebhookDeliveries(webhook.ID, 10, nil)
+		s.NoError(err)
+		s.Len(deliveries, 1)
+		s.Equal(models.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+		s.Equal(1, deliveries[0].Attempts)
+		s.Equal(http.StatusNoContent, deliveries[0].LastStatusCode)
+		s.NotNil(deliveries[0].DeliveredAt)
+	})
+
+	s.Run("retry failed delivery", func() {
+		failing.Store(true)
+		defer failing.Store(false)
+
+		service.EnqueueEvent(&models.Event{
+			Type:   models.EventMessageCreated,
+			RoomID: room.ID,
+		})
+		s.Require().NoError(service.recordEvent(<-service.queue))
+		s.makeDue()
+
+		n, err := service.DeliverDue(context.Background())
+		s.NoError(err)
+		s.Equal(1, n)
+		<-requests
+
+		deliveries, err := service.GetWebhookDeliveries(webhook.ID, 10, nil)
+		s.NoError(err)
+		s.Len(deliveries, 2)
+		s.Equal(models.WebhookDeliveryStatusPending, deliveries[0].Status)
+		s.Equal(1, deliveries[0].Attempts)
+		s.Equal(http.StatusServiceUnavailable, deliveries[0].LastStatusCode)
+		s.NotEmpty(deliveries[0].LastError)
+		s.True(deliveries[0].NextAttemptAt.After(time.Now()))
+
+		// not due again until the backoff passes
+		n, err = service.DeliverDue(context.Background())
+		s.NoError(err)
+		s.Zero(n)
+	})
+}
+
+func TestOutgoingWebhookService(t *testing.T) {
+	suite.Run(t, new(OutgoingWebhookServiceTestSuite))
+}
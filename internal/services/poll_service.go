@@ -0,0 +1,343 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrPollQuestionRequired = errors.New("poll question is required")
+	ErrInvalidPollOptions   = errors.New("poll must have between 2 and 10 distinct options")
+	ErrPollClosesInPast     = errors.New("poll close time must be in the future")
+	ErrPollClosed           = errors.New("poll is closed")
+	ErrInvalidPollVote      = errors.New("invalid poll vote")
+)
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+type pollService struct {
+	conn           *pgxpool.Pool
+	roomService    RoomService
+	PollRepository PollRepository
+}
+
+func NewPollService(conn *pgxpool.Pool, roomService RoomService) PollService {
+	return &pollService{
+		conn:           conn,
+		roomService:    roomService,
+		PollRepository: repositories.NewPollRepository(conn),
+	}
+}
+
+// CreatePoll posts message to its room and attaches poll to it. The message
+// content defaults to the poll question so clients without poll support
+// still show something meaningful.
+func (s *pollService) CreatePoll(message *models.RoomMessage, poll *models.Poll, tx pgx.Tx) error {
+	poll.Question = strings.TrimSpace(poll.Question)
+	if poll.Question == "" {
+		return ErrPollQuestionRequired
+	}
+
+	seen := map[string]bool{}
+	for _, option := range poll.Options {
+		option.Text = strings.TrimSpace(option.Text)
+		if option.Text == "" || seen[option.Text] {
+			return ErrInvalidPollOptions
+		}
+		seen[option.Text] = true
+	}
+	if len(poll.Options) < minPollOptions || len(poll.Options) > maxPollOptions {
+		return ErrInvalidPollOptions
+	}
+
+	if poll.ClosesAt != nil && !poll.ClosesAt.After(time.Now()) {
+		return ErrPollClosesInPast
+	}
+
+	if strings.TrimSpace(message.Content) == "" {
+		message.Content = poll.Question
+	}
+	err := s.roomService.CreateMessage(message, tx)
+	if err != nil {
+		return err
+	}
+
+	poll.RoomID = message.RoomID
+	poll.RoomMessageID = message.ID
+	poll.CreatedBy = message.UserID
+	err = s.PollRepository.CreatePoll(poll, tx)
+	if err != nil {
+		return err
+	}
+
+	tallyPoll(poll, nil, "")
+	message.Poll = poll
+	return nil
+}
+
+// GetPoll returns a poll with its current results. The options viewerId
+// voted for are reported even on anonymous polls.
+func (s *pollService) GetPoll(id, viewerId string, tx pgx.Tx) (*models.Poll, error) {
+	poll, err := s.PollRepository.GetPoll(id, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadResults([]*models.Poll{poll}, viewerId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// Vote replaces the votes of userId on a poll with optionIds. Single choice
+// polls accept exactly one option. The poll is locked for the rest of tx so
+// concurrent votes by the same user cannot interleave, which means tx must
+// not be nil.
+func (s *pollService) Vote(pollId, userId string, optionIds []string, tx pgx.Tx) (*models.Poll, error) {
+	poll, err := s.lockOpenPoll(pollId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.PollRepository.GetPollsOptions([]string{poll.ID}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := map[string]bool{}
+	for _, option := range options {
+		valid[option.ID] = true
+	}
+
+	chosen := []string{}
+	seen := map[string]bool{}
+	for _, id := range optionIds {
+		if !valid[id] {
+			return nil, ErrInvalidPollVote
+		}
+		if !seen[id] {
+			seen[id] = true
+			chosen = append(chosen, id)
+		}
+	}
+	if len(chosen) == 0 || (!poll.MultipleChoice && len(chosen) > 1) {
+		return nil, ErrInvalidPollVote
+	}
+
+	err = s.PollRepository.DeletePollVotes(poll.ID, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range chosen {
+		err = s.PollRepository.CreatePollVote(&models.PollVote{
+			PollID:       poll.ID,
+			PollOptionID: id,
+			UserID:       userId,
+		}, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.loadResults([]*models.Poll{poll}, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// RetractVote removes every vote of userId on an open poll. Like Vote, tx
+// must not be nil.
+func (s *pollService) RetractVote(pollId, userId string, tx pgx.Tx) (*models.Poll, error) {
+	poll, err := s.lockOpenPoll(pollId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.PollRepository.DeletePollVotes(poll.ID, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadResults([]*models.Poll{poll}, userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// ClosePoll stops a poll from accepting votes before its close time.
+func (s *pollService) ClosePoll(pollId string, tx pgx.Tx) (*models.Poll, error) {
+	poll, err := s.lockOpenPoll(pollId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.PollRepository.ClosePoll(poll, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.loadResults([]*models.Poll{poll}, "", tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// LoadMessagePolls populates the Poll of each message that carries one.
+func (s *pollService) LoadMessagePolls(messages []*models.RoomMessage, tx pgx.Tx) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	messageIds := []string{}
+	for _, message := range messages {
+		messageIds = append(messageIds, message.ID)
+	}
+
+	polls, err := s.PollRepository.GetMessagesPolls(messageIds, tx)
+	if err != nil {
+		return err
+	}
+	if len(polls) == 0 {
+		return nil
+	}
+
+	err = s.loadResults(polls, "", tx)
+	if err != nil {
+		return err
+	}
+
+	byMessage := map[string]*models.Poll{}
+	for _, poll := range polls {
+		byMessage[poll.RoomMessageID] = poll
+	}
+	for _, message := range messages {
+		message.Poll = byMessage[message.ID]
+	}
+
+	return nil
+}
+
+func (s *pollService) lockOpenPoll(pollId string, tx pgx.Tx) (*models.Poll, error) {
+	poll, err := s.PollRepository.GetPollForUpdate(pollId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pollClosed(poll, time.Now()) {
+		return nil, ErrPollClosed
+	}
+
+	return poll, nil
+}
+
+func (s *pollService) loadResults(polls []*models.Poll, viewerId string, tx pgx.Tx) error {
+	pollIds := []string{}
+	for _, poll := range polls {
+		pollIds = append(pollIds, poll.ID)
+	}
+
+	options, err := s.PollRepository.GetPollsOptions(pollIds, tx)
+	if err != nil {
+		return err
+	}
+
+	votes, err := s.PollRepository.GetPollsVotes(pollIds, tx)
+	if err != nil {
+		return err
+	}
+
+	byPoll := map[string]*models.Poll{}
+	for _, poll := range polls {
+		poll.Options = []*models.PollOption{}
+		byPoll[poll.ID] = poll
+	}
+	for _, option := range options {
+		poll := byPoll[option.PollID]
+		poll.Options = append(poll.Options, option)
+	}
+
+	pollVotes := map[string][]*models.PollVote{}
+	for _, vote := range votes {
+		pollVotes[vote.PollID] = append(pollVotes[vote.PollID], vote)
+	}
+	for _, poll := range polls {
+		tallyPoll(poll, pollVotes[poll.ID], viewerId)
+	}
+
+	return nil
+}
+
+// tallyPoll fills in the results of poll from its votes. Voters are only
+// listed on public polls.
+func tallyPoll(poll *models.Poll, votes []*models.PollVote, viewerId string) {
+	poll.Closed = pollClosed(poll, time.Now())
+	poll.VotedOptionIDs = nil
+
+	byOption := map[string]*models.PollOption{}
+	for _, option := range poll.Options {
+		option.VoteCount = 0
+		option.VoterIDs = nil
+		byOption[option.ID] = option
+	}
+
+	voters := map[string]bool{}
+	for _, vote := range votes {
+		option, ok := byOption[vote.PollOptionID]
+		if !ok {
+			continue
+		}
+
+		option.VoteCount++
+		if !poll.Anonymous {
+			option.VoterIDs = append(option.VoterIDs, vote.UserID)
+		}
+		if viewerId != "" && vote.UserID == viewerId {
+			poll.VotedOptionIDs = append(poll.VotedOptionIDs, option.ID)
+		}
+		voters[vote.UserID] = true
+	}
+	poll.TotalVoters = len(voters)
+}
+
+func pollClosed(poll *models.Poll, now time.Time) bool {
+	return poll.ClosedAt != nil || (poll.ClosesAt != nil && !poll.ClosesAt.After(now))
+}
+
+type PollRepository interface {
+	CreatePoll(poll *models.Poll, tx pgx.Tx) error
+	GetPoll(id string, tx pgx.Tx) (*models.Poll, error)
+	GetPollForUpdate(id string, tx pgx.Tx) (*models.Poll, error)
+	GetMessagesPolls(messageIds []string, tx pgx.Tx) ([]*models.Poll, error)
+	GetPollsOptions(pollIds []string, tx pgx.Tx) ([]*models.PollOption, error)
+	GetPollsVotes(pollIds []string, tx pgx.Tx) ([]*models.PollVote, error)
+	CreatePollVote(vote *models.PollVote, tx pgx.Tx) error
+	DeletePollVotes(pollId, userId string, tx pgx.Tx) error
+	ClosePoll(poll *models.Poll, tx pgx.Tx) error
+}
+
+type PollService interface {
+	CreatePoll(message *models.RoomMessage, poll *models.Poll, tx pgx.Tx) error
+	GetPoll(id, viewerId string, tx pgx.Tx) (*models.Poll, error)
+	Vote(pollId, userId string, optionIds []string, tx pgx.Tx) (*models.Poll, error)
+	RetractVote(pollId, userId string, tx pgx.Tx) (*models.Poll, error)
+	ClosePoll(pollId string, tx pgx.Tx) (*models.Poll, error)
+	LoadMessagePolls(messages []*models.RoomMessage, tx pgx.Tx) error
+}
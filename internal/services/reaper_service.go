@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+const (
+	reapBatchSize     = 200
+	reapInterval      = 30 * time.Second
+	retentionInterval = time.Hour
+)
+
+// defaultRetentionDays reads how many days messages are kept in rooms
+// without their own retention period from MESSAGE_RETENTION_DAYS. Unset,
+// invalid or zero keeps them forever.
+func defaultRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("MESSAGE_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		return 0
+	}
+	return days
+}
+
+type reaperService struct {
+	conn              *pgxpool.Pool
+	eventService      EventService
+	attachmentService AttachmentService
+	RoomRepository    RoomRepository
+}
+
+func NewReaperService(conn *pgxpool.Pool, eventService EventService, attachmentService AttachmentService) ReaperService {
+	return &reaperService{
+		conn:              conn,
+		eventService:      eventService,
+		attachmentService: attachmentService,
+		RoomRepository:    repositories.NewRoomRepository(conn),
+	}
+}
+
+// ReapExpiredMessages purges expired messages until ctx is cancelled.
+func (s *reaperService) ReapExpiredMessages(ctx context.Context) {
+	s.loop(ctx, reapInterval, "expired", s.PurgeExpiredMessages)
+}
+
+// EnforceRetention purges messages past their room's retention period until
+// ctx is cancelled.
+func (s *reaperService) EnforceRetention(ctx context.Context) {
+	s.loop(ctx, retentionInterval, "retained", s.PurgeRetainedMessages)
+}
+
+// loop runs purge every interval, repeating it while it keeps returning
+// full batches.
+func (s *reaperService) loop(ctx context.Context, interval time.Duration, kind string, purge func(context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			n, err := purge(ctx)
+			if err != nil {
+				log.Printf("failed to purge %s messages: %v", kind, err)
+				break
+			}
+			if n < reapBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpiredMessages deletes one batch of expired messages along with
+// their attachments and returns how many it deleted. Each affected room is
+// sent an EventMessageExpired listing its deleted messages. Rooms on legal
+// hold are left alone.
+func (s *reaperService) PurgeExpiredMessages(ctx context.Context) (int, error) {
+	return s.purge(ctx, func(tx pgx.Tx) ([]*models.RoomMessage, error) {
+		return s.RoomRepository.GetExpiredRoomMessages(time.Now(), reapBatchSize, tx)
+	})
+}
+
+// PurgeRetainedMessages deletes one batch of messages older than their
+// room's retention period, the same way PurgeExpiredMessages does. Rooms on
+// legal hold are left alone.
+func (s *reaperService) PurgeRetainedMessages(ctx context.Context) (int, error) {
+	days := defaultRetentionDays()
+	return s.purge(ctx, func(tx pgx.Tx) ([]*models.RoomMessage, error) {
+		return s.RoomRepository.GetRetentionExpiredMessages(days, reapBatchSize, tx)
+	})
+}
+
+// purge deletes the messages returned by find, which must lock them in tx.
+func (s *reaperService) purge(ctx context.Context, find func(tx pgx.Tx) ([]*models.RoomMessage, error)) (int, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(context.Background())
+
+	messages, err := find(tx)
+	if err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	// attachment rows go with their messages, their files have to be
+	// removed separately once the delete is committed
+	err = s.attachmentService.LoadMessageAttachments(messages, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []string{}
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	err = s.RoomRepository.DeleteRoomMessages(ids, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	byRoom := map[string][]string{}
+	for _, message := range messages {
+		s.attachmentService.DeleteAttachmentBlobs(message.Attachments)
+		byRoom[message.RoomID] = append(byRoom[message.RoomID], message.ID)
+	}
+
+	for roomId, messageIds := range byRoom {
+		s.eventService.Publish(&models.Event{
+			Type:   models.EventMessageExpired,
+			RoomID: roomId,
+			Data:   &models.MessagesRemoved{MessageIDs: messageIds},
+		})
+	}
+
+	return len(messages), nil
+}
+
+type ReaperService interface {
+	ReapExpiredMessages(ctx context.Context)
+	PurgeExpiredMessages(ctx context.Context) (int, error)
+	EnforceRetention(ctx context.Context)
+	PurgeRetainedMessages(ctx context.Context) (int, error)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrInviteExpiresInPast = errors.New("invite expiry must be in the future")
+	ErrInvalidInviteUses   = errors.New("invite max uses must be at least 1")
+	ErrInviteExpired       = errors.New("invite has expired")
+	ErrInviteRevoked       = errors.New("invite has been revoked")
+	ErrInviteUsedUp        = errors.New("invite has no uses left")
+	ErrAlreadyMember       = errors.New("already a member")
+)
+
+type roomInviteService struct {
+	conn                 *pgxpool.Pool
+	roomService          RoomService
+	RoomInviteRepository RoomInviteRepository
+}
+
+func NewRoomInviteService(conn *pgxpool.Pool, roomService RoomService) RoomInviteService {
+	return &roomInviteService{
+		conn:                 conn,
+		roomService:          roomService,
+		RoomInviteRepository: repositories.NewRoomInviteRepository(conn),
+	}
+}
+
+// CreateInvite creates an invite link to a room and sets its generated
+// code. Expiry and max uses are optional.
+func (s *roomInviteService) CreateInvite(invite *models.RoomInvite, tx pgx.Tx) error {
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now()) {
+		return ErrInviteExpiresInPast
+	}
+	if invite.MaxUses != nil && *invite.MaxUses < 1 {
+		return ErrInvalidInviteUses
+	}
+
+	room, err := s.roomService.GetRoom(invite.RoomID, tx)
+	if err != nil {
+		return err
+	}
+	if room.Kind != models.RoomKindRoom {
+		return ErrDirectMessageRoom
+	}
+
+	invite.Code, err = newInviteCode()
+	if err != nil {
+		return err
+	}
+
+	return s.RoomInviteRepository.CreateRoomInvite(invite, tx)
+}
+
+func (s *roomInviteService) GetInvite(id string, tx pgx.Tx) (*models.RoomInvite, error) {
+	return s.RoomInviteRepository.GetRoomInvite(id, tx)
+}
+
+// GetInvites returns every invite to a room, newest first, including the
+// ones that no longer work.
+func (s *roomInviteService) GetInvites(roomId string, tx pgx.Tx) ([]*models.RoomInvite, error) {
+	return s.RoomInviteRepository.GetRoomInvites(roomId, tx)
+}
+
+// RevokeInvite stops an invite from being used. Revoking it again keeps the
+// original revocation time.
+func (s *roomInviteService) RevokeInvite(invite *models.RoomInvite, tx pgx.Tx) error {
+	if invite.RevokedAt != nil {
+		return nil
+	}
+	return s.RoomInviteRepository.RevokeRoomInvite(invite, tx)
+}
+
+// AcceptInvite adds userId to the room the invite with code is for. It
+// returns pgx.ErrNoRows if there is no such invite, and counts a use of the
+// invite only when the user joins.
+func (s *roomInviteService) AcceptInvite(code, userId string, tx pgx.Tx) (*models.RoomMember, error) {
+	invite, err := s.RoomInviteRepository.GetRoomInviteByCode(code, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case invite.RevokedAt != nil:
+		return nil, ErrInviteRevoked
+	case invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now()):
+		return nil, ErrInviteExpired
+	}
+
+	_, err = s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: userId,
+		RoomID: invite.RoomID,
+	}, tx)
+	if err == nil {
+		return nil, ErrAlreadyMember
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// the use is counted with a conditional update so concurrent joins
+	// cannot go over the limit
+	ok, err := s.RoomInviteRepository.UseRoomInvite(invite, tx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInviteUsedUp
+	}
+
+	member := &models.RoomMember{
+		RoomID: invite.RoomID,
+		UserID: userId,
+	}
+	err = s.roomService.AdmitMember(member, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func newInviteCode() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type RoomInviteRepository interface {
+	CreateRoomInvite(invite *models.RoomInvite, tx pgx.Tx) error
+	GetRoomInvite(id string, tx pgx.Tx) (*models.RoomInvite, error)
+	GetRoomInviteByCode(code string, tx pgx.Tx) (*models.RoomInvite, error)
+	GetRoomInvites(roomId string, tx pgx.Tx) ([]*models.RoomInvite, error)
+	UseRoomInvite(invite *models.RoomInvite, tx pgx.Tx) (bool, error)
+	RevokeRoomInvite(invite *models.RoomInvite, tx pgx.Tx) error
+}
+
+type RoomInviteService interface {
+	CreateInvite(invite *models.RoomInvite, tx pgx.Tx) error
+	GetInvite(id string, tx pgx.Tx) (*models.RoomInvite, error)
+	GetInvites(roomId string, tx pgx.Tx) ([]*models.RoomInvite, error)
+	RevokeInvite(invite *models.RoomInvite, tx pgx.Tx) error
+	AcceptInvite(code, userId string, tx pgx.Tx) (*models.RoomMember, error)
+}
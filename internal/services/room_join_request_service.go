@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrJoinRequestNotNeeded     = errors.New("room is public and can be joined directly")
+	ErrJoinRequestPending       = errors.New("a join request is already pending")
+	ErrJoinRequestReviewed      = errors.New("join request has already been reviewed")
+	ErrJoinRequestTooLong       = errors.New("join request message must be at most 500 characters")
+	ErrInvalidJoinRequestStatus = errors.New("status must be one of pending, approved or rejected")
+)
+
+const maxJoinRequestMessageLength = 500
+
+type roomJoinRequestService struct {
+	conn                      *pgxpool.Pool
+	roomService               RoomService
+	authorizationService      AuthorizationService
+	RoomJoinRequestRepository RoomJoinRequestRepository
+}
+
+func NewRoomJoinRequestService(conn *pgxpool.Pool, roomService RoomService, authorizationService AuthorizationService) RoomJoinRequestService {
+	return &roomJoinRequestService{
+		conn:                      conn,
+		roomService:               roomService,
+		authorizationService:      authorizationService,
+		RoomJoinRequestRepository: repositories.NewRoomJoinRequestRepository(conn),
+	}
+}
+
+// RequestToJoin asks to join a restricted room. Public rooms are joined
+// directly and private ones only through invites, so neither takes
+// requests. A user can only have one pending request per room.
+func (s *roomJoinRequestService) RequestToJoin(request *models.RoomJoinRequest, tx pgx.Tx) error {
+	if len([]rune(request.Message)) > maxJoinRequestMessageLength {
+		return ErrJoinRequestTooLong
+	}
+
+	room, err := s.roomService.GetRoom(request.RoomID, tx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case room.Kind != models.RoomKindRoom:
+		return ErrDirectMessageRoom
+	case room.Visibility == models.RoomVisibilityPrivate:
+		// private rooms are not listed, so they are reported as missing
+		return pgx.ErrNoRows
+	case room.Visibility != models.RoomVisibilityRestricted:
+		return ErrJoinRequestNotNeeded
+	}
+
+	_, err = s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: request.UserID,
+		RoomID: request.RoomID,
+	}, tx)
+	if err == nil {
+		return ErrAlreadyMember
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	_, err = s.RoomJoinRequestRepository.GetPendingRoomJoinRequest(request.RoomID, request.UserID, tx)
+	if err == nil {
+		return ErrJoinRequestPending
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	return s.RoomJoinRequestRepository.CreateRoomJoinRequest(request, tx)
+}
+
+func (s *roomJoinRequestService) GetJoinRequest(id string, tx pgx.Tx) (*models.RoomJoinRequest, error) {
+	return s.RoomJoinRequestRepository.GetRoomJoinRequest(id, tx)
+}
+
+// GetJoinRequests returns a room's requests with status, oldest first. An
+// empty status selects the pending ones.
+func (s *roomJoinRequestService) GetJoinRequests(roomId, status string, tx pgx.Tx) ([]*models.RoomJoinRequest, error) {
+	switch status {
+	case "":
+		status = models.JoinRequestPending
+	case models.JoinRequestPending, models.JoinRequestApproved, models.JoinRequestRejected:
+	default:
+		return nil, ErrInvalidJoinRequestStatus
+	}
+
+	return s.RoomJoinRequestRepository.GetRoomJoinRequests(roomId, status, tx)
+}
+
+// ApproveJoinRequest marks the request approved and adds its user to the
+// room through RoomService.AdmitMember, so the member limit still applies. The
+// caller should roll back tx on error.
+func (s *roomJoinRequestService) ApproveJoinRequest(request *models.RoomJoinRequest, reviewerId string, tx pgx.Tx) (*models.RoomMember, error) {
+	err := s.review(request, models.JoinRequestApproved, reviewerId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: request.UserID,
+		RoomID: request.RoomID,
+	}, tx)
+	if err == nil {
+		return nil, ErrAlreadyMember
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	member := &models.RoomMember{
+		RoomID: request.RoomID,
+		UserID: request.UserID,
+	}
+	err = s.roomService.AdmitMember(member, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (s *roomJoinRequestService) RejectJoinRequest(request *models.RoomJoinRequest, reviewerId string, tx pgx.Tx) error {
+	return s.review(request, models.JoinRequestRejected, reviewerId, tx)
+}
+
+func (s *roomJoinRequestService) review(request *models.RoomJoinRequest, status, reviewerId string, tx pgx.Tx) error {
+	if request.Status != models.JoinRequestPending {
+		return ErrJoinRequestReviewed
+	}
+
+	// the review is a conditional update so two approvers cannot both
+	// decide on the same request
+	ok, err := s.RoomJoinRequestRepository.ReviewRoomJoinRequest(request, status, reviewerId, tx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrJoinRequestReviewed
+	}
+	return nil
+}
+
+// GetApprovers returns the members of a room who can review its join
+// requests.
+func (s *roomJoinRequestService) GetApprovers(roomId string, tx pgx.Tx) ([]*models.RoomMember, error) {
+	members, err := s.roomService.GetRoomMembers(repositories.GetRoomMembersParams{RoomID: &roomId}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := []*models.RoomMember{}
+	for _, member := range members {
+		if s.authorizationService.Can(member.Role, PermissionReviewJoinRequests) {
+			approvers = append(approvers, member)
+		}
+	}
+	return approvers, nil
+}
+
+type RoomJoinRequestRepository interface {
+	CreateRoomJoinRequest(request *models.RoomJoinRequest, tx pgx.Tx) error
+	GetRoomJoinRequest(id string, tx pgx.Tx) (*models.RoomJoinRequest, error)
+	GetPendingRoomJoinRequest(roomId, userId string, tx pgx.Tx) (*models.RoomJoinRequest, error)
+	GetRoomJoinRequests(roomId, status string, tx pgx.Tx) ([]*models.RoomJoinRequest, error)
+	ReviewRoomJoinRequest(request *models.RoomJoinRequest, status, reviewerId string, tx pgx.Tx) (bool, error)
+}
+
+type RoomJoinRequestService interface {
+	RequestToJoin(request *models.RoomJoinRequest, tx pgx.Tx) error
+	GetJoinRequest(id string, tx pgx.Tx) (*models.RoomJoinRequest, error)
+	GetJoinRequests(roomId, status string, tx pgx.Tx) ([]*models.RoomJoinRequest, error)
+	ApproveJoinRequest(request *models.RoomJoinRequest, reviewerId string, tx pgx.Tx) (*models.RoomMember, error)
+	RejectJoinRequest(request *models.RoomJoinRequest, reviewerId string, tx pgx.Tx) error
+	GetApprovers(roomId string, tx pgx.Tx) ([]*models.RoomMember, error)
+}
@@ -2,30 +2,90 @@ package services
 
 import (
 	"errors"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/markdown"
 	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
 )
 
 var (
 	ErrMaxMembersReached = errors.New("max room members reached")
+	ErrMaxPinsReached    = errors.New("max room pins reached")
+	ErrAlreadyPinned     = errors.New("message already pinned")
+	ErrMessageNotInRoom  = errors.New("message does not belong to room")
+	ErrInvalidMessageTTL = errors.New("message ttl must not be negative")
+	ErrDirectMessageRoom = errors.New("not allowed in direct messages")
+	ErrInvalidRetention  = errors.New("retention days must not be negative")
+	ErrInvalidVisibility = errors.New("visibility must be public, restricted or private")
+	ErrPrivateRoom       = errors.New("room is private and can only be joined with an invite")
+	ErrApprovalRequired  = errors.New("room requires approval to join, send a join request")
+	ErrMessageTooLong    = errors.New("message must be at most 4000 characters")
+	ErrOwnerCannotLeave  = errors.New("the room owner cannot leave, delete the room instead")
 )
 
+const defaultMaxRoomPins = 50
+
+const transcriptBatchSize = 500
+
+// maxResolvedMentions caps how many distinct users a message can mention.
+const maxResolvedMentions = 50
+
+// maxMessageLength caps the characters in a message's content.
+const maxMessageLength = 4000
+
+// maxRoomPins reads the pin limit from MAX_ROOM_PINS, falling back to
+// defaultMaxRoomPins when it is unset or invalid.
+func maxRoomPins() int {
+	max, err := strconv.Atoi(os.Getenv("MAX_ROOM_PINS"))
+	if err != nil || max <= 0 {
+		return defaultMaxRoomPins
+	}
+	return max
+}
+
 type roomService struct {
-	conn           *pgxpool.Pool
-	RoomRepository RoomRepository
+	conn                       *pgxpool.Pool
+	moderationService          ModerationService
+	RoomRepository             RoomRepository
+	UserBlockRepository        UserBlockRepository
+	MemberModerationRepository MemberModerationRepository
 }
 
-func NewRoomService(conn *pgxpool.Pool) RoomService {
+func NewRoomService(conn *pgxpool.Pool, moderationService ModerationService) RoomService {
 	return &roomService{
-		conn:           conn,
-		RoomRepository: repositories.NewRoomRepository(conn),
+		conn:                       conn,
+		moderationService:          moderationService,
+		RoomRepository:             repositories.NewRoomRepository(conn),
+		UserBlockRepository:        repositories.NewUserBlockRepository(conn),
+		MemberModerationRepository: repositories.NewMemberModerationRepository(conn),
 	}
 }
 
+// CreateRoom creates a room owned by its creator. Rooms are public unless
+// they ask to be private.
 func (s *roomService) CreateRoom(room *models.Room, tx pgx.Tx) error {
+	if room.MessageTTL < 0 {
+		return ErrInvalidMessageTTL
+	}
+	if room.Visibility == "" {
+		room.Visibility = models.RoomVisibilityPublic
+	}
+	if !validVisibility(room.Visibility) {
+		return ErrInvalidVisibility
+	}
+
 	err := s.RoomRepository.CreateRoom(room, tx)
 	if err != nil {
 		return err
@@ -34,40 +94,137 @@ func (s *roomService) CreateRoom(room *models.Room, tx pgx.Tx) error {
 	member := &models.RoomMember{
 		RoomID: room.ID,
 		UserID: room.CreatedBy,
+		Role:   models.RoomRoleOwner,
 	}
-	return s.JoinRoom(member, tx)
+	return s.AdmitMember(member, tx)
 }
 
 func (s *roomService) GetRoom(id string, tx pgx.Tx) (*models.Room, error) {
 	return s.RoomRepository.GetRoom(id, tx)
 }
 
-func (s *roomService) GetRooms(createdBy *string, tx pgx.Tx) ([]*models.Room, error) {
-	return s.RoomRepository.GetRooms(createdBy, tx)
+func (s *roomService) GetRooms(params repositories.GetRoomsParams, tx pgx.Tx) ([]*models.Room, error) {
+	return s.RoomRepository.GetRooms(params, tx)
 }
 
 func (s *roomService) DeleteRoom(id string, tx pgx.Tx) error {
 	return s.RoomRepository.DeleteRoom(id, tx)
 }
 
+// UpdateRoom updates a room's settings. Direct and group messages have none.
 func (s *roomService) UpdateRoom(room *models.Room, tx pgx.Tx) error {
+	if room.Kind != models.RoomKindRoom {
+		return ErrDirectMessageRoom
+	}
+	if room.MessageTTL < 0 {
+		return ErrInvalidMessageTTL
+	}
+	if !validVisibility(room.Visibility) {
+		return ErrInvalidVisibility
+	}
+
 	return s.RoomRepository.UpdateRoom(room, tx)
 }
 
+func validVisibility(visibility string) bool {
+	switch visibility {
+	case models.RoomVisibilityPublic, models.RoomVisibilityRestricted, models.RoomVisibilityPrivate:
+		return true
+	default:
+		return false
+	}
+}
+
+// UpdateRetention saves room's retention period and legal hold. A nil
+// period falls back to the server default and zero keeps messages forever.
+func (s *roomService) UpdateRetention(room *models.Room, tx pgx.Tx) error {
+	if room.RetentionDays != nil && *room.RetentionDays < 0 {
+		return ErrInvalidRetention
+	}
+
+	return s.RoomRepository.UpdateRoomRetention(room, tx)
+}
+
 func (s *roomService) GetRoomMember(id string, tx pgx.Tx) (*models.RoomMember, error) {
 	return s.RoomRepository.GetRoomMember(id, tx)
 }
 
+func (s *roomService) UpdateMemberRole(member *models.RoomMember, tx pgx.Tx) error {
+	return s.RoomRepository.UpdateRoomMemberRole(member, tx)
+}
+
+// LeaveRoom removes a member from their room. A room has a single owner and
+// ownership cannot be handed over, so the owner is refused with
+// ErrOwnerCannotLeave rather than leaving the room without one.
 func (s *roomService) LeaveRoom(roomMemberID string, tx pgx.Tx) error {
+	member, err := s.RoomRepository.GetRoomMember(roomMemberID, tx)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.RoomRoleOwner {
+		return ErrOwnerCannotLeave
+	}
+
 	return s.RoomRepository.DeleteRoomMember(roomMemberID, tx)
 }
 
+// JoinRoom adds a user who asked to join a room. Private rooms turn them
+// away with ErrPrivateRoom and restricted rooms with ErrApprovalRequired;
+// those users get in through an invite or a join request instead.
 func (s *roomService) JoinRoom(member *models.RoomMember, tx pgx.Tx) error {
-	room, err := s.GetRoom(member.RoomID, tx)
+	room, err := s.joinableRoom(member.RoomID, tx)
+	if err != nil {
+		return err
+	}
+
+	switch room.Visibility {
+	case models.RoomVisibilityPrivate:
+		return ErrPrivateRoom
+	case models.RoomVisibilityRestricted:
+		return ErrApprovalRequired
+	}
+
+	return s.addMember(room, member, tx)
+}
+
+// AdmitMember adds a member to a room whatever its visibility. It is only
+// for users who were already let in: room creators, accepted invites,
+// approved join requests, and the integrations and imports set up by the
+// room's admins.
+func (s *roomService) AdmitMember(member *models.RoomMember, tx pgx.Tx) error {
+	room, err := s.joinableRoom(member.RoomID, tx)
 	if err != nil {
 		return err
 	}
 
+	return s.addMember(room, member, tx)
+}
+
+// joinableRoom returns the room with the given id. Direct and group
+// messages cannot be joined; their participants are added by the people
+// already in them.
+func (s *roomService) joinableRoom(roomId string, tx pgx.Tx) (*models.Room, error) {
+	room, err := s.GetRoom(roomId, tx)
+	if err != nil {
+		return nil, err
+	}
+	if room.Kind != models.RoomKindRoom {
+		return nil, ErrDirectMessageRoom
+	}
+	return room, nil
+}
+
+// addMember adds member to room while it has space. Users with an active
+// ban are turned away with ErrBanned.
+func (s *roomService) addMember(room *models.Room, member *models.RoomMember, tx pgx.Tx) error {
+	_, err := s.MemberModerationRepository.GetActiveRoomBan(room.ID, member.UserID, tx)
+	if err == nil {
+		return ErrBanned
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
 	memberCount, err := s.RoomRepository.GetRoomMemberCount(room.ID, tx)
 	if err != nil {
 		return err
@@ -88,8 +245,103 @@ func (s *roomService) GetRoomMemberByWhere(params repositories.GetRoomMemberByWh
 	return s.RoomRepository.GetRoomMemberByWhere(params, tx)
 }
 
+// CreateMessage renders the message's Markdown content to sanitized HTML and
+// rich-text entities before storing it. Messages in rooms with a message TTL
+// expire after it, or sooner if the message sets an earlier ExpiresAt.
+// Messages in direct messages between users who blocked one another are
+// rejected with ErrBlocked, messages from muted members with ErrMuted and
+// messages longer than maxMessageLength with ErrMessageTooLong.
+//
+// Every message first goes through the room's moderation pipeline, which
+// may redact its content or reject it with ErrMessageRejected.
 func (s *roomService) CreateMessage(message *models.RoomMessage, tx pgx.Tx) error {
-	return s.RoomRepository.CreateRoomMessage(message, tx)
+	if utf8.RuneCountInString(message.Content) > maxMessageLength {
+		return ErrMessageTooLong
+	}
+
+	room, err := s.RoomRepository.GetRoom(message.RoomID, tx)
+	if err != nil {
+		return err
+	}
+
+	if room.Kind == models.RoomKindDirect {
+		blocked, err := s.UserBlockRepository.UsersBlocked(strings.Split(room.ParticipantsKey, ","), tx)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrBlocked
+		}
+	}
+
+	_, err = s.MemberModerationRepository.GetActiveRoomMute(room.ID, message.UserID, tx)
+	if err == nil {
+		return ErrMuted
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	result, err := s.moderationService.Moderate(message, tx)
+	if err != nil {
+		return err
+	}
+	content := message.Content
+	message.Content = result.Content
+
+	if room.MessageTTL > 0 {
+		expiresAt := time.Now().Add(time.Duration(room.MessageTTL) * time.Second)
+		if message.ExpiresAt == nil || expiresAt.Before(*message.ExpiresAt) {
+			message.ExpiresAt = &expiresAt
+		}
+	}
+
+	s.renderMessage(message, tx)
+	err = s.RoomRepository.CreateRoomMessage(message, tx)
+	if err != nil {
+		return err
+	}
+
+	return s.moderationService.RecordDecisions(message, content, result.Decisions, tx)
+}
+
+// ImportMessage stores a message brought over from another system, keeping
+// its CreatedAt. Like CreateMessage it renders the content, but room
+// message TTLs are not applied to history.
+func (s *roomService) ImportMessage(message *models.RoomMessage, tx pgx.Tx) error {
+	s.renderMessage(message, tx)
+	return s.RoomRepository.CreateImportedRoomMessage(message, tx)
+}
+
+// renderMessage renders the message's content. Only mentions of room
+// members are resolved, at most maxResolvedMentions of them, with a single
+// query; the rest are left as plain text.
+func (s *roomService) renderMessage(message *models.RoomMessage, tx pgx.Tx) {
+	userIds := []string{}
+	for _, id := range markdown.Mentions(message.Content) {
+		if len(userIds) == maxResolvedMentions {
+			break
+		}
+		if uuid.Validate(id) == nil {
+			userIds = append(userIds, id)
+		}
+	}
+
+	names := map[string]string{}
+	if len(userIds) > 0 {
+		users, err := s.RoomRepository.GetRoomMemberUsers(message.RoomID, userIds, tx)
+		if err != nil {
+			log.Printf("failed to resolve mentions in room %s: %v", message.RoomID, err)
+		}
+		for _, user := range users {
+			names[user.ID] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+	}
+
+	message.ContentHTML, message.Entities = markdown.Render(message.Content, func(userID string) (string, bool) {
+		name, ok := names[userID]
+		return name, ok
+	})
 }
 
 func (s *roomService) GetMessage(id string, tx pgx.Tx) (*models.RoomMessage, error) {
@@ -100,16 +352,153 @@ func (s *roomService) GetMessages(params repositories.GetRoomMessagesParams, tx
 	return s.RoomRepository.GetRoomMessages(params, tx)
 }
 
+// GetMessagesPage returns a page of room messages in chronological order,
+// along with the cursors for the pages immediately before and after it.
+func (s *roomService) GetMessagesPage(params repositories.GetRoomMessagesPageParams, tx pgx.Tx) (*models.RoomMessagePage, error) {
+	limit := params.Limit
+	params.Limit = limit + 1
+
+	messages, err := s.RoomRepository.GetRoomMessagesPage(params, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	if params.After == nil {
+		slices.Reverse(messages)
+	}
+
+	page := &models.RoomMessagePage{Messages: messages}
+	if len(messages) == 0 {
+		return page, nil
+	}
+
+	first := utils.EncodeCursor(utils.Cursor{CreatedAt: messages[0].CreatedAt, ID: messages[0].ID})
+	last := utils.EncodeCursor(utils.Cursor{CreatedAt: messages[len(messages)-1].CreatedAt, ID: messages[len(messages)-1].ID})
+
+	if params.After != nil {
+		page.PrevCursor = first
+		if hasMore {
+			page.NextCursor = last
+		}
+	} else {
+		if hasMore {
+			page.PrevCursor = first
+		}
+		if params.Before != nil {
+			page.NextCursor = last
+		}
+	}
+
+	return page, nil
+}
+
+// ExportTranscript passes a room's whole history to fn in chronological
+// batches, so callers can stream it without loading the room into memory.
+// It stops at the first error returned by fn.
+func (s *roomService) ExportTranscript(roomId string, fn func([]*models.TranscriptEntry) error, tx pgx.Tx) error {
+	var after *utils.Cursor
+	for {
+		entries, err := s.RoomRepository.GetRoomTranscript(roomId, after, transcriptBatchSize, tx)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if err := fn(entries); err != nil {
+			return err
+		}
+		if len(entries) < transcriptBatchSize {
+			return nil
+		}
+
+		last := entries[len(entries)-1]
+		after = &utils.Cursor{CreatedAt: last.CreatedAt, ID: last.MessageID}
+	}
+}
+
 func (s *roomService) DeleteMessage(id string, tx pgx.Tx) error {
 	return s.RoomRepository.DeleteRoomMessage(id, tx)
 }
 
+// PinMessage pins a message of the room while the room is under its pin
+// limit. The room is locked until tx ends so concurrent pins cannot go over
+// the limit; callers should pass a transaction.
+func (s *roomService) PinMessage(pin *models.RoomPin, tx pgx.Tx) error {
+	_, err := s.RoomRepository.GetRoomForUpdate(pin.RoomID, tx)
+	if err != nil {
+		return err
+	}
+
+	message, err := s.GetMessage(pin.RoomMessageID, tx)
+	if err != nil {
+		return err
+	}
+
+	if message.RoomID != pin.RoomID {
+		return ErrMessageNotInRoom
+	}
+
+	_, err = s.RoomRepository.GetRoomPinByWhere(pin.RoomID, pin.RoomMessageID, tx)
+	if err == nil {
+		return ErrAlreadyPinned
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	pinCount, err := s.RoomRepository.GetRoomPinCount(pin.RoomID, tx)
+	if err != nil {
+		return err
+	}
+
+	if maxRoomPins() < *pinCount+1 {
+		return ErrMaxPinsReached
+	}
+
+	err = s.RoomRepository.CreateRoomPin(pin, tx)
+	if err != nil {
+		return err
+	}
+
+	pin.Message = message
+	return nil
+}
+
+func (s *roomService) UnpinMessage(roomId, messageId string, tx pgx.Tx) (*models.RoomPin, error) {
+	pin, err := s.RoomRepository.GetRoomPinByWhere(roomId, messageId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pin, s.RoomRepository.DeleteRoomPin(pin.ID, tx)
+}
+
+func (s *roomService) GetPins(roomId string, tx pgx.Tx) ([]*models.RoomPin, error) {
+	return s.RoomRepository.GetRoomPins(roomId, tx)
+}
+
 type RoomRepository interface {
 	CreateRoom(room *models.Room, tx pgx.Tx) error
+	CreateDirectRoom(room *models.Room, tx pgx.Tx) error
 	GetRoom(id string, tx pgx.Tx) (*models.Room, error)
-	GetRooms(createdBy *string, tx pgx.Tx) ([]*models.Room, error)
+	GetRoomForUpdate(id string, tx pgx.Tx) (*models.Room, error)
+	GetRoomByParticipantsKey(key string, tx pgx.Tx) (*models.Room, error)
+	GetRooms(params repositories.GetRoomsParams, tx pgx.Tx) ([]*models.Room, error)
+	GetUserConversations(userId string, tx pgx.Tx) ([]*models.Room, error)
+	GetConversationParticipants(roomIds []string, tx pgx.Tx) (map[string][]*models.User, error)
+	GetRoomMemberUsers(roomId string, userIds []string, tx pgx.Tx) ([]*models.User, error)
 	DeleteRoom(id string, tx pgx.Tx) error
 	UpdateRoom(room *models.Room, tx pgx.Tx) error
+	UpdateConversation(room *models.Room, tx pgx.Tx) error
+	UpdateRoomRetention(room *models.Room, tx pgx.Tx) error
+	UpdateRoomMemberRole(member *models.RoomMember, tx pgx.Tx) error
 	CreateRoomMember(member *models.RoomMember, tx pgx.Tx) error
 	GetRoomMember(id string, tx pgx.Tx) (*models.RoomMember, error)
 	GetRoomMemberByWhere(params repositories.GetRoomMemberByWhereParams, tx pgx.Tx) (*models.RoomMember, error)
@@ -117,24 +506,44 @@ type RoomRepository interface {
 	GetRoomMembers(params repositories.GetRoomMembersParams, tx pgx.Tx) ([]*models.RoomMember, error)
 	DeleteRoomMember(id string, tx pgx.Tx) error
 	CreateRoomMessage(message *models.RoomMessage, tx pgx.Tx) error
+	CreateImportedRoomMessage(message *models.RoomMessage, tx pgx.Tx) error
 	GetRoomMessage(id string, tx pgx.Tx) (*models.RoomMessage, error)
 	GetRoomMessages(params repositories.GetRoomMessagesParams, tx pgx.Tx) ([]*models.RoomMessage, error)
+	GetRoomMessagesPage(params repositories.GetRoomMessagesPageParams, tx pgx.Tx) ([]*models.RoomMessage, error)
+	GetRoomTranscript(roomId string, after *utils.Cursor, limit int, tx pgx.Tx) ([]*models.TranscriptEntry, error)
 	DeleteRoomMessage(id string, tx pgx.Tx) error
+	GetExpiredRoomMessages(expiredBefore time.Time, limit int, tx pgx.Tx) ([]*models.RoomMessage, error)
+	GetRetentionExpiredMessages(defaultDays int, limit int, tx pgx.Tx) ([]*models.RoomMessage, error)
+	DeleteRoomMessages(ids []string, tx pgx.Tx) error
+	CreateRoomPin(pin *models.RoomPin, tx pgx.Tx) error
+	GetRoomPinByWhere(roomId, messageId string, tx pgx.Tx) (*models.RoomPin, error)
+	GetRoomPins(roomId string, tx pgx.Tx) ([]*models.RoomPin, error)
+	GetRoomPinCount(roomId string, tx pgx.Tx) (*int, error)
+	DeleteRoomPin(id string, tx pgx.Tx) error
 }
 
 type RoomService interface {
 	CreateRoom(room *models.Room, tx pgx.Tx) error
 	GetRoom(id string, tx pgx.Tx) (*models.Room, error)
-	GetRooms(createdBy *string, tx pgx.Tx) ([]*models.Room, error)
+	GetRooms(params repositories.GetRoomsParams, tx pgx.Tx) ([]*models.Room, error)
 	DeleteRoom(id string, tx pgx.Tx) error
 	UpdateRoom(room *models.Room, tx pgx.Tx) error
+	UpdateRetention(room *models.Room, tx pgx.Tx) error
 	GetRoomMember(id string, tx pgx.Tx) (*models.RoomMember, error)
 	GetRoomMemberByWhere(params repositories.GetRoomMemberByWhereParams, tx pgx.Tx) (*models.RoomMember, error)
+	UpdateMemberRole(member *models.RoomMember, tx pgx.Tx) error
 	LeaveRoom(roomMemberId string, tx pgx.Tx) error
 	JoinRoom(member *models.RoomMember, tx pgx.Tx) error
+	AdmitMember(member *models.RoomMember, tx pgx.Tx) error
 	GetRoomMembers(params repositories.GetRoomMembersParams, tx pgx.Tx) ([]*models.RoomMember, error)
 	CreateMessage(message *models.RoomMessage, tx pgx.Tx) error
+	ImportMessage(message *models.RoomMessage, tx pgx.Tx) error
 	GetMessage(id string, tx pgx.Tx) (*models.RoomMessage, error)
 	GetMessages(params repositories.GetRoomMessagesParams, tx pgx.Tx) ([]*models.RoomMessage, error)
+	GetMessagesPage(params repositories.GetRoomMessagesPageParams, tx pgx.Tx) (*models.RoomMessagePage, error)
+	ExportTranscript(roomId string, fn func([]*models.TranscriptEntry) error, tx pgx.Tx) error
 	DeleteMessage(id string, tx pgx.Tx) error
+	PinMessage(pin *models.RoomPin, tx pgx.Tx) error
+	UnpinMessage(roomId, messageId string, tx pgx.Tx) (*models.RoomPin, error)
+	GetPins(roomId string, tx pgx.Tx) ([]*models.RoomPin, error)
 }
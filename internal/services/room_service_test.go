@@ -3,8 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +17,8 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/princecee/go_chat/internal/db/repositories"
 	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/storage"
+	"github.com/princecee/go_chat/utils"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -36,7 +42,7 @@ func (s *RoomServiceTestSuite) SetupSuite() {
 	roomRepository := repositories.NewRoomRepository(conn)
 	userRepository := repositories.NewUserRepository(conn)
 	s.conn = conn
-	s.roomService = &roomService{conn: conn, RoomRepository: roomRepository}
+	s.roomService = &roomService{conn: conn, moderationService: NewModerationService(conn), RoomRepository: roomRepository, MemberModerationRepository: repositories.NewMemberModerationRepository(conn)}
 	s.userService = &userService{conn: conn, UserRepository: userRepository}
 }
 
@@ -49,6 +55,7 @@ func (s *RoomServiceTestSuite) TearDownSuite() {
 		DELETE FROM room_members;
 		DELETE FROM rooms;
 		DELETE FROM users;
+		DELETE FROM link_previews;
 	`
 
 	_, err := s.conn.Exec(context.Background(), teardownQuery)
@@ -127,16 +134,17 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 		s.Len(roomMembers, 1)
 		s.Equal(room.ID, roomMember.RoomID)
 		s.Equal(creator.ID, roomMember.UserID)
+		s.Equal(models.RoomRoleOwner, roomMember.Role)
 	})
 
 	s.Run("get rooms", func() {
-		rooms, err := s.roomService.GetRooms(nil, nil)
+		rooms, err := s.roomService.GetRooms(repositories.GetRoomsParams{}, nil)
 		s.NoError(err)
 		s.Equal(room.ID, rooms[0].ID)
 		s.Equal(room.CreatedBy, rooms[0].CreatedBy)
 		s.Equal(1, len(rooms))
 
-		rooms, err = s.roomService.GetRooms(&room.CreatedBy, nil)
+		rooms, err = s.roomService.GetRooms(repositories.GetRoomsParams{CreatedBy: &room.CreatedBy}, nil)
 		s.NoError(err)
 		s.Equal(room.ID, rooms[0].ID)
 		s.Equal(room.CreatedBy, rooms[0].CreatedBy)
@@ -176,6 +184,20 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 			mc := *membersCount
 			s.Equal(3, mc)
 
+			members, err := s.roomService.GetRoomMembers(repositories.GetRoomMembersParams{
+				RoomID: &room.ID,
+			}, nil)
+			s.NoError(err)
+			s.Len(members, 3)
+
+			members, err = s.roomService.GetRoomMembers(repositories.GetRoomMembersParams{
+				RoomID: &room.ID,
+				UserID: &successUsers[0].ID,
+			}, nil)
+			s.NoError(err)
+			s.Require().Len(members, 1)
+			s.Equal(successUsers[0].ID, members[0].UserID)
+
 			for _, user := range errorUsers {
 				member := models.RoomMember{
 					RoomID: room.ID,
@@ -205,6 +227,14 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 			err = s.roomService.LeaveRoom(member.ID, nil)
 			s.NoError(err)
 
+			owner, err := s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+				UserID: creator.ID,
+				RoomID: room.ID,
+			}, nil)
+			s.NoError(err)
+			err = s.roomService.LeaveRoom(owner.ID, nil)
+			s.ErrorIs(err, ErrOwnerCannotLeave)
+
 			membersCount, err := s.roomService.RoomRepository.GetRoomMemberCount(room.ID, nil)
 			s.NoError(err)
 			mc := *membersCount
@@ -234,6 +264,12 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 			s.NoError(err)
 			s.Greater(message.UpdatedAt, then)
 			s.NotEmpty(message.ID)
+
+			long := message
+			long.ID = ""
+			long.Content = strings.Repeat("é", maxMessageLength+1)
+			err = s.roomService.CreateMessage(&long, nil)
+			s.ErrorIs(err, ErrMessageTooLong)
 		})
 
 		s.Run("get message", func() {
@@ -254,6 +290,182 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 			s.Equal(message.Content, messages[0].Content)
 		})
 
+		s.Run("get messages page", func() {
+			for i := 0; i < 4; i++ {
+				err := s.roomService.CreateMessage(&models.RoomMessage{
+					RoomID:       room.ID,
+					RoomMemberID: roomMember.ID,
+					UserID:       creator.ID,
+					Content:      fmt.Sprintf("page message %d", i),
+				}, nil)
+				s.NoError(err)
+			}
+
+			latest, err := s.roomService.GetMessagesPage(repositories.GetRoomMessagesPageParams{
+				RoomID: room.ID,
+				Limit:  2,
+			}, nil)
+			s.NoError(err)
+			s.Len(latest.Messages, 2)
+			s.Equal("page message 2", latest.Messages[0].Content)
+			s.Equal("page message 3", latest.Messages[1].Content)
+			s.NotEmpty(latest.PrevCursor)
+			s.Empty(latest.NextCursor)
+
+			before, err := utils.DecodeCursor(latest.PrevCursor)
+			s.NoError(err)
+
+			older, err := s.roomService.GetMessagesPage(repositories.GetRoomMessagesPageParams{
+				RoomID: room.ID,
+				Before: before,
+				Limit:  2,
+			}, nil)
+			s.NoError(err)
+			s.Len(older.Messages, 2)
+			s.Equal("page message 0", older.Messages[0].Content)
+			s.Equal("page message 1", older.Messages[1].Content)
+			s.NotEmpty(older.PrevCursor)
+			s.NotEmpty(older.NextCursor)
+
+			after, err := utils.DecodeCursor(older.NextCursor)
+			s.NoError(err)
+
+			newer, err := s.roomService.GetMessagesPage(repositories.GetRoomMessagesPageParams{
+				RoomID: room.ID,
+				After:  after,
+				Limit:  2,
+			}, nil)
+			s.NoError(err)
+			s.Len(newer.Messages, 2)
+			s.Equal(latest.Messages[0].ID, newer.Messages[0].ID)
+			s.Equal(latest.Messages[1].ID, newer.Messages[1].ID)
+			s.Empty(newer.NextCursor)
+		})
+
+		s.Run("render message markdown", func() {
+			rich := models.RoomMessage{
+				RoomID:       room.ID,
+				RoomMemberID: roomMember.ID,
+				UserID:       creator.ID,
+				Content:      fmt.Sprintf("**hey** <@%s>, see <script> <@%s>", creator.ID, users[4].ID),
+			}
+
+			err := s.roomService.CreateMessage(&rich, nil)
+			s.NoError(err)
+
+			_message, err := s.roomService.GetMessage(rich.ID, nil)
+			s.NoError(err)
+			s.Equal(fmt.Sprintf(
+				`<strong>hey</strong> <span class="mention" data-user-id="%s">@%s %s</span>, see &lt;script&gt; &lt;@%s&gt;`,
+				creator.ID, creator.FirstName, creator.LastName, users[4].ID,
+			), _message.ContentHTML)
+			s.Len(_message.Entities, 2)
+			s.Equal(models.EntityBold, _message.Entities[0].Type)
+			s.Equal(models.EntityMention, _message.Entities[1].Type)
+			s.Equal(creator.ID, _message.Entities[1].UserID)
+		})
+
+		s.Run("unfurl message links", func() {
+			fetches := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches++
+				w.Header().Set("Content-Type", "text/html")
+				fmt.Fprint(w, `<html><head><meta property="og:title" content="Release notes"></head></html>`)
+			}))
+			defer server.Close()
+
+			unfurlService := NewUnfurlService(s.conn, server.Client(), NewEventService())
+
+			linked := models.RoomMessage{
+				RoomID:       room.ID,
+				RoomMemberID: roomMember.ID,
+				UserID:       creator.ID,
+				Content:      fmt.Sprintf("read %s/notes and [again](%s/notes)", server.URL, server.URL),
+			}
+			err := s.roomService.CreateMessage(&linked, nil)
+			s.NoError(err)
+
+			previews, err := unfurlService.UnfurlMessage(context.Background(), &linked)
+			s.NoError(err)
+			s.Len(previews, 1)
+			s.Equal("Release notes", previews[0].Title)
+
+			// the second unfurl is served from the preview cache
+			previews, err = unfurlService.UnfurlMessage(context.Background(), &linked)
+			s.NoError(err)
+			s.Len(previews, 1)
+			s.Equal(1, fetches)
+
+			messages := []*models.RoomMessage{{ID: linked.ID}}
+			err = unfurlService.LoadMessagePreviews(messages, nil)
+			s.NoError(err)
+			s.Len(messages[0].Previews, 1)
+			s.Equal(server.URL+"/notes", messages[0].Previews[0].URL)
+		})
+
+		s.Run("pin and unpin message", func() {
+			pin := models.RoomPin{
+				RoomID:        room.ID,
+				RoomMessageID: message.ID,
+				PinnedBy:      creator.ID,
+			}
+
+			err := s.roomService.PinMessage(&pin, nil)
+			s.NoError(err)
+			s.NotEmpty(pin.ID)
+			s.Equal(message.Content, pin.Message.Content)
+
+			err = s.roomService.PinMessage(&models.RoomPin{
+				RoomID:        room.ID,
+				RoomMessageID: message.ID,
+				PinnedBy:      creator.ID,
+			}, nil)
+			s.ErrorIs(err, ErrAlreadyPinned)
+
+			pins, err := s.roomService.GetPins(room.ID, nil)
+			s.NoError(err)
+			s.Len(pins, 1)
+			s.Equal(message.ID, pins[0].Message.ID)
+
+			_pin, err := s.roomService.UnpinMessage(room.ID, message.ID, nil)
+			s.NoError(err)
+			s.Equal(pin.ID, _pin.ID)
+
+			pins, err = s.roomService.GetPins(room.ID, nil)
+			s.NoError(err)
+			s.Len(pins, 0)
+
+			// a pin waits for the room lock held by another, then sees the
+			// limit it reached
+			s.T().Setenv("MAX_ROOM_PINS", "1")
+			other := models.RoomMessage{RoomID: room.ID, RoomMemberID: roomMember.ID, UserID: creator.ID, Content: "Pin me too"}
+			s.Require().NoError(s.roomService.CreateMessage(&other, nil))
+
+			tx, err := s.conn.Begin(context.Background())
+			s.Require().NoError(err)
+			defer tx.Rollback(context.Background())
+
+			first := models.RoomPin{RoomID: room.ID, RoomMessageID: message.ID, PinnedBy: creator.ID}
+			s.NoError(s.roomService.PinMessage(&first, tx))
+
+			done := make(chan error, 1)
+			go func() {
+				done <- s.roomService.PinMessage(&models.RoomPin{RoomID: room.ID, RoomMessageID: other.ID, PinnedBy: creator.ID}, nil)
+			}()
+
+			select {
+			case err := <-done:
+				s.Failf("pin did not wait for the room lock", "err: %v", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			s.NoError(tx.Commit(context.Background()))
+			s.ErrorIs(<-done, ErrMaxPinsReached)
+
+			_, err = s.roomService.UnpinMessage(room.ID, message.ID, nil)
+			s.NoError(err)
+		})
+
 		s.Run("delete message", func() {
 			err := s.roomService.DeleteMessage(message.ID, nil)
 			s.NoError(err)
@@ -264,6 +476,133 @@ func (s *RoomServiceTestSuite) TestRoomService() {
 		})
 	})
 
+	s.Run("enforce retention", func() {
+		eventService := NewEventService()
+		reaper := NewReaperService(s.conn, eventService, NewAttachmentService(s.conn, storage.NewLocalStore(s.T().TempDir()), eventService))
+
+		old := models.RoomMessage{RoomID: room.ID, RoomMemberID: roomMember.ID, UserID: creator.ID, Content: "Old news"}
+		recent := models.RoomMessage{RoomID: room.ID, RoomMemberID: roomMember.ID, UserID: creator.ID, Content: "Fresh news"}
+		s.NoError(s.roomService.CreateMessage(&old, nil))
+		s.NoError(s.roomService.CreateMessage(&recent, nil))
+
+		expiring := models.RoomMessage{RoomID: room.ID, RoomMemberID: roomMember.ID, UserID: creator.ID, Content: "Gone soon"}
+		s.NoError(s.roomService.CreateMessage(&expiring, nil))
+
+		_, err := s.conn.Exec(context.Background(), "UPDATE room_messages SET created_at = NOW() - INTERVAL '40 days' WHERE id = $1", old.ID)
+		s.NoError(err)
+		_, err = s.conn.Exec(context.Background(), "UPDATE room_messages SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1", expiring.ID)
+		s.NoError(err)
+
+		days := -1
+		room.RetentionDays = &days
+		s.ErrorIs(s.roomService.UpdateRetention(room, nil), ErrInvalidRetention)
+
+		days = 30
+		room.LegalHold = true
+		s.NoError(s.roomService.UpdateRetention(room, nil))
+
+		n, err := reaper.PurgeRetainedMessages(context.Background())
+		s.NoError(err)
+		s.Equal(0, n)
+
+		_, err = reaper.PurgeExpiredMessages(context.Background())
+		s.NoError(err)
+		_, err = s.roomService.GetMessage(expiring.ID, nil)
+		s.NoError(err)
+
+		room.LegalHold = false
+		s.NoError(s.roomService.UpdateRetention(room, nil))
+
+		_, err = reaper.PurgeExpiredMessages(context.Background())
+		s.NoError(err)
+		_, err = s.roomService.GetMessage(expiring.ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+
+		_room, err := s.roomService.GetRoom(room.ID, nil)
+		s.NoError(err)
+		s.Equal(30, *_room.RetentionDays)
+		s.False(_room.LegalHold)
+
+		n, err = reaper.PurgeRetainedMessages(context.Background())
+		s.NoError(err)
+		s.Equal(1, n)
+
+		_, err = s.roomService.GetMessage(old.ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+		_, err = s.roomService.GetMessage(recent.ID, nil)
+		s.NoError(err)
+	})
+
+	s.Run("private rooms and invites", func() {
+		private := &models.Room{
+			Name:       "Messi insiders",
+			MaxMembers: 3,
+			CreatedBy:  creator.ID,
+			Visibility: models.RoomVisibilityPrivate,
+		}
+		err := s.roomService.CreateRoom(private, nil)
+		s.NoError(err)
+
+		rooms, err := s.roomService.GetRooms(repositories.GetRoomsParams{ViewerID: &users[4].ID}, nil)
+		s.NoError(err)
+		s.Len(rooms, 1)
+		s.Equal(room.ID, rooms[0].ID)
+
+		rooms, err = s.roomService.GetRooms(repositories.GetRoomsParams{ViewerID: &creator.ID}, nil)
+		s.NoError(err)
+		s.Len(rooms, 2)
+
+		private.Visibility = "secret"
+		s.ErrorIs(s.roomService.UpdateRoom(private, nil), ErrInvalidVisibility)
+		private.Visibility = models.RoomVisibilityPrivate
+
+		err = s.roomService.JoinRoom(&models.RoomMember{RoomID: private.ID, UserID: users[4].ID}, nil)
+		s.ErrorIs(err, ErrPrivateRoom)
+
+		inviteService := &roomInviteService{
+			conn:                 s.conn,
+			roomService:          s.roomService,
+			RoomInviteRepository: repositories.NewRoomInviteRepository(s.conn),
+		}
+
+		past := time.Now().Add(-time.Minute)
+		err = inviteService.CreateInvite(&models.RoomInvite{RoomID: private.ID, CreatedBy: creator.ID, ExpiresAt: &past}, nil)
+		s.ErrorIs(err, ErrInviteExpiresInPast)
+
+		maxUses := 1
+		invite := &models.RoomInvite{RoomID: private.ID, CreatedBy: creator.ID, MaxUses: &maxUses}
+		err = inviteService.CreateInvite(invite, nil)
+		s.NoError(err)
+		s.NotEmpty(invite.Code)
+
+		member, err := inviteService.AcceptInvite(invite.Code, users[4].ID, nil)
+		s.NoError(err)
+		s.Equal(private.ID, member.RoomID)
+		s.Equal(models.RoomRoleMember, member.Role)
+
+		_, err = inviteService.AcceptInvite(invite.Code, users[4].ID, nil)
+		s.ErrorIs(err, ErrAlreadyMember)
+		_, err = inviteService.AcceptInvite(invite.Code, users[3].ID, nil)
+		s.ErrorIs(err, ErrInviteUsedUp)
+
+		revoked := &models.RoomInvite{RoomID: private.ID, CreatedBy: creator.ID}
+		s.NoError(inviteService.CreateInvite(revoked, nil))
+		s.NoError(inviteService.RevokeInvite(revoked, nil))
+		_, err = inviteService.AcceptInvite(revoked.Code, users[3].ID, nil)
+		s.ErrorIs(err, ErrInviteRevoked)
+
+		_, err = inviteService.AcceptInvite("missing", users[3].ID, nil)
+		s.ErrorIs(err, pgx.ErrNoRows)
+
+		invites, err := inviteService.GetInvites(private.ID, nil)
+		s.NoError(err)
+		s.Len(invites, 2)
+		s.Equal(revoked.ID, invites[0].ID)
+		s.Equal(1, invites[1].Uses)
+
+		s.NoError(s.roomService.DeleteRoom(private.ID, nil))
+	})
+
 	s.Run("delete room", func() {
 		err := s.roomService.DeleteRoom(room.ID, nil)
 		s.NoError(err)
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/utils"
+)
+
+var ErrSavedNoteTooLong = errors.New("note must be at most 1000 characters")
+
+const maxSavedNoteLength = 1000
+
+type savedMessageService struct {
+	conn                   *pgxpool.Pool
+	roomService            RoomService
+	SavedMessageRepository SavedMessageRepository
+}
+
+func NewSavedMessageService(conn *pgxpool.Pool, roomService RoomService) SavedMessageService {
+	return &savedMessageService{
+		conn:                   conn,
+		roomService:            roomService,
+		SavedMessageRepository: repositories.NewSavedMessageRepository(conn),
+	}
+}
+
+// SaveMessage bookmarks a message for saved.UserID, who must be a member of
+// the room it was posted in. Saving a message again replaces its note.
+func (s *savedMessageService) SaveMessage(saved *models.SavedMessage, tx pgx.Tx) error {
+	if len([]rune(saved.Note)) > maxSavedNoteLength {
+		return ErrSavedNoteTooLong
+	}
+
+	message, err := s.roomService.GetMessage(saved.MessageID, tx)
+	if err != nil {
+		return err
+	}
+
+	// messages in rooms the user cannot see are reported as missing
+	_, err = s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+		UserID: saved.UserID,
+		RoomID: message.RoomID,
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.SavedMessageRepository.SaveMessage(saved, tx)
+	if err != nil {
+		return err
+	}
+
+	saved.Message = message
+	return nil
+}
+
+// UnsaveMessage removes the user's bookmark of the message. It returns
+// pgx.ErrNoRows if they had not saved it.
+func (s *savedMessageService) UnsaveMessage(userId, messageId string, tx pgx.Tx) error {
+	deleted, err := s.SavedMessageRepository.DeleteSavedMessage(userId, messageId, tx)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetSavedMessages returns a page of the user's saved messages, most
+// recently saved first. Bookmarks in rooms the user has left are hidden
+// until they rejoin.
+func (s *savedMessageService) GetSavedMessages(params repositories.GetSavedMessagesParams, tx pgx.Tx) (*models.SavedMessagePage, error) {
+	limit := params.Limit
+	params.Limit = limit + 1
+
+	items, err := s.SavedMessageRepository.GetSavedMessages(params, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.SavedMessagePage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = utils.EncodeCursor(utils.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+type SavedMessageRepository interface {
+	SaveMessage(saved *models.SavedMessage, tx pgx.Tx) error
+	DeleteSavedMessage(userId, messageId string, tx pgx.Tx) (bool, error)
+	GetSavedMessages(params repositories.GetSavedMessagesParams, tx pgx.Tx) ([]*models.SavedMessage, error)
+}
+
+type SavedMessageService interface {
+	SaveMessage(saved *models.SavedMessage, tx pgx.Tx) error
+	UnsaveMessage(userId, messageId string, tx pgx.Tx) error
+	GetSavedMessages(params repositories.GetSavedMessagesParams, tx pgx.Tx) (*models.SavedMessagePage, error)
+}
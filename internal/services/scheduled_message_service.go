@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+var (
+	ErrEmptyMessage           = errors.New("message content is required")
+	ErrScheduledInPast        = errors.New("scheduled time must be in the future")
+	ErrScheduledMessageLocked = errors.New("scheduled message has already been sent")
+)
+
+const (
+	dispatchBatchSize = 50
+	dispatchInterval  = 5 * time.Second
+)
+
+type scheduledMessageService struct {
+	conn                       *pgxpool.Pool
+	roomService                RoomService
+	eventService               EventService
+	unfurlService              UnfurlService
+	ScheduledMessageRepository ScheduledMessageRepository
+}
+
+func NewScheduledMessageService(conn *pgxpool.Pool, roomService RoomService, eventService EventService, unfurlService UnfurlService) ScheduledMessageService {
+	return &scheduledMessageService{
+		conn:                       conn,
+		roomService:                roomService,
+		eventService:               eventService,
+		unfurlService:              unfurlService,
+		ScheduledMessageRepository: repositories.NewScheduledMessageRepository(conn),
+	}
+}
+
+func (s *scheduledMessageService) CreateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error {
+	if err := validateScheduledMessage(message); err != nil {
+		return err
+	}
+
+	return s.ScheduledMessageRepository.CreateScheduledMessage(message, tx)
+}
+
+func (s *scheduledMessageService) GetScheduledMessage(id string, tx pgx.Tx) (*models.ScheduledMessage, error) {
+	return s.ScheduledMessageRepository.GetScheduledMessage(id, tx)
+}
+
+// GetScheduledMessages returns the messages a user has scheduled in a room,
+// soonest first.
+func (s *scheduledMessageService) GetScheduledMessages(roomId, userId string, tx pgx.Tx) ([]*models.ScheduledMessage, error) {
+	return s.ScheduledMessageRepository.GetScheduledMessages(roomId, userId, tx)
+}
+
+// UpdateScheduledMessage changes the content or time of a message that has
+// not been sent yet.
+func (s *scheduledMessageService) UpdateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error {
+	if err := validateScheduledMessage(message); err != nil {
+		return err
+	}
+
+	ok, err := s.ScheduledMessageRepository.UpdateScheduledMessage(message, tx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrScheduledMessageLocked
+	}
+
+	return nil
+}
+
+// DeleteScheduledMessage cancels a message that has not been sent yet.
+func (s *scheduledMessageService) DeleteScheduledMessage(id string, tx pgx.Tx) error {
+	ok, err := s.ScheduledMessageRepository.DeleteScheduledMessage(id, tx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrScheduledMessageLocked
+	}
+
+	return nil
+}
+
+// DispatchScheduledMessages posts due scheduled messages until ctx is
+// cancelled.
+func (s *scheduledMessageService) DispatchScheduledMessages(ctx context.Context) {
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			n, err := s.DispatchDue(ctx)
+			if err != nil {
+				log.Printf("failed to dispatch scheduled messages: %v", err)
+				break
+			}
+			if n < dispatchBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue posts one batch of due scheduled messages and returns how
+// many it handled. The batch is locked with FOR UPDATE SKIP LOCKED and each
+// message is created in the same transaction that marks it as sent, so
+// concurrent dispatchers never pick up the same message and a crash before
+// commit leaves it pending to be retried.
+func (s *scheduledMessageService) DispatchDue(ctx context.Context) (int, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(context.Background())
+
+	due, err := s.ScheduledMessageRepository.GetDueScheduledMessages(time.Now(), dispatchBatchSize, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	messages := []*models.RoomMessage{}
+	for _, scheduled := range due {
+		member, err := s.roomService.GetRoomMemberByWhere(repositories.GetRoomMemberByWhereParams{
+			UserID: scheduled.UserID,
+			RoomID: scheduled.RoomID,
+		}, tx)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// the author left the room since scheduling the message
+			scheduled.Status = models.ScheduledMessageStatusFailed
+		case err != nil:
+			return 0, err
+		default:
+			message := &models.RoomMessage{
+				RoomID:       scheduled.RoomID,
+				UserID:       scheduled.UserID,
+				RoomMemberID: member.ID,
+				Content:      scheduled.Content,
+			}
+			err = s.roomService.CreateMessage(message, tx)
+			switch {
+			case errors.Is(err, ErrMessageRejected), errors.Is(err, ErrBlocked), errors.Is(err, ErrMuted),
+				errors.Is(err, ErrMessageTooLong):
+				scheduled.Status = models.ScheduledMessageStatusFailed
+			case err != nil:
+				return 0, err
+			default:
+				scheduled.Status = models.ScheduledMessageStatusSent
+				scheduled.RoomMessageID = message.ID
+				messages = append(messages, message)
+			}
+		}
+
+		err = s.ScheduledMessageRepository.UpdateScheduledMessageStatus(scheduled, tx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		s.eventService.Publish(&models.Event{
+			Type:   models.EventMessageCreated,
+			RoomID: message.RoomID,
+			Data:   message,
+		})
+		s.unfurlService.EnqueueMessage(message)
+	}
+
+	return len(due), nil
+}
+
+func validateScheduledMessage(message *models.ScheduledMessage) error {
+	if strings.TrimSpace(message.Content) == "" {
+		return ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(message.Content) > maxMessageLength {
+		return ErrMessageTooLong
+	}
+	if !message.ScheduledAt.After(time.Now()) {
+		return ErrScheduledInPast
+	}
+	return nil
+}
+
+type ScheduledMessageRepository interface {
+	CreateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error
+	GetScheduledMessage(id string, tx pgx.Tx) (*models.ScheduledMessage, error)
+	GetScheduledMessages(roomId, userId string, tx pgx.Tx) ([]*models.ScheduledMessage, error)
+	UpdateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) (bool, error)
+	DeleteScheduledMessage(id string, tx pgx.Tx) (bool, error)
+	GetDueScheduledMessages(dueBefore time.Time, limit int, tx pgx.Tx) ([]*models.ScheduledMessage, error)
+	UpdateScheduledMessageStatus(message *models.ScheduledMessage, tx pgx.Tx) error
+}
+
+type ScheduledMessageService interface {
+	CreateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error
+	GetScheduledMessage(id string, tx pgx.Tx) (*models.ScheduledMessage, error)
+	GetScheduledMessages(roomId, userId string, tx pgx.Tx) ([]*models.ScheduledMessage, error)
+	UpdateScheduledMessage(message *models.ScheduledMessage, tx pgx.Tx) error
+	DeleteScheduledMessage(id string, tx pgx.Tx) error
+	DispatchScheduledMessages(ctx context.Context)
+	DispatchDue(ctx context.Context) (int, error)
+}
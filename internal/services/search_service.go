@@ -0,0 +1,48 @@
+package services
+
+import (
+	"html"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+)
+
+// the search query marks matches in snippets with these control characters
+// so that message content can be escaped before the <mark> tags are added.
+var snippetReplacer = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+type searchService struct {
+	conn             *pgxpool.Pool
+	SearchRepository SearchRepository
+}
+
+func NewSearchService(conn *pgxpool.Pool) SearchService {
+	return &searchService{
+		conn:             conn,
+		SearchRepository: repositories.NewSearchRepository(conn),
+	}
+}
+
+func (s *searchService) SearchMessages(params repositories.SearchMessagesParams, tx pgx.Tx) ([]*models.MessageSearchResult, error) {
+	results, err := s.SearchRepository.SearchMessages(params, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		result.Snippet = snippetReplacer.Replace(html.EscapeString(result.Snippet))
+	}
+
+	return results, nil
+}
+
+type SearchRepository interface {
+	SearchMessages(params repositories.SearchMessagesParams, tx pgx.Tx) ([]*models.MessageSearchResult, error)
+}
+
+type SearchService interface {
+	SearchMessages(params repositories.SearchMessagesParams, tx pgx.Tx) ([]*models.MessageSearchResult, error)
+}
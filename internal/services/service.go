@@ -2,13 +2,34 @@ package services
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/storage"
+	"github.com/princecee/go_chat/internal/unfurl"
 )
 
 type services struct {
-	userService UserService
-	roomService RoomService
-	authService AuthService
-	conn        *pgxpool.Pool
+	userService       UserService
+	roomService       RoomService
+	authService       AuthService
+	eventService      EventService
+	searchService     SearchService
+	attachmentService AttachmentService
+	unfurlService     UnfurlService
+	scheduledService  ScheduledMessageService
+	reaperService     ReaperService
+	pollService       PollService
+	incomingService   IncomingWebhookService
+	outgoingService   OutgoingWebhookService
+	importService     ImportService
+	blockService      BlockService
+	directService     DirectMessageService
+	moderationService ModerationService
+	draftService      MessageDraftService
+	savedService      SavedMessageService
+	authzService      AuthorizationService
+	inviteService     RoomInviteService
+	joinService       RoomJoinRequestService
+	memberModService  MemberModerationService
+	conn              *pgxpool.Pool
 }
 
 var _services *services
@@ -19,10 +40,30 @@ func New(conn *pgxpool.Pool) *services {
 	}
 
 	uservice := NewUserService(conn)
-	rservice := NewRoomService(conn)
+	mservice := NewModerationService(conn)
+	rservice := NewRoomService(conn, mservice)
 	aservice := NewAuthService(conn)
+	eservice := NewEventService()
+	sservice := NewSearchService(conn)
+	atservice := NewAttachmentService(conn, storage.NewFromEnv(), eservice)
+	ufservice := NewUnfurlService(conn, unfurl.NewHTTPFetcher(), eservice)
+	scservice := NewScheduledMessageService(conn, rservice, eservice, ufservice)
+	rpservice := NewReaperService(conn, eservice, atservice)
+	pservice := NewPollService(conn, rservice)
+	iwservice := NewIncomingWebhookService(conn, uservice, rservice)
+	owservice := NewOutgoingWebhookService(conn, unfurl.NewHTTPFetcher(), eservice)
+	imservice := NewImportService(conn)
+	bservice := NewBlockService(conn)
+	dmservice := NewDirectMessageService(conn)
+	mdservice := NewMessageDraftService(conn, eservice)
+	smservice := NewSavedMessageService(conn, rservice)
+	azservice := NewAuthorizationService(conn)
+	rinservice := NewRoomInviteService(conn, rservice)
+	rjservice := NewRoomJoinRequestService(conn, rservice, azservice)
+	mmservice := NewMemberModerationService(conn, rservice)
 
-	return &services{uservice, rservice, aservice, conn}
+	_services = &services{uservice, rservice, aservice, eservice, sservice, atservice, ufservice, scservice, rpservice, pservice, iwservice, owservice, imservice, bservice, dmservice, mservice, mdservice, smservice, azservice, rinservice, rjservice, mmservice, conn}
+	return _services
 }
 
 func (s *services) GetUserService() UserService {
@@ -37,6 +78,82 @@ func (s *services) GetAuthService() AuthService {
 	return s.authService
 }
 
+func (s *services) GetEventService() EventService {
+	return s.eventService
+}
+
+func (s *services) GetSearchService() SearchService {
+	return s.searchService
+}
+
+func (s *services) GetAttachmentService() AttachmentService {
+	return s.attachmentService
+}
+
+func (s *services) GetUnfurlService() UnfurlService {
+	return s.unfurlService
+}
+
+func (s *services) GetScheduledMessageService() ScheduledMessageService {
+	return s.scheduledService
+}
+
+func (s *services) GetReaperService() ReaperService {
+	return s.reaperService
+}
+
+func (s *services) GetPollService() PollService {
+	return s.pollService
+}
+
+func (s *services) GetIncomingWebhookService() IncomingWebhookService {
+	return s.incomingService
+}
+
+func (s *services) GetOutgoingWebhookService() OutgoingWebhookService {
+	return s.outgoingService
+}
+
+func (s *services) GetImportService() ImportService {
+	return s.importService
+}
+
+func (s *services) GetBlockService() BlockService {
+	return s.blockService
+}
+
+func (s *services) GetDirectMessageService() DirectMessageService {
+	return s.directService
+}
+
+func (s *services) GetModerationService() ModerationService {
+	return s.moderationService
+}
+
+func (s *services) GetMessageDraftService() MessageDraftService {
+	return s.draftService
+}
+
+func (s *services) GetSavedMessageService() SavedMessageService {
+	return s.savedService
+}
+
+func (s *services) GetAuthorizationService() AuthorizationService {
+	return s.authzService
+}
+
+func (s *services) GetRoomInviteService() RoomInviteService {
+	return s.inviteService
+}
+
+func (s *services) GetRoomJoinRequestService() RoomJoinRequestService {
+	return s.joinService
+}
+
+func (s *services) GetMemberModerationService() MemberModerationService {
+	return s.memberModService
+}
+
 func (s *services) GetDB() *pgxpool.Pool {
 	return s.conn
 }
@@ -45,5 +162,24 @@ type Services interface {
 	GetUserService() UserService
 	GetRoomService() RoomService
 	GetAuthService() AuthService
+	GetEventService() EventService
+	GetSearchService() SearchService
+	GetAttachmentService() AttachmentService
+	GetUnfurlService() UnfurlService
+	GetScheduledMessageService() ScheduledMessageService
+	GetReaperService() ReaperService
+	GetPollService() PollService
+	GetIncomingWebhookService() IncomingWebhookService
+	GetOutgoingWebhookService() OutgoingWebhookService
+	GetImportService() ImportService
+	GetBlockService() BlockService
+	GetDirectMessageService() DirectMessageService
+	GetModerationService() ModerationService
+	GetMessageDraftService() MessageDraftService
+	GetSavedMessageService() SavedMessageService
+	GetAuthorizationService() AuthorizationService
+	GetRoomInviteService() RoomInviteService
+	GetRoomJoinRequestService() RoomJoinRequestService
+	GetMemberModerationService() MemberModerationService
 	GetDB() *pgxpool.Pool
 }
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/unfurl"
+)
+
+const (
+	// maxMessagePreviews is the number of links unfurled per message
+	maxMessagePreviews = 3
+	// linkPreviewTTL is how long a fetched preview is reused before the
+	// page is fetched again
+	linkPreviewTTL = 24 * time.Hour
+	unfurlTimeout  = 15 * time.Second
+)
+
+type unfurlService struct {
+	conn                  *pgxpool.Pool
+	unfurler              *unfurl.Unfurler
+	eventService          EventService
+	queue                 chan *models.RoomMessage
+	LinkPreviewRepository LinkPreviewRepository
+}
+
+func NewUnfurlService(conn *pgxpool.Pool, fetcher unfurl.Fetcher, eventService EventService) UnfurlService {
+	return &unfurlService{
+		conn:                  conn,
+		unfurler:              unfurl.New(fetcher),
+		eventService:          eventService,
+		queue:                 make(chan *models.RoomMessage, 100),
+		LinkPreviewRepository: repositories.NewLinkPreviewRepository(conn),
+	}
+}
+
+// EnqueueMessage hands a newly created message to the background unfurler.
+// Messages without links are ignored, and messages that do not fit in the
+// queue are dropped rather than holding up the sender.
+func (s *unfurlService) EnqueueMessage(message *models.RoomMessage) {
+	if len(messageLinks(message)) == 0 {
+		return
+	}
+
+	select {
+	case s.queue <- message:
+	default:
+		log.Printf("unfurl queue full, skipping message %s", message.ID)
+	}
+}
+
+// ProcessMessages unfurls queued messages until ctx is cancelled, pushing
+// an EventMessageUnfurled to the room for each message that got previews.
+func (s *unfurlService) ProcessMessages(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message := <-s.queue:
+			previews, err := s.UnfurlMessage(ctx, message)
+			if err != nil {
+				log.Printf("failed to unfurl message %s: %v", message.ID, err)
+				continue
+			}
+			if len(previews) == 0 {
+				continue
+			}
+
+			s.eventService.Publish(&models.Event{
+				Type:   models.EventMessageUnfurled,
+				RoomID: message.RoomID,
+				Data: &models.MessageUnfurled{
+					MessageID: message.ID,
+					Previews:  previews,
+				},
+			})
+		}
+	}
+}
+
+// UnfurlMessage builds previews for the links in message and records them
+// against it. Links that cannot be previewed are skipped.
+func (s *unfurlService) UnfurlMessage(ctx context.Context, message *models.RoomMessage) ([]*models.LinkPreview, error) {
+	previews := []*models.LinkPreview{}
+	for _, link := range messageLinks(message) {
+		preview, err := s.getLinkPreview(ctx, link)
+		if err != nil {
+			log.Printf("failed to unfurl %s: %v", link, err)
+			continue
+		}
+
+		err = s.LinkPreviewRepository.CreateRoomMessageLinkPreview(message.ID, preview.ID, len(previews), nil)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, preview)
+	}
+
+	return previews, nil
+}
+
+// getLinkPreview returns the cached preview of url, fetching it when it is
+// missing or stale.
+func (s *unfurlService) getLinkPreview(ctx context.Context, url string) (*models.LinkPreview, error) {
+	cached, err := s.LinkPreviewRepository.GetLinkPreviewByURL(url, nil)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if cached != nil && time.Since(cached.UpdatedAt) < linkPreviewTTL {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, unfurlTimeout)
+	defer cancel()
+
+	preview, err := s.unfurler.Unfurl(ctx, url)
+	if err != nil {
+		// a stale preview is better than none if the page is unreachable
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	err = s.LinkPreviewRepository.UpsertLinkPreview(preview, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// LoadMessagePreviews populates the Previews of each message.
+func (s *unfurlService) LoadMessagePreviews(messages []*models.RoomMessage, tx pgx.Tx) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	messageIds := []string{}
+	for _, message := range messages {
+		messageIds = append(messageIds, message.ID)
+	}
+
+	previews, err := s.LinkPreviewRepository.GetMessagesLinkPreviews(messageIds, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.Previews = previews[message.ID]
+	}
+
+	return nil
+}
+
+// messageLinks returns the distinct http(s) links of a message, in order,
+// as found by the Markdown renderer.
+func messageLinks(message *models.RoomMessage) []string {
+	links := []string{}
+	seen := map[string]bool{}
+	for _, entity := range message.Entities {
+		if entity.Type != models.EntityLink || seen[entity.URL] {
+			continue
+		}
+		if len(links) == maxMessagePreviews {
+			break
+		}
+
+		// mailto links have nothing to preview
+		if !strings.HasPrefix(entity.URL, "http://") && !strings.HasPrefix(entity.URL, "https://") {
+			continue
+		}
+
+		seen[entity.URL] = true
+		links = append(links, entity.URL)
+	}
+	return links
+}
+
+type LinkPreviewRepository interface {
+	GetLinkPreviewByURL(url string, tx pgx.Tx) (*models.LinkPreview, error)
+	UpsertLinkPreview(preview *models.LinkPreview, tx pgx.Tx) error
+	CreateRoomMessageLinkPreview(messageId, previewId string, position int, tx pgx.Tx) error
+	GetMessagesLinkPreviews(messageIds []string, tx pgx.Tx) (map[string][]*models.LinkPreview, error)
+}
+
+type UnfurlService interface {
+	EnqueueMessage(message *models.RoomMessage)
+	ProcessMessages(ctx context.Context)
+	UnfurlMessage(ctx context.Context, message *models.RoomMessage) ([]*models.LinkPreview, error)
+	LoadMessagePreviews(messages []*models.RoomMessage, tx pgx.Tx) error
+}
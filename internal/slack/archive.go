@@ -0,0 +1,232 @@
+// Package slack reads Slack workspace export archives and imports their
+// public channels into rooms.
+package slack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotExport = errors.New("archive is not a slack export: channels.json not found")
+
+var errFileNotFound = errors.New("file not found in archive")
+
+type Profile struct {
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	RealName    string `json:"real_name"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+}
+
+type User struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	RealName string  `json:"real_name"`
+	Deleted  bool    `json:"deleted"`
+	IsBot    bool    `json:"is_bot"`
+	Profile  Profile `json:"profile"`
+}
+
+// Names returns the first and last name to give the user, falling back
+// from the profile's names to the real name, display name and handle.
+func (u *User) Names() (string, string) {
+	if u.Profile.FirstName != "" || u.Profile.LastName != "" {
+		return u.Profile.FirstName, u.Profile.LastName
+	}
+
+	for _, name := range []string{u.Profile.RealName, u.RealName, u.Profile.DisplayName, u.Name} {
+		if name = strings.TrimSpace(name); name != "" {
+			first, last, _ := strings.Cut(name, " ")
+			return first, strings.TrimSpace(last)
+		}
+	}
+
+	return u.ID, ""
+}
+
+type Text struct {
+	Value string `json:"value"`
+}
+
+type Channel struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Created    int64    `json:"created"`
+	Creator    string   `json:"creator"`
+	IsArchived bool     `json:"is_archived"`
+	Members    []string `json:"members"`
+	Topic      Text     `json:"topic"`
+	Purpose    Text     `json:"purpose"`
+}
+
+type File struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type BotProfile struct {
+	Name string `json:"name"`
+}
+
+type Message struct {
+	Type        string      `json:"type"`
+	Subtype     string      `json:"subtype"`
+	TS          string      `json:"ts"`
+	User        string      `json:"user"`
+	BotID       string      `json:"bot_id"`
+	Username    string      `json:"username"`
+	Text        string      `json:"text"`
+	Files       []File      `json:"files"`
+	UserProfile *Profile    `json:"user_profile"`
+	BotProfile  *BotProfile `json:"bot_profile"`
+}
+
+// AuthorID returns the ID of the user or, for integrations posting without
+// one, the bot that sent the message.
+func (m *Message) AuthorID() string {
+	if m.User != "" {
+		return m.User
+	}
+	return m.BotID
+}
+
+// Time returns when the message was sent. Slack timestamps are Unix seconds
+// with a microsecond fraction, which also makes them unique per channel.
+func (m *Message) Time() (time.Time, error) {
+	secs, frac, _ := strings.Cut(m.TS, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid message timestamp %q", m.TS)
+	}
+
+	var usec int64
+	if frac != "" {
+		frac = (frac + "000000")[:6]
+		usec, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid message timestamp %q", m.TS)
+		}
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC(), nil
+}
+
+// Archive is an opened export. Users and channels are read up front;
+// channel history is read one channel at a time by Messages.
+type Archive struct {
+	zip      *zip.Reader
+	closer   func() error
+	Users    []*User
+	Channels []*Channel
+}
+
+// OpenArchive opens the export zip at name. The archive must be closed.
+func OpenArchive(name string) (*Archive, error) {
+	rc, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := ReadArchive(&rc.Reader)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	archive.closer = rc.Close
+	return archive, nil
+}
+
+// ReadArchive reads an export from r.
+func ReadArchive(r *zip.Reader) (*Archive, error) {
+	archive := &Archive{zip: r}
+
+	err := archive.decode("channels.json", &archive.Channels)
+	if errors.Is(err, errFileNotFound) {
+		return nil, ErrNotExport
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// users.json is missing from exports of workspaces that restrict
+	// member profiles; authors are then named after their messages
+	err = archive.decode("users.json", &archive.Users)
+	if err != nil && !errors.Is(err, errFileNotFound) {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer()
+}
+
+// Messages returns the history of channel, oldest first.
+func (a *Archive) Messages(channel *Channel) ([]*Message, error) {
+	files := []*zip.File{}
+	for _, f := range a.zip.File {
+		if path.Dir(f.Name) == channel.Name && path.Ext(f.Name) == ".json" {
+			files = append(files, f)
+		}
+	}
+	// day files are named YYYY-MM-DD.json
+	slices.SortFunc(files, func(a, b *zip.File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	messages := []*Message{}
+	for _, f := range files {
+		var day []*Message
+		if err := decodeFile(f, &day); err != nil {
+			return nil, err
+		}
+		for _, message := range day {
+			if message.Type == "message" && message.TS != "" {
+				messages = append(messages, message)
+			}
+		}
+	}
+
+	slices.SortStableFunc(messages, func(a, b *Message) int {
+		at, _ := a.Time()
+		bt, _ := b.Time()
+		return at.Compare(bt)
+	})
+
+	return messages, nil
+}
+
+func (a *Archive) decode(name string, v any) error {
+	for _, f := range a.zip.File {
+		if f.Name == name {
+			return decodeFile(f, v)
+		}
+	}
+	return errFileNotFound
+}
+
+func decodeFile(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
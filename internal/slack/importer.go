@@ -0,0 +1,516 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+)
+
+const (
+	StatusCreated  = "created"
+	StatusMatched  = "matched"
+	StatusExisting = "existing"
+)
+
+const (
+	// defaultMaxMembers leaves imported rooms room to grow; rooms with more
+	// participants are sized to fit them
+	defaultMaxMembers = 100
+	messageBatchSize  = 500
+	maxNameLength     = 50
+	maxRoomNameLength = 255
+)
+
+// importedSubtypes are the message subtypes carrying conversation. Others,
+// such as joins and topic changes, are system notices and are skipped.
+var importedSubtypes = map[string]bool{
+	"":                 true,
+	"bot_message":      true,
+	"me_message":       true,
+	"file_share":       true,
+	"thread_broadcast": true,
+}
+
+// Mapping is how one Slack user or channel was imported.
+type Mapping struct {
+	SlackID string
+	Name    string
+	LocalID string
+	// Status is StatusCreated for new rows, StatusMatched for users matched
+	// to an existing account by email and StatusExisting for objects
+	// imported by an earlier run.
+	Status string
+}
+
+type ChannelReport struct {
+	Mapping
+	// Imported counts messages imported by this run, Skipped those imported
+	// by an earlier one and Ignored system notices and empty messages.
+	Imported int
+	Skipped  int
+	Ignored  int
+}
+
+type Report struct {
+	Users    []*Mapping
+	Channels []*ChannelReport
+}
+
+// Write writes the report as aligned tables.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "USERS")
+	fmt.Fprintln(tw, "SLACK ID\tNAME\tID\tSTATUS")
+	for _, user := range r.Users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", user.SlackID, user.Name, user.LocalID, user.Status)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "CHANNELS")
+	fmt.Fprintln(tw, "SLACK ID\tNAME\tROOM ID\tSTATUS\tIMPORTED\tSKIPPED\tIGNORED")
+	for _, channel := range r.Channels {
+		fmt.Fprintf(tw, "%s\t#%s\t%s\t%s\t%d\t%d\t%d\n", channel.SlackID, channel.Name, channel.LocalID, channel.Status, channel.Imported, channel.Skipped, channel.Ignored)
+	}
+
+	return tw.Flush()
+}
+
+// Importer imports exports of one Slack workspace. Every imported user,
+// channel and message is recorded in the import mappings under the
+// workspace, so running an import again only adds what is new.
+type Importer struct {
+	services services.Services
+	source   string
+	users    map[string]*User
+	userIDs  map[string]string
+	report   *Report
+}
+
+func NewImporter(s services.Services, workspace string) *Importer {
+	return &Importer{
+		services: s,
+		source:   "slack:" + workspace,
+	}
+}
+
+// Import imports the public channels of archive with their members and
+// history. Users become placeholder accounts without credentials unless
+// their email matches an existing account.
+func (i *Importer) Import(archive *Archive) (*Report, error) {
+	i.users = map[string]*User{}
+	i.userIDs = map[string]string{}
+	i.report = &Report{Users: []*Mapping{}, Channels: []*ChannelReport{}}
+
+	for _, user := range archive.Users {
+		i.users[user.ID] = user
+	}
+
+	for _, user := range archive.Users {
+		if _, err := i.importUser(user); err != nil {
+			return nil, fmt.Errorf("user %s: %w", user.ID, err)
+		}
+	}
+
+	for _, channel := range archive.Channels {
+		messages, err := archive.Messages(channel)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channel.Name, err)
+		}
+
+		if err := i.importChannel(channel, messages); err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channel.Name, err)
+		}
+	}
+
+	return i.report, nil
+}
+
+// importUser returns the local ID of user, creating or matching an account
+// the first time it is seen.
+func (i *Importer) importUser(user *User) (string, error) {
+	if id, ok := i.userIDs[user.ID]; ok {
+		return id, nil
+	}
+
+	userService := i.services.GetUserService()
+	importService := i.services.GetImportService()
+	first, last := user.Names()
+	mapping := &Mapping{SlackID: user.ID, Name: strings.TrimSpace(first + " " + last)}
+
+	// the placeholder and its mapping are saved together so an import that
+	// fails in between does not leave an account the next run cannot find
+	tx, err := i.services.GetDB().Begin(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(context.Background())
+
+	existing, err := importService.GetImportMapping(i.source, models.ImportKindUser, user.ID, tx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+	if existing != nil {
+		_, err := userService.GetUser(repositories.GetUserParams{ID: existing.LocalID}, tx)
+		if err == nil {
+			mapping.LocalID = existing.LocalID
+			mapping.Status = StatusExisting
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	if mapping.LocalID == "" && user.Profile.Email != "" {
+		local, err := userService.GetUser(repositories.GetUserParams{Email: user.Profile.Email}, tx)
+		if err == nil {
+			mapping.LocalID = local.ID
+			mapping.Status = StatusMatched
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	if mapping.LocalID == "" {
+		// placeholders have no auth so nobody can sign in as them until
+		// the account is claimed; the address is on a reserved domain so
+		// it never matches a real sign-up
+		local := &models.User{
+			FirstName: truncate(first, maxNameLength),
+			LastName:  truncate(last, maxNameLength),
+			Email:     fmt.Sprintf("slack-%s@import.invalid", strings.ToLower(user.ID)),
+		}
+		if err := userService.CreateUser(local, tx); err != nil {
+			return "", err
+		}
+		mapping.LocalID = local.ID
+		mapping.Status = StatusCreated
+	}
+
+	if mapping.Status != StatusExisting {
+		err = importService.SaveImportMapping(&models.ImportMapping{
+			Source:     i.source,
+			Kind:       models.ImportKindUser,
+			ExternalID: user.ID,
+			LocalID:    mapping.LocalID,
+		}, tx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return "", err
+	}
+
+	i.userIDs[user.ID] = mapping.LocalID
+	i.report.Users = append(i.report.Users, mapping)
+	return mapping.LocalID, nil
+}
+
+// author returns the user who sent message, naming users missing from the
+// export and bots after the message itself.
+func (i *Importer) author(message *Message) *User {
+	id := message.AuthorID()
+	if user, ok := i.users[id]; ok {
+		return user
+	}
+
+	user := &User{ID: id, Name: message.Username}
+	if message.UserProfile != nil {
+		user.Profile = *message.UserProfile
+	}
+	if message.BotProfile != nil && user.Name == "" {
+		user.Name = message.BotProfile.Name
+	}
+	i.users[id] = user
+	return user
+}
+
+func (i *Importer) importChannel(channel *Channel, messages []*Message) error {
+	report := &ChannelReport{Mapping: Mapping{SlackID: channel.ID, Name: channel.Name}}
+	i.report.Channels = append(i.report.Channels, report)
+
+	// everyone who is or was in the channel becomes a member, creator
+	// first so they own the room
+	participants := []string{}
+	seen := map[string]bool{}
+	addParticipant := func(user *User) error {
+		if user.ID == "" || seen[user.ID] {
+			return nil
+		}
+		seen[user.ID] = true
+
+		id, err := i.importUser(user)
+		if err != nil {
+			return err
+		}
+		participants = append(participants, id)
+		return nil
+	}
+
+	for _, id := range append([]string{channel.Creator}, channel.Members...) {
+		user, ok := i.users[id]
+		if !ok {
+			user = &User{ID: id}
+			i.users[id] = user
+		}
+		if err := addParticipant(user); err != nil {
+			return err
+		}
+	}
+
+	imported := []*Message{}
+	for _, message := range messages {
+		if !importedSubtypes[message.Subtype] || message.AuthorID() == "" {
+			report.Ignored++
+			continue
+		}
+		if err := addParticipant(i.author(message)); err != nil {
+			return err
+		}
+		imported = append(imported, message)
+	}
+
+	if len(participants) == 0 {
+		return nil
+	}
+
+	tx, err := i.services.GetDB().Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	room, err := i.room(channel, participants, report, tx)
+	if err != nil {
+		return err
+	}
+
+	members, err := i.members(room, participants, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(imported); start += messageBatchSize {
+		end := min(start+messageBatchSize, len(imported))
+		if err := i.importMessages(channel, room, members, imported[start:end], report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// room returns the room channel was imported as, creating it if it was
+// never imported or has since been deleted.
+func (i *Importer) room(channel *Channel, participants []string, report *ChannelReport, tx pgx.Tx) (*models.Room, error) {
+	roomService := i.services.GetRoomService()
+	importService := i.services.GetImportService()
+
+	existing, err := importService.GetImportMapping(i.source, models.ImportKindChannel, channel.ID, tx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if existing != nil {
+		room, err := roomService.GetRoom(existing.LocalID, tx)
+		if err == nil {
+			report.LocalID = room.ID
+			report.Status = StatusExisting
+			return room, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		// the room's messages went with it
+		err = importService.DeleteImportMappings(i.source, models.ImportKindMessage, channel.ID, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	description := channel.Purpose.Value
+	if description == "" {
+		description = channel.Topic.Value
+	}
+
+	room := &models.Room{
+		Name:        truncate(channel.Name, maxRoomNameLength),
+		Description: ToMarkdown(description, nil),
+		MaxMembers:  max(len(participants), defaultMaxMembers),
+		CreatedBy:   participants[0],
+	}
+	if err := roomService.CreateRoom(room, tx); err != nil {
+		return nil, err
+	}
+
+	err = importService.SaveImportMapping(&models.ImportMapping{
+		Source:     i.source,
+		Kind:       models.ImportKindChannel,
+		ExternalID: channel.ID,
+		LocalID:    room.ID,
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	report.LocalID = room.ID
+	report.Status = StatusCreated
+	return room, nil
+}
+
+// members adds the participants missing from room, growing it if needed,
+// and returns the room member ID of every participant by user ID.
+func (i *Importer) members(room *models.Room, participants []string, tx pgx.Tx) (map[string]string, error) {
+	roomService := i.services.GetRoomService()
+
+	current, err := roomService.GetRoomMembers(repositories.GetRoomMembersParams{RoomID: &room.ID}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]string{}
+	for _, member := range current {
+		members[member.UserID] = member.ID
+	}
+
+	missing := []string{}
+	for _, userId := range participants {
+		if _, ok := members[userId]; !ok {
+			missing = append(missing, userId)
+		}
+	}
+
+	if needed := len(current) + len(missing); needed > room.MaxMembers {
+		room.MaxMembers = needed
+		if err := roomService.UpdateRoom(room, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, userId := range missing {
+		member := &models.RoomMember{RoomID: room.ID, UserID: userId}
+		if err := roomService.AdmitMember(member, tx); err != nil {
+			return nil, err
+		}
+		members[userId] = member.ID
+	}
+
+	return members, nil
+}
+
+// importMessages imports a batch of messages in one transaction, skipping
+// those imported before.
+func (i *Importer) importMessages(channel *Channel, room *models.Room, members map[string]string, messages []*Message, report *ChannelReport) error {
+	roomService := i.services.GetRoomService()
+	importService := i.services.GetImportService()
+
+	tx, err := i.services.GetDB().Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	imported, skipped, ignored := 0, 0, 0
+	for _, message := range messages {
+		// ts is unique within a channel
+		externalId := channel.ID + ":" + message.TS
+
+		_, err := importService.GetImportMapping(i.source, models.ImportKindMessage, externalId, tx)
+		if err == nil {
+			skipped++
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		content := i.content(message)
+		createdAt, err := message.Time()
+		if content == "" || err != nil {
+			ignored++
+			continue
+		}
+
+		userId := i.userIDs[message.AuthorID()]
+		local := &models.RoomMessage{
+			RoomID:       room.ID,
+			RoomMemberID: members[userId],
+			UserID:       userId,
+			Content:      content,
+			CreatedAt:    createdAt,
+		}
+		if err := roomService.ImportMessage(local, tx); err != nil {
+			return err
+		}
+
+		err = importService.SaveImportMapping(&models.ImportMapping{
+			Source:     i.source,
+			Kind:       models.ImportKindMessage,
+			ExternalID: externalId,
+			ParentID:   channel.ID,
+			LocalID:    local.ID,
+		}, tx)
+		if err != nil {
+			return err
+		}
+		imported++
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+
+	report.Imported += imported
+	report.Skipped += skipped
+	report.Ignored += ignored
+	return nil
+}
+
+// content returns the Markdown content of message, listing its files by
+// name since their contents stay behind in Slack.
+func (i *Importer) content(message *Message) string {
+	text := strings.TrimSpace(ToMarkdown(message.Text, func(slackID string) (string, bool) {
+		id, ok := i.userIDs[slackID]
+		return id, ok
+	}))
+	if message.Subtype == "me_message" && text != "" {
+		text = "_" + text + "_"
+	}
+
+	lines := []string{}
+	if text != "" {
+		lines = append(lines, text)
+	}
+	for _, file := range message.Files {
+		name := file.Title
+		if name == "" {
+			name = file.Name
+		}
+		if name != "" {
+			lines = append(lines, "[file: "+name+"]")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
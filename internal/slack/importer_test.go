@@ -0,0 +1,165 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/princecee/go_chat/internal/db/repositories"
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/princecee/go_chat/internal/services"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImporterTestSuite struct {
+	suite.Suite
+	conn     *pgxpool.Pool
+	services services.Services
+}
+
+func (s *ImporterTestSuite) SetupSuite() {
+	if err := godotenv.Load("../../.env"); err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(context.Background(), os.Getenv("DSN"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s.conn = conn
+	s.services = services.New(conn)
+}
+
+func (s *ImporterTestSuite) TearDownSuite() {
+	defer s.conn.Close()
+
+	teardownQuery := `
+		DELETE FROM import_mappings;
+		DELETE FROM room_messages;
+		DELETE FROM room_members;
+		DELETE FROM rooms;
+		DELETE FROM users;
+	`
+
+	_, err := s.conn.Exec(context.Background(), teardownQuery)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			panic(err)
+		}
+	}
+}
+
+func (s *ImporterTestSuite) TestImport() {
+	existing := &models.User{FirstName: "Ada", LastName: "L", Email: "ada@example.com"}
+	s.Require().NoError(s.services.GetUserService().CreateUser(existing, nil))
+
+	archive, err := ReadArchive(newExport(s.T(), map[string]string{
+		"channels.json": `[{"id":"C1","name":"general","creator":"U1","members":["U1","U2"],"purpose":{"value":"Company wide"}}]`,
+		"users.json":    `[{"id":"U1","name":"ada","profile":{"first_name":"Ada","last_name":"Lovelace","email":"ada@example.com"}},{"id":"U2","name":"alan","real_name":"Alan Turing"}]`,
+		"general/2024-03-01.json": `[
+			{"type":"message","user":"U1","text":"hello <@U2>","ts":"1709294400.000100"},
+			{"type":"message","subtype":"channel_join","user":"U2","text":"<@U2> has joined the channel","ts":"1709294401.000100"},
+			{"type":"message","subtype":"bot_message","bot_id":"B1","username":"deploybot","text":"deployed","ts":"1709294402.000100"},
+			{"type":"message","user":"U2","text":"","files":[{"name":"plan.pdf","title":"Plan"}],"ts":"1709294403.000100"}
+		]`,
+	}))
+	s.Require().NoError(err)
+
+	var roomId string
+
+	s.Run("first import", func() {
+		report, err := NewImporter(s.services, "test").Import(archive)
+		s.Require().NoError(err)
+
+		statuses := map[string]string{}
+		for _, user := range report.Users {
+			statuses[user.SlackID] = user.Status
+		}
+		s.Equal(map[string]string{"U1": StatusMatched, "U2": StatusCreated, "B1": StatusCreated}, statuses)
+		s.Equal(existing.ID, report.Users[0].LocalID)
+
+		s.Require().Len(report.Channels, 1)
+		channel := report.Channels[0]
+		s.Equal(StatusCreated, channel.Status)
+		s.Equal(3, channel.Imported)
+		s.Equal(0, channel.Skipped)
+		s.Equal(1, channel.Ignored)
+		roomId = channel.LocalID
+
+		room, err := s.services.GetRoomService().GetRoom(roomId, nil)
+		s.NoError(err)
+		s.Equal("general", room.Name)
+		s.Equal("Company wide", room.Description)
+		s.Equal(existing.ID, room.CreatedBy)
+
+		members, err := s.services.GetRoomService().GetRoomMembers(repositories.GetRoomMembersParams{RoomID: &roomId}, nil)
+		s.NoError(err)
+		s.Len(members, 3)
+
+		messages, err := s.services.GetRoomService().GetMessages(repositories.GetRoomMessagesParams{RoomID: &roomId}, nil)
+		s.NoError(err)
+		s.Require().Len(messages, 3)
+
+		var alan string
+		for _, user := range report.Users {
+			if user.SlackID == "U2" {
+				alan = user.LocalID
+			}
+		}
+
+		byContent := map[string]*models.RoomMessage{}
+		for _, message := range messages {
+			byContent[message.Content] = message
+		}
+		s.Contains(byContent, "hello <@"+alan+">")
+		s.Contains(byContent, "deployed")
+		s.Contains(byContent, "[file: Plan]")
+		s.True(time.Date(2024, 3, 1, 12, 0, 0, 100000, time.UTC).Equal(byContent["hello <@"+alan+">"].CreatedAt))
+
+		var buf bytes.Buffer
+		s.NoError(report.Write(&buf))
+		s.Contains(buf.String(), "#general")
+	})
+
+	s.Run("import again", func() {
+		report, err := NewImporter(s.services, "test").Import(archive)
+		s.Require().NoError(err)
+
+		for _, user := range report.Users {
+			s.Equal(StatusExisting, user.Status)
+		}
+		channel := report.Channels[0]
+		s.Equal(StatusExisting, channel.Status)
+		s.Equal(roomId, channel.LocalID)
+		s.Equal(0, channel.Imported)
+		s.Equal(3, channel.Skipped)
+
+		messages, err := s.services.GetRoomService().GetMessages(repositories.GetRoomMessagesParams{RoomID: &roomId}, nil)
+		s.NoError(err)
+		s.Len(messages, 3)
+	})
+
+	s.Run("reimport deleted room", func() {
+		s.Require().NoError(s.services.GetRoomService().DeleteRoom(roomId, nil))
+
+		report, err := NewImporter(s.services, "test").Import(archive)
+		s.Require().NoError(err)
+
+		channel := report.Channels[0]
+		s.Equal(StatusCreated, channel.Status)
+		s.NotEqual(roomId, channel.LocalID)
+		s.Equal(3, channel.Imported)
+	})
+}
+
+func TestImporter(t *testing.T) {
+	suite.Run(t, new(ImporterTestSuite))
+}
@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	linkPattern    = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]+))?>`)
+	userPattern    = regexp.MustCompile(`<@([UW][A-Z0-9]+)(?:\|([^>]+))?>`)
+	channelPattern = regexp.MustCompile(`<#([CG][A-Z0-9]+)(?:\|([^>]*))?>`)
+	specialPattern = regexp.MustCompile(`<!(here|channel|everyone)(?:\|[^>]*)?>|<!subteam\^[A-Z0-9]+\|([^>]+)>`)
+)
+
+// ToMarkdown converts Slack message formatting to Markdown: angle-bracket
+// links and the HTML entities Slack requires senders to escape.
+//
+// When users is set, mentions are converted too. A user mention becomes a
+// mention of the local user users returns, or plain @name text when it
+// returns false; channel, broadcast and group mentions become plain text.
+func ToMarkdown(text string, users func(slackID string) (string, bool)) string {
+	text = linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		m := linkPattern.FindStringSubmatch(link)
+		if m[2] == "" {
+			return m[1]
+		}
+		return "[" + m[2] + "](" + m[1] + ")"
+	})
+
+	if users != nil {
+		text = userPattern.ReplaceAllStringFunc(text, func(mention string) string {
+			m := userPattern.FindStringSubmatch(mention)
+			if id, ok := users(m[1]); ok {
+				return "<@" + id + ">"
+			}
+			if m[2] != "" {
+				return "@" + m[2]
+			}
+			return "@" + m[1]
+		})
+
+		text = channelPattern.ReplaceAllStringFunc(text, func(mention string) string {
+			m := channelPattern.FindStringSubmatch(mention)
+			if m[2] != "" {
+				return "#" + m[2]
+			}
+			return "#" + m[1]
+		})
+
+		text = specialPattern.ReplaceAllStringFunc(text, func(mention string) string {
+			m := specialPattern.FindStringSubmatch(mention)
+			if m[1] != "" {
+				return "@" + m[1]
+			}
+			return m[2]
+		})
+	}
+
+	return strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">").Replace(text)
+}
@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExport builds an export zip from file names and contents.
+func newExport(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func TestReadArchive(t *testing.T) {
+	r := newExport(t, map[string]string{
+		"channels.json": `[{"id":"C1","name":"general","creator":"U1","members":["U1","U2"],"purpose":{"value":"Company wide"}}]`,
+		"users.json":    `[{"id":"U1","name":"ada","profile":{"first_name":"Ada","last_name":"Lovelace","email":"ada@example.com"}},{"id":"U2","name":"alan","real_name":"Alan Mathison Turing"}]`,
+		"general/2024-03-02.json": `[
+			{"type":"message","user":"U2","text":"second day","ts":"1709380800.000200"}
+		]`,
+		"general/2024-03-01.json": `[
+			{"type":"message","user":"U1","text":"later","ts":"1709294460.000100"},
+			{"type":"message","user":"U1","text":"first","ts":"1709294400.000100"},
+			{"type":"message","subtype":"channel_join","user":"U2","text":"<@U2> has joined the channel","ts":"1709294401.000100"}
+		]`,
+		"random/2024-03-01.json": `[{"type":"message","user":"U1","text":"elsewhere","ts":"1709294400.000100"}]`,
+	})
+
+	archive, err := ReadArchive(r)
+	require.NoError(t, err)
+	require.Len(t, archive.Channels, 1)
+	require.Len(t, archive.Users, 2)
+	assert.Equal(t, "Company wide", archive.Channels[0].Purpose.Value)
+
+	first, last := archive.Users[0].Names()
+	assert.Equal(t, "Ada", first)
+	assert.Equal(t, "Lovelace", last)
+	first, last = archive.Users[1].Names()
+	assert.Equal(t, "Alan", first)
+	assert.Equal(t, "Mathison Turing", last)
+
+	messages, err := archive.Messages(archive.Channels[0])
+	require.NoError(t, err)
+	texts := []string{}
+	for _, message := range messages {
+		texts = append(texts, message.Text)
+	}
+	assert.Equal(t, []string{"first", "<@U2> has joined the channel", "later", "second day"}, texts)
+
+	ts, err := messages[0].Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 100000, time.UTC), ts)
+}
+
+func TestReadArchiveNotExport(t *testing.T) {
+	_, err := ReadArchive(newExport(t, map[string]string{"readme.txt": "hi"}))
+	assert.ErrorIs(t, err, ErrNotExport)
+}
+
+func TestMessageTime(t *testing.T) {
+	m := &Message{TS: "1700000000.5"}
+	ts, err := m.Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 500000000).UTC(), ts)
+
+	m.TS = "not-a-ts"
+	_, err = m.Time()
+	assert.Error(t, err)
+}
+
+func TestToMarkdown(t *testing.T) {
+	users := func(id string) (string, bool) {
+		if id == "U1" {
+			return "3f0c2d5e-2d1c-4c57-9a43-0d1b8c7c6f10", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		users func(string) (string, bool)
+		want  string
+	}{
+		{"link", "see <https://example.com|docs> &amp; more", nil, "see [docs](https://example.com) & more"},
+		{"mentions untouched without users", "<@U1> <!channel>", nil, "<@U1> <!channel>"},
+		{"known user", "hi <@U1>", users, "hi <@3f0c2d5e-2d1c-4c57-9a43-0d1b8c7c6f10>"},
+		{"unknown user with label", "hi <@U2|alan>", users, "hi @alan"},
+		{"unknown user", "hi <@U2>", users, "hi @U2"},
+		{"channel", "see <#C1|general>", users, "see #general"},
+		{"broadcast", "<!here> standup", users, "@here standup"},
+		{"user group", "<!subteam^S1|@oncall> help", users, "@oncall help"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToMarkdown(tt.text, tt.users))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore persists opaque binary objects, such as message attachments,
+// under caller-chosen keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv builds the blob store selected by BLOB_STORE. "s3" uses an
+// S3-compatible service configured through the S3_* variables, anything
+// else stores blobs on the local filesystem under BLOB_STORE_PATH.
+func NewFromEnv() BlobStore {
+	if os.Getenv("BLOB_STORE") == "s3" {
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	}
+
+	root := os.Getenv("BLOB_STORE_PATH")
+	if root == "" {
+		root = "uploads"
+	}
+	return NewLocalStore(root)
+}
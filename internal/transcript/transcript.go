@@ -0,0 +1,152 @@
+// Package transcript writes room transcripts as JSON Lines, CSV or plain
+// text. Writers are fed one entry at a time so a transcript can be streamed
+// without holding the whole room in memory.
+package transcript
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/princecee/go_chat/internal/models"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+	FormatText  = "text"
+)
+
+var ErrUnknownFormat = errors.New("transcript format must be one of jsonl, csv or text")
+
+// TextTimeLayout is how timestamps are written in CSV and text transcripts.
+// JSON Lines transcripts use RFC 3339.
+const TextTimeLayout = "2006-01-02 15:04:05 -0700"
+
+var csvHeader = []string{"message_id", "created_at", "author_id", "author_name", "content"}
+
+// Writer writes transcript entries in a single format. Entries may be
+// buffered until Flush is called.
+type Writer interface {
+	Write(entry *models.TranscriptEntry) error
+	Flush() error
+}
+
+// NewWriter returns a Writer for format that writes to w with timestamps in
+// loc.
+func NewWriter(format string, w io.Writer, loc *time.Location) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		enc := json.NewEncoder(bw)
+		enc.SetEscapeHTML(false)
+		return &jsonlWriter{w: bw, enc: enc, loc: loc}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), loc: loc}, nil
+	case FormatText:
+		return &textWriter{w: bufio.NewWriter(w), loc: loc}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType returns the media type of transcripts in format.
+func ContentType(format string) string {
+	switch format {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Extension returns the file extension of transcripts in format.
+func Extension(format string) string {
+	switch format {
+	case FormatJSONL:
+		return "jsonl"
+	case FormatCSV:
+		return "csv"
+	default:
+		return "txt"
+	}
+}
+
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	loc *time.Location
+}
+
+func (w *jsonlWriter) Write(entry *models.TranscriptEntry) error {
+	e := *entry
+	e.CreatedAt = e.CreatedAt.In(w.loc)
+	return w.enc.Encode(&e)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.w.Flush()
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	loc           *time.Location
+	headerWritten bool
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.w.Write(csvHeader)
+}
+
+func (w *csvWriter) Write(entry *models.TranscriptEntry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.w.Write([]string{
+		entry.MessageID,
+		entry.CreatedAt.In(w.loc).Format(TextTimeLayout),
+		entry.AuthorID,
+		entry.AuthorName,
+		entry.Content,
+	})
+}
+
+// Flush writes the header too, so an empty transcript is still a valid
+// CSV file with named columns.
+func (w *csvWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type textWriter struct {
+	w   *bufio.Writer
+	loc *time.Location
+}
+
+// Write writes an entry as "[time] Author: content". Continuation lines of
+// multi-line messages are indented so every entry starts on a line of its
+// own.
+func (w *textWriter) Write(entry *models.TranscriptEntry) error {
+	content := strings.ReplaceAll(entry.Content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\n", "\n    ")
+	_, err := fmt.Fprintf(w.w, "[%s] %s: %s\n", entry.CreatedAt.In(w.loc).Format(TextTimeLayout), entry.AuthorName, content)
+	return err
+}
+
+func (w *textWriter) Flush() error {
+	return w.w.Flush()
+}
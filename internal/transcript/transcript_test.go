@@ -0,0 +1,88 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/princecee/go_chat/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entries() []*models.TranscriptEntry {
+	return []*models.TranscriptEntry{
+		{
+			MessageID:  "m1",
+			CreatedAt:  time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC),
+			AuthorID:   "u1",
+			AuthorName: "Ada Lovelace",
+			Content:    "hello <world>",
+		},
+		{
+			MessageID:  "m2",
+			CreatedAt:  time.Date(2024, 3, 1, 23, 31, 5, 0, time.UTC),
+			AuthorID:   "u2",
+			AuthorName: "Alan Turing",
+			Content:    "line one\r\nline two, \"quoted\"",
+		},
+	}
+}
+
+func write(t *testing.T, format string, loc *time.Location, entries []*models.TranscriptEntry) string {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, loc)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, w.Write(entry))
+	}
+	require.NoError(t, w.Flush())
+	return buf.String()
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xml", &bytes.Buffer{}, time.UTC)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestJSONL(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	out := write(t, FormatJSONL, loc, entries())
+	lines := bytes.Split(bytes.TrimSpace([]byte(out)), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var first map[string]string
+	require.NoError(t, json.Unmarshal(lines[0], &first))
+	assert.Equal(t, "m1", first["message_id"])
+	assert.Equal(t, "Ada Lovelace", first["author_name"])
+	assert.Equal(t, "2024-03-02T08:30:00+09:00", first["created_at"])
+	assert.Contains(t, string(lines[0]), "hello <world>")
+}
+
+func TestCSV(t *testing.T) {
+	out := write(t, FormatCSV, time.UTC, entries())
+
+	records, err := csv.NewReader(bytes.NewBufferString(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"m2", "2024-03-01 23:31:05 +0000", "u2", "Alan Turing", "line one\nline two, \"quoted\""}, records[2])
+
+	assert.Equal(t, "message_id,created_at,author_id,author_name,content\n", write(t, FormatCSV, time.UTC, nil))
+}
+
+func TestText(t *testing.T) {
+	loc := time.FixedZone("", -5*60*60)
+
+	out := write(t, FormatText, loc, entries())
+	assert.Equal(t,
+		"[2024-03-01 18:30:00 -0500] Ada Lovelace: hello <world>\n"+
+			"[2024-03-01 18:31:05 -0500] Alan Turing: line one\n"+
+			"    line two, \"quoted\"\n",
+		out,
+	)
+}
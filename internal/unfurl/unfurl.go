@@ -0,0 +1,184 @@
+// Package unfurl builds link previews from the OpenGraph metadata of web
+// pages.
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/princecee/go_chat/internal/models"
+	"golang.org/x/net/html"
+)
+
+var (
+	ErrUnsupportedURL = errors.New("unsupported url")
+	ErrNoPreview      = errors.New("page has no preview metadata")
+)
+
+const (
+	// maxBodySize bounds how much of a page is read looking for metadata,
+	// which lives in the head and so near the start of the document.
+	maxBodySize = 512 << 10
+
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+)
+
+// Fetcher performs the HTTP requests made while unfurling. *http.Client
+// satisfies it; NewHTTPFetcher returns one that refuses to connect to
+// private networks.
+type Fetcher interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Unfurler struct {
+	fetcher Fetcher
+}
+
+func New(fetcher Fetcher) *Unfurler {
+	return &Unfurler{fetcher}
+}
+
+// Unfurl fetches rawURL and builds a preview from its OpenGraph metadata,
+// falling back to the page title and meta description.
+func (u *Unfurler) Unfurl(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", "go_chat-unfurler/1.0")
+
+	resp, err := u.fetcher.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unfurl %s: unexpected status %d", rawURL, resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNoPreview
+	}
+
+	// relative image URLs resolve against the page we were redirected to
+	base := target
+	if resp.Request != nil && resp.Request.URL != nil {
+		base = resp.Request.URL
+	}
+
+	meta := parseHead(io.LimitReader(resp.Body, maxBodySize))
+	preview := &models.LinkPreview{
+		URL:         rawURL,
+		Title:       truncate(firstOf(meta["og:title"], meta["twitter:title"], meta["title"]), maxTitleLength),
+		Description: truncate(firstOf(meta["og:description"], meta["twitter:description"], meta["description"]), maxDescriptionLength),
+		SiteName:    truncate(meta["og:site_name"], maxTitleLength),
+		ImageURL:    resolveImage(base, firstOf(meta["og:image"], meta["og:image:url"], meta["twitter:image"])),
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return nil, ErrNoPreview
+	}
+
+	return preview, nil
+}
+
+// parseHead collects the page title and the content of its meta tags,
+// keyed by their property or name, until the end of the document head.
+func parseHead(r io.Reader) map[string]string {
+	meta := map[string]string{}
+	z := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return meta
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag := z.Token()
+			switch tag.Data {
+			case "body":
+				return meta
+			case "title":
+				inTitle = true
+			case "meta":
+				var key, content string
+				for _, attr := range tag.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(attr.Val))
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				if key != "" && content != "" && meta[key] == "" {
+					meta[key] = content
+				}
+			}
+
+		case html.EndTagToken:
+			tag := z.Token()
+			switch tag.Data {
+			case "head":
+				return meta
+			case "title":
+				inTitle = false
+			}
+
+		case html.TextToken:
+			if inTitle && meta["title"] == "" {
+				meta["title"] = strings.TrimSpace(z.Token().Data)
+			}
+		}
+	}
+}
+
+func resolveImage(base *url.URL, raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	image := base.ResolveReference(ref)
+	if image.Scheme != "http" && image.Scheme != "https" {
+		return ""
+	}
+	return image.String()
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
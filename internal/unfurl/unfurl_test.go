@@ -0,0 +1,113 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<!doctype html><html><head>
+			<title>Fallback title</title>
+			<meta property="og:title" content="  Go   1.22 released ">
+			<meta property="og:description" content="Loop variables are now per-iteration.">
+			<meta property="og:image" content="/images/cover.png">
+			<meta property="og:site_name" content="The Go Blog">
+			</head><body><meta property="og:title" content="ignored"></body></html>`)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Just a title</title><meta name="description" content="Described"></head></html>`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/article", http.StatusFound)
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG"))
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head></head><body>hello</body></html>`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUnfurl(t *testing.T) {
+	server := newServer(t)
+	unfurler := New(server.Client())
+	ctx := context.Background()
+
+	preview, err := unfurler.Unfurl(ctx, server.URL+"/article")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/article", preview.URL)
+	assert.Equal(t, "Go 1.22 released", preview.Title)
+	assert.Equal(t, "Loop variables are now per-iteration.", preview.Description)
+	assert.Equal(t, server.URL+"/images/cover.png", preview.ImageURL)
+	assert.Equal(t, "The Go Blog", preview.SiteName)
+
+	preview, err = unfurler.Unfurl(ctx, server.URL+"/plain")
+	require.NoError(t, err)
+	assert.Equal(t, "Just a title", preview.Title)
+	assert.Equal(t, "Described", preview.Description)
+	assert.Empty(t, preview.ImageURL)
+
+	preview, err = unfurler.Unfurl(ctx, server.URL+"/redirect")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/redirect", preview.URL)
+	assert.Equal(t, server.URL+"/images/cover.png", preview.ImageURL)
+
+	_, err = unfurler.Unfurl(ctx, server.URL+"/image")
+	assert.ErrorIs(t, err, ErrNoPreview)
+
+	_, err = unfurler.Unfurl(ctx, server.URL+"/empty")
+	assert.ErrorIs(t, err, ErrNoPreview)
+
+	_, err = unfurler.Unfurl(ctx, server.URL+"/missing")
+	assert.Error(t, err)
+
+	_, err = unfurler.Unfurl(ctx, "file:///etc/passwd")
+	assert.ErrorIs(t, err, ErrUnsupportedURL)
+}
+
+func TestHTTPFetcherBlocksPrivateAddresses(t *testing.T) {
+	server := newServer(t)
+	unfurler := New(NewHTTPFetcher())
+
+	_, err := unfurler.Unfurl(context.Background(), server.URL+"/article")
+	assert.True(t, errors.Is(err, ErrPrivateAddress), "got %v", err)
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.216.34":       true,
+		"2606:4700::1111":     true,
+		"127.0.0.1":           false,
+		"10.1.2.3":            false,
+		"172.16.0.1":          false,
+		"192.168.1.1":         false,
+		"169.254.169.254":     false,
+		"100.64.0.1":          false,
+		"0.0.0.0":             false,
+		"::1":                 false,
+		"fd00::1":             false,
+		"fe80::1":             false,
+		"::ffff:127.0.0.1":    false,
+		"::ffff:93.184.216.3": true,
+	} {
+		assert.Equal(t, public, IsPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"message.created"}`)
+
+	sig := Sign("secret", 1700000000, body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, sig)
+	assert.True(t, Verify("secret", 1700000000, body, sig))
+	assert.False(t, Verify("other", 1700000000, body, sig))
+	assert.False(t, Verify("secret", 1700000001, body, sig))
+	assert.False(t, Verify("secret", 1700000000, []byte(`{}`), sig))
+}
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	d := &Delivery{
+		ID:        "d1",
+		EventType: "message.created",
+		URL:       srv.URL,
+		Secret:    "secret",
+		Payload:   []byte(`{"type":"message.created"}`),
+	}
+	now := time.Unix(1700000000, 0)
+
+	code, err := Send(context.Background(), srv.Client(), d, now)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	assert.Equal(t, d.Payload, gotBody)
+	assert.Equal(t, "d1", got.Header.Get(HeaderDelivery))
+	assert.Equal(t, "message.created", got.Header.Get(HeaderEvent))
+	ts, err := strconv.ParseInt(got.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify("secret", ts, gotBody, got.Header.Get(HeaderSignature)))
+
+	status = http.StatusBadGateway
+	code, err = Send(context.Background(), srv.Client(), d, now)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, code)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 8*time.Minute, Backoff(5))
+	assert.Equal(t, 6*time.Hour, Backoff(20))
+}
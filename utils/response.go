@@ -14,6 +14,8 @@ type ResponseMeta struct {
 	Page        int    `json:"page,omitempty"`
 	TotalPages  int    `json:"total_pages,omitempty"`
 	AccessToken string `json:"access_token,omitempty"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
 }
 
 type Response[T any] struct {
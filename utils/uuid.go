@@ -14,6 +14,27 @@ func StringToUUID(id string) uuid.UUID {
 	return uuid
 }
 
+// StringPtrToUUID converts an optional id into a nullable UUID, where a nil
+// id becomes NULL.
+func StringPtrToUUID(id *string) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+
+	var _uuid pgtype.UUID
+	_uuid.Scan(*id)
+	return _uuid
+}
+
+// NullUUIDToString converts a nullable UUID into a string, where NULL
+// becomes the empty string.
+func NullUUIDToString(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return uuid.UUID(id.Bytes).String()
+}
+
 func StringToText(str string) pgtype.Text {
 	return pgtype.Text{String: str, Valid: true}
 }